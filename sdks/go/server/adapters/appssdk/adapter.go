@@ -4,16 +4,18 @@ package appssdk
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
 	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/adapters"
 )
 
-// Configuration errors
+// Configuration errors, shared with the mcpuiserver shim config validation
 var (
-	ErrInvalidTimeout        = errors.New("timeout must be positive")
-	ErrInvalidIntentHandling = errors.New("intentHandling must be 'prompt' or 'ignore'")
+	ErrInvalidTimeout        = mcpuiserver.ErrInvalidTimeout
+	ErrInvalidIntentHandling = mcpuiserver.ErrInvalidIntentHandling
+	ErrInvalidHostOrigin     = mcpuiserver.ErrInvalidHostOrigin
+	ErrInvalidLogLevel       = mcpuiserver.ErrInvalidLogLevel
 )
 
 // Config holds the configuration for the Apps SDK adapter.
@@ -28,6 +30,18 @@ type Config struct {
 
 	// HostOrigin for MessageEvents (default: empty, uses window.location.origin)
 	HostOrigin string
+
+	// LogLevel is the minimum console log level of the runtime
+	// (default: empty, logs everything)
+	LogLevel mcpuiserver.LogLevel
+
+	// IntentRoutes maps intent names (or "*") to declarative routes.
+	// Unrouted intents fall back to IntentHandling.
+	IntentRoutes map[string]mcpuiserver.IntentRoute
+
+	// DataProviders maps ui-request-data request types to tool calls.
+	// Requests without a provider are answered with an error.
+	DataProviders map[string]mcpuiserver.DataProvider
 }
 
 // Validate validates the adapter configuration.
@@ -38,7 +52,18 @@ func (c *Config) Validate() error {
 	if c.IntentHandling != "prompt" && c.IntentHandling != "ignore" {
 		return ErrInvalidIntentHandling
 	}
-	return nil
+	if c.HostOrigin != "" {
+		if err := mcpuiserver.ValidateHostOrigin(c.HostOrigin); err != nil {
+			return err
+		}
+	}
+	if c.LogLevel != "" && !c.LogLevel.IsValid() {
+		return ErrInvalidLogLevel
+	}
+	if err := mcpuiserver.ValidateIntentRoutes(c.IntentRoutes); err != nil {
+		return err
+	}
+	return mcpuiserver.ValidateDataProviders(c.DataProviders)
 }
 
 // Option is a functional option for configuring the Apps SDK adapter.
@@ -66,6 +91,54 @@ func WithHostOrigin(origin string) Option {
 	}
 }
 
+// WithLogLevel sets the minimum level the runtime writes to the console.
+// Use mcpuiserver.LogLevelWarn or LogLevelSilent to keep production consoles quiet.
+func WithLogLevel(level mcpuiserver.LogLevel) Option {
+	return func(c *Config) {
+		c.LogLevel = level
+	}
+}
+
+// WithIntentRoute routes the named intent (or mcpuiserver.IntentRouteWildcard
+// for any other intent) to a tool call, templated prompt, link or drop.
+func WithIntentRoute(intent string, route mcpuiserver.IntentRoute) Option {
+	return func(c *Config) {
+		if c.IntentRoutes == nil {
+			c.IntentRoutes = make(map[string]mcpuiserver.IntentRoute)
+		}
+		c.IntentRoutes[intent] = route
+	}
+}
+
+// WithIntentRoutes adds every route in the given routing table.
+func WithIntentRoutes(routes map[string]mcpuiserver.IntentRoute) Option {
+	return func(c *Config) {
+		for intent, route := range routes {
+			WithIntentRoute(intent, route)(c)
+		}
+	}
+}
+
+// WithDataProvider answers ui-request-data messages with the given request type
+// by calling the provider's tool through the host.
+func WithDataProvider(requestType string, provider mcpuiserver.DataProvider) Option {
+	return func(c *Config) {
+		if c.DataProviders == nil {
+			c.DataProviders = make(map[string]mcpuiserver.DataProvider)
+		}
+		c.DataProviders[requestType] = provider
+	}
+}
+
+// WithDataProviders adds every provider in the given table.
+func WithDataProviders(providers map[string]mcpuiserver.DataProvider) Option {
+	return func(c *Config) {
+		for requestType, provider := range providers {
+			WithDataProvider(requestType, provider)(c)
+		}
+	}
+}
+
 // Adapter implements the Apps SDK adapter for MCP-UI widgets.
 type Adapter struct {
 	config *Config
@@ -76,6 +149,7 @@ type Adapter struct {
 //   - Timeout: 30000ms
 //   - IntentHandling: "prompt"
 //   - HostOrigin: "" (uses window.location.origin)
+//   - LogLevel: "" (logs everything)
 func NewAdapter(opts ...Option) (*Adapter, error) {
 	config := &Config{
 		Timeout:        30000,
@@ -94,6 +168,47 @@ func NewAdapter(opts ...Option) (*Adapter, error) {
 	return &Adapter{config: config}, nil
 }
 
+// NewAdapterFromConfig creates an Apps SDK adapter from a negotiated protocol
+// configuration, typically the result of mcpuiserver.ParseProtocolConfig.
+// The Config map is decoded with mcpuiserver.DecodeAppsSdkShimConfig; keys that
+// are absent or zero keep the NewAdapter defaults. The config Type must be
+// ProtocolTypeAppsSDK or empty.
+func NewAdapterFromConfig(config *mcpuiserver.ProtocolConfig) (*Adapter, error) {
+	if config == nil {
+		return nil, adapters.ErrNilProtocolConfig
+	}
+	if config.Type != "" && config.Type != mcpuiserver.ProtocolTypeAppsSDK {
+		return nil, fmt.Errorf("%w: got %q, want %q", adapters.ErrProtocolMismatch, config.Type, mcpuiserver.ProtocolTypeAppsSDK)
+	}
+
+	parsed, err := mcpuiserver.DecodeAppsSdkShimConfig(config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if parsed.Timeout != 0 {
+		opts = append(opts, WithTimeout(parsed.Timeout))
+	}
+	if parsed.IntentHandling != "" {
+		opts = append(opts, WithIntentHandling(parsed.IntentHandling))
+	}
+	if parsed.HostOrigin != "" {
+		opts = append(opts, WithHostOrigin(parsed.HostOrigin))
+	}
+	if parsed.LogLevel != "" {
+		opts = append(opts, WithLogLevel(parsed.LogLevel))
+	}
+	if parsed.IntentRoutes != nil {
+		opts = append(opts, WithIntentRoutes(parsed.IntentRoutes))
+	}
+	if parsed.DataProviders != nil {
+		opts = append(opts, WithDataProviders(parsed.DataProviders))
+	}
+
+	return NewAdapter(opts...)
+}
+
 // GetScript returns the complete <script> tag containing the adapter runtime
 // with the injected configuration.
 func (a *Adapter) GetScript() string {
@@ -130,5 +245,17 @@ func (a *Adapter) serializableConfig() map[string]interface{} {
 		config["hostOrigin"] = a.config.HostOrigin
 	}
 
+	if a.config.LogLevel != "" {
+		config["logLevel"] = a.config.LogLevel
+	}
+
+	if len(a.config.IntentRoutes) > 0 {
+		config["intentRoutes"] = a.config.IntentRoutes
+	}
+
+	if len(a.config.DataProviders) > 0 {
+		config["dataProviders"] = a.config.DataProviders
+	}
+
 	return config
 }
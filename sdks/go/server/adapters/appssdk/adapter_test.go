@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
 	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/adapters"
 	"github.com/stretchr/testify/assert"
 )
@@ -81,6 +82,12 @@ func TestNewAdapter(t *testing.T) {
 			wantErr: true,
 			errType: ErrInvalidIntentHandling,
 		},
+		{
+			name:    "invalid host origin",
+			opts:    []Option{WithHostOrigin("https://example.com/app")},
+			wantErr: true,
+			errType: ErrInvalidHostOrigin,
+		},
 	}
 
 	for _, tt := range tests {
@@ -298,6 +305,26 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errType: ErrInvalidIntentHandling,
 		},
+		{
+			name: "invalid host origin - path",
+			config: Config{
+				Timeout:        30000,
+				IntentHandling: "prompt",
+				HostOrigin:     "https://example.com/path/to/resource",
+			},
+			wantErr: true,
+			errType: ErrInvalidHostOrigin,
+		},
+		{
+			name: "invalid host origin - relative",
+			config: Config{
+				Timeout:        30000,
+				IntentHandling: "prompt",
+				HostOrigin:     "example.com",
+			},
+			wantErr: true,
+			errType: ErrInvalidHostOrigin,
+		},
 	}
 
 	for _, tt := range tests {
@@ -328,6 +355,28 @@ func TestAdapter_ScriptContainsAdapterClass(t *testing.T) {
 	assert.Contains(t, script, "function uninstallAdapter")
 }
 
+func TestAdapter_ScriptSendsTeardown(t *testing.T) {
+	adapter, err := NewAdapter()
+	assert.NoError(t, err)
+
+	script := adapter.GetScript()
+
+	assert.Contains(t, script, `type: "ui-lifecycle-teardown"`)
+	assert.Contains(t, script, `window.addEventListener("pagehide"`)
+	assert.Contains(t, script, `this.sendTeardown("adapter-uninstalled")`)
+}
+
+func TestAdapter_ScriptUsesProtocolErrors(t *testing.T) {
+	adapter, err := NewAdapter()
+	assert.NoError(t, err)
+
+	script := adapter.GetScript()
+
+	assert.Contains(t, script, `payload: { messageId, error: toProtocolError(error) }`)
+	assert.Regexp(t, `createProtocolError\(\s*"unsupported",\s*"Tool calling is not supported in this environment"\s*\)`, script)
+	assert.Contains(t, script, `createProtocolError("timeout", "Request timed out after "`)
+}
+
 func TestAdapter_SpecialCharactersInConfig(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -339,7 +388,7 @@ func TestAdapter_SpecialCharactersInConfig(t *testing.T) {
 		},
 		{
 			name:       "URL with special characters",
-			hostOrigin: "https://example.com/path/to/resource",
+			hostOrigin: `https://example.com?q="quoted"&tag=<script>`,
 		},
 		{
 			name:       "URL with fragment",
@@ -375,9 +424,213 @@ func TestAdapter_EmptyOptionalFields(t *testing.T) {
 	config := adapter.serializableConfig()
 	_, hasHostOrigin := config["hostOrigin"]
 	assert.False(t, hasHostOrigin, "empty hostOrigin should not be in config")
+	_, hasLogLevel := config["logLevel"]
+	assert.False(t, hasLogLevel, "empty logLevel should not be in config")
 
 	// But script should still be valid
 	assert.Contains(t, script, "<script>")
 	assert.Contains(t, script, "</script>")
 	assert.Contains(t, script, "MCPUIAppsSdkAdapter")
 }
+
+func TestNewAdapterFromConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *mcpuiserver.ProtocolConfig
+		wantTimeout int
+		wantIntent  string
+		wantOrigin  string
+		wantErr     error
+	}{
+		{
+			name:        "no client config uses defaults",
+			config:      &mcpuiserver.ProtocolConfig{Type: mcpuiserver.ProtocolTypeAppsSDK},
+			wantTimeout: 30000,
+			wantIntent:  "prompt",
+		},
+		{
+			name: "JSON decoded numbers",
+			config: &mcpuiserver.ProtocolConfig{
+				Type: mcpuiserver.ProtocolTypeAppsSDK,
+				Config: map[string]interface{}{
+					"timeout":        float64(10000),
+					"intentHandling": "ignore",
+					"hostOrigin":     "https://chatgpt.com",
+				},
+			},
+			wantTimeout: 10000,
+			wantIntent:  "ignore",
+			wantOrigin:  "https://chatgpt.com",
+		},
+		{
+			name: "json.Number timeout",
+			config: &mcpuiserver.ProtocolConfig{
+				Config: map[string]interface{}{"timeout": json.Number("5000")},
+			},
+			wantTimeout: 5000,
+			wantIntent:  "prompt",
+		},
+		{
+			name:    "nil config",
+			config:  nil,
+			wantErr: adapters.ErrNilProtocolConfig,
+		},
+		{
+			name:    "mismatched protocol",
+			config:  &mcpuiserver.ProtocolConfig{Type: mcpuiserver.ProtocolTypeMCPApps},
+			wantErr: adapters.ErrProtocolMismatch,
+		},
+		{
+			name: "fractional timeout",
+			config: &mcpuiserver.ProtocolConfig{
+				Config: map[string]interface{}{"timeout": 2.5},
+			},
+			wantErr: mcpuiserver.ErrInvalidProtocolConfig,
+		},
+		{
+			name: "invalid intent handling",
+			config: &mcpuiserver.ProtocolConfig{
+				Config: map[string]interface{}{"intentHandling": "execute"},
+			},
+			wantErr: mcpuiserver.ErrInvalidIntentHandling,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, err := NewAdapterFromConfig(tt.config)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, adapter)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTimeout, adapter.config.Timeout)
+			assert.Equal(t, tt.wantIntent, adapter.config.IntentHandling)
+			assert.Equal(t, tt.wantOrigin, adapter.config.HostOrigin)
+
+			var _ adapters.Adapter = adapter
+		})
+	}
+}
+
+func TestNewAdapterFromConfig_ParsedInitialize(t *testing.T) {
+	var params map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"metadata": {
+			"mcp-ui-protocol": "appssdk",
+			"mcp-ui-protocol-config": {"timeout": 12000, "intentHandling": "ignore"}
+		}
+	}`), &params)
+	assert.NoError(t, err)
+
+	adapter, err := NewAdapterFromConfig(mcpuiserver.ParseProtocolConfig(params))
+	assert.NoError(t, err)
+	assert.Contains(t, adapter.GetScript(), `"timeout":12000`)
+	assert.Contains(t, adapter.GetScript(), `"intentHandling":"ignore"`)
+}
+
+func TestWithLogLevel(t *testing.T) {
+	adapter, err := NewAdapter(WithLogLevel(mcpuiserver.LogLevelWarn))
+	assert.NoError(t, err)
+	assert.Equal(t, mcpuiserver.LogLevelWarn, adapter.serializableConfig()["logLevel"])
+	assert.Contains(t, adapter.GetScript(), `"logLevel":"warn"`)
+	assert.Contains(t, adapter.GetScript(), "createLeveledLogger")
+
+	adapter, err = NewAdapter(WithLogLevel("loud"))
+	assert.ErrorIs(t, err, ErrInvalidLogLevel)
+	assert.Nil(t, adapter)
+
+	adapter, err = NewAdapterFromConfig(&mcpuiserver.ProtocolConfig{
+		Config: map[string]interface{}{"logLevel": "silent"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mcpuiserver.LogLevelSilent, adapter.config.LogLevel)
+}
+
+func TestWithIntentRoute(t *testing.T) {
+	adapter, err := NewAdapter(
+		WithIntentRoute("showSettings", mcpuiserver.IntentRoute{
+			Action:       mcpuiserver.IntentActionTool,
+			ToolName:     "openSettings",
+			ParamMapping: map[string]string{"section": "tab"},
+		}),
+		WithIntentRoutes(map[string]mcpuiserver.IntentRoute{
+			"openDocs":                      {Action: mcpuiserver.IntentActionLink, Template: "https://docs.example.com/{{page}}"},
+			mcpuiserver.IntentRouteWildcard: {Action: mcpuiserver.IntentActionDrop},
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, adapter.config.IntentRoutes, 3)
+
+	script := adapter.GetScript()
+	assert.Contains(t, script, `"intentRoutes":{`)
+	assert.Contains(t, script, `"showSettings":{"action":"tool","toolName":"openSettings","paramMapping":{"section":"tab"}}`)
+	assert.Contains(t, script, `"*":{"action":"drop"}`)
+	assert.Contains(t, script, "resolveIntentRoute")
+	assert.Contains(t, script, "handleRoutedIntent")
+
+	adapter, err = NewAdapter(WithIntentRoute("showSettings", mcpuiserver.IntentRoute{Action: mcpuiserver.IntentActionTool}))
+	assert.ErrorIs(t, err, mcpuiserver.ErrInvalidIntentRoute)
+	assert.Nil(t, adapter)
+}
+
+func TestNewAdapterFromConfig_IntentRoutes(t *testing.T) {
+	adapter, err := NewAdapterFromConfig(&mcpuiserver.ProtocolConfig{
+		Config: map[string]interface{}{
+			"intentRoutes": map[string]interface{}{
+				"showSettings": map[string]interface{}{"action": "prompt", "template": "Open {{tab}} settings"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mcpuiserver.IntentRoute{
+		Action:   mcpuiserver.IntentActionPrompt,
+		Template: "Open {{tab}} settings",
+	}, adapter.config.IntentRoutes["showSettings"])
+}
+
+func TestWithDataProvider(t *testing.T) {
+	adapter, err := NewAdapter(
+		WithDataProvider("userStats", mcpuiserver.DataProvider{
+			ToolName:     "getUserStats",
+			ParamMapping: map[string]string{"userId": "id"},
+		}),
+		WithDataProviders(map[string]mcpuiserver.DataProvider{
+			"orders": {ToolName: "listOrders"},
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, adapter.config.DataProviders, 2)
+
+	script := adapter.GetScript()
+	assert.Contains(t, script, `"dataProviders":{`)
+	assert.Contains(t, script, `"userStats":{"toolName":"getUserStats","paramMapping":{"userId":"id"}}`)
+	assert.Contains(t, script, `"orders":{"toolName":"listOrders"}`)
+	assert.Contains(t, script, "No data provider registered for request type")
+
+	adapter, err = NewAdapter(WithDataProvider("userStats", mcpuiserver.DataProvider{}))
+	assert.ErrorIs(t, err, mcpuiserver.ErrInvalidDataProvider)
+	assert.Nil(t, adapter)
+}
+
+func TestNewAdapterFromConfig_DataProviders(t *testing.T) {
+	adapter, err := NewAdapterFromConfig(&mcpuiserver.ProtocolConfig{
+		Config: map[string]interface{}{
+			"dataProviders": map[string]interface{}{
+				"userStats": map[string]interface{}{"toolName": "getUserStats"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mcpuiserver.DataProvider{ToolName: "getUserStats"}, adapter.config.DataProviders["userStats"])
+}
+
+func TestConfigErrors_ShareRootSentinels(t *testing.T) {
+	assert.ErrorIs(t, ErrInvalidTimeout, mcpuiserver.ErrInvalidTimeout)
+	assert.ErrorIs(t, ErrInvalidIntentHandling, mcpuiserver.ErrInvalidIntentHandling)
+	assert.ErrorIs(t, ErrInvalidHostOrigin, mcpuiserver.ErrInvalidHostOrigin)
+	assert.ErrorIs(t, ErrInvalidLogLevel, mcpuiserver.ErrInvalidLogLevel)
+}
@@ -4,21 +4,42 @@ package mcpapps
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
 	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/adapters"
 )
 
-// Configuration errors
+// Configuration errors, shared with the mcpuiserver shim config validation
 var (
-	ErrInvalidTimeout = errors.New("timeout must be positive")
+	ErrInvalidTimeout  = mcpuiserver.ErrInvalidTimeout
+	ErrInvalidAppInfo  = mcpuiserver.ErrInvalidAppInfo
+	ErrInvalidLogLevel = mcpuiserver.ErrInvalidLogLevel
 )
 
 // Config holds the configuration for the MCP Apps adapter.
 type Config struct {
 	// Timeout in milliseconds for async operations (default: 30000)
 	Timeout int
+
+	// AppInfo identifies the app to the host in ui/initialize
+	// (default: nil, the runtime sends "mcp-ui-adapter" 1.0.0)
+	AppInfo *mcpuiserver.AppInfo
+
+	// AppCapabilities are announced to the host in ui/initialize (default: empty)
+	AppCapabilities map[string]interface{}
+
+	// LogLevel is the minimum console log level of the runtime
+	// (default: empty, logs everything)
+	LogLevel mcpuiserver.LogLevel
+
+	// IntentRoutes maps intent names (or "*") to declarative routes.
+	// Unrouted intents are sent to the host as a ui/message prompt.
+	IntentRoutes map[string]mcpuiserver.IntentRoute
+
+	// DataProviders maps ui-request-data request types to tool calls.
+	// Requests without a provider are answered with an error.
+	DataProviders map[string]mcpuiserver.DataProvider
 }
 
 // Validate validates the adapter configuration.
@@ -26,7 +47,16 @@ func (c *Config) Validate() error {
 	if c.Timeout <= 0 {
 		return ErrInvalidTimeout
 	}
-	return nil
+	if c.AppInfo != nil && (c.AppInfo.Name == "" || c.AppInfo.Version == "") {
+		return ErrInvalidAppInfo
+	}
+	if c.LogLevel != "" && !c.LogLevel.IsValid() {
+		return ErrInvalidLogLevel
+	}
+	if err := mcpuiserver.ValidateIntentRoutes(c.IntentRoutes); err != nil {
+		return err
+	}
+	return mcpuiserver.ValidateDataProviders(c.DataProviders)
 }
 
 // Option is a functional option for configuring the MCP Apps adapter.
@@ -39,6 +69,72 @@ func WithTimeout(milliseconds int) Option {
 	}
 }
 
+// WithAppInfo sets the app name and version sent to the host in ui/initialize,
+// so hosts can identify the widget instead of seeing the generic adapter name.
+func WithAppInfo(name, version string) Option {
+	return func(c *Config) {
+		c.AppInfo = &mcpuiserver.AppInfo{Name: name, Version: version}
+	}
+}
+
+// WithAppCapabilities sets the capabilities announced to the host in ui/initialize.
+func WithAppCapabilities(capabilities map[string]interface{}) Option {
+	return func(c *Config) {
+		c.AppCapabilities = make(map[string]interface{}, len(capabilities))
+		for k, v := range capabilities {
+			c.AppCapabilities[k] = v
+		}
+	}
+}
+
+// WithLogLevel sets the minimum level the runtime writes to the console.
+// Use mcpuiserver.LogLevelWarn or LogLevelSilent to keep production consoles quiet.
+func WithLogLevel(level mcpuiserver.LogLevel) Option {
+	return func(c *Config) {
+		c.LogLevel = level
+	}
+}
+
+// WithIntentRoute routes the named intent (or mcpuiserver.IntentRouteWildcard
+// for any other intent) to a tool call, templated prompt, link or drop.
+func WithIntentRoute(intent string, route mcpuiserver.IntentRoute) Option {
+	return func(c *Config) {
+		if c.IntentRoutes == nil {
+			c.IntentRoutes = make(map[string]mcpuiserver.IntentRoute)
+		}
+		c.IntentRoutes[intent] = route
+	}
+}
+
+// WithIntentRoutes adds every route in the given routing table.
+func WithIntentRoutes(routes map[string]mcpuiserver.IntentRoute) Option {
+	return func(c *Config) {
+		for intent, route := range routes {
+			WithIntentRoute(intent, route)(c)
+		}
+	}
+}
+
+// WithDataProvider answers ui-request-data messages with the given request type
+// by calling the provider's tool through the host.
+func WithDataProvider(requestType string, provider mcpuiserver.DataProvider) Option {
+	return func(c *Config) {
+		if c.DataProviders == nil {
+			c.DataProviders = make(map[string]mcpuiserver.DataProvider)
+		}
+		c.DataProviders[requestType] = provider
+	}
+}
+
+// WithDataProviders adds every provider in the given table.
+func WithDataProviders(providers map[string]mcpuiserver.DataProvider) Option {
+	return func(c *Config) {
+		for requestType, provider := range providers {
+			WithDataProvider(requestType, provider)(c)
+		}
+	}
+}
+
 // Adapter implements the MCP Apps adapter for MCP-UI widgets.
 type Adapter struct {
 	config *Config
@@ -47,6 +143,9 @@ type Adapter struct {
 // NewAdapter creates a new MCP Apps adapter with the provided options.
 // Default configuration:
 //   - Timeout: 30000ms
+//   - AppInfo: nil (runtime sends "mcp-ui-adapter" 1.0.0)
+//   - AppCapabilities: empty
+//   - LogLevel: "" (logs everything)
 func NewAdapter(opts ...Option) (*Adapter, error) {
 	config := &Config{
 		Timeout: 30000,
@@ -63,6 +162,47 @@ func NewAdapter(opts ...Option) (*Adapter, error) {
 	return &Adapter{config: config}, nil
 }
 
+// NewAdapterFromConfig creates an MCP Apps adapter from a negotiated protocol
+// configuration, typically the result of mcpuiserver.ParseProtocolConfig.
+// The Config map is decoded with mcpuiserver.DecodeMcpAppsShimConfig; keys that
+// are absent or zero keep the NewAdapter defaults. The config Type must be
+// ProtocolTypeMCPApps or empty.
+func NewAdapterFromConfig(config *mcpuiserver.ProtocolConfig) (*Adapter, error) {
+	if config == nil {
+		return nil, adapters.ErrNilProtocolConfig
+	}
+	if config.Type != "" && config.Type != mcpuiserver.ProtocolTypeMCPApps {
+		return nil, fmt.Errorf("%w: got %q, want %q", adapters.ErrProtocolMismatch, config.Type, mcpuiserver.ProtocolTypeMCPApps)
+	}
+
+	parsed, err := mcpuiserver.DecodeMcpAppsShimConfig(config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if parsed.Timeout != 0 {
+		opts = append(opts, WithTimeout(parsed.Timeout))
+	}
+	if parsed.AppInfo != nil {
+		opts = append(opts, WithAppInfo(parsed.AppInfo.Name, parsed.AppInfo.Version))
+	}
+	if parsed.AppCapabilities != nil {
+		opts = append(opts, WithAppCapabilities(parsed.AppCapabilities))
+	}
+	if parsed.LogLevel != "" {
+		opts = append(opts, WithLogLevel(parsed.LogLevel))
+	}
+	if parsed.IntentRoutes != nil {
+		opts = append(opts, WithIntentRoutes(parsed.IntentRoutes))
+	}
+	if parsed.DataProviders != nil {
+		opts = append(opts, WithDataProviders(parsed.DataProviders))
+	}
+
+	return NewAdapter(opts...)
+}
+
 // GetScript returns the complete <script> tag containing the adapter runtime
 // with the injected configuration.
 func (a *Adapter) GetScript() string {
@@ -88,7 +228,29 @@ func (a *Adapter) GetType() string {
 
 // serializableConfig returns a config map with only serializable fields.
 func (a *Adapter) serializableConfig() map[string]interface{} {
-	return map[string]interface{}{
+	config := map[string]interface{}{
 		"timeout": a.config.Timeout,
 	}
+
+	if a.config.AppInfo != nil {
+		config["appInfo"] = a.config.AppInfo
+	}
+
+	if a.config.AppCapabilities != nil {
+		config["appCapabilities"] = a.config.AppCapabilities
+	}
+
+	if a.config.LogLevel != "" {
+		config["logLevel"] = a.config.LogLevel
+	}
+
+	if len(a.config.IntentRoutes) > 0 {
+		config["intentRoutes"] = a.config.IntentRoutes
+	}
+
+	if len(a.config.DataProviders) > 0 {
+		config["dataProviders"] = a.config.DataProviders
+	}
+
+	return config
 }
@@ -2,9 +2,12 @@ package mcpapps
 
 import (
 	"encoding/json"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
 	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/adapters"
 	"github.com/stretchr/testify/assert"
 )
@@ -261,6 +264,41 @@ func TestAdapter_ScriptContainsProtocolMethods(t *testing.T) {
 	}
 }
 
+func TestAdapter_ScriptForwardsLifecycleMessages(t *testing.T) {
+	adapter, err := NewAdapter()
+	assert.NoError(t, err)
+
+	script := adapter.GetScript()
+
+	for _, msgType := range []mcpuiserver.ProtocolMessageType{
+		mcpuiserver.MessageTypeToolCancelled,
+		mcpuiserver.MessageTypeTeardown,
+		mcpuiserver.MessageTypeToolInputPartial,
+	} {
+		assert.Contains(t, script, `type: "`+string(msgType)+`"`, "script should dispatch %q", msgType)
+	}
+}
+
+func TestAdapter_ScriptUsesProtocolErrorCodes(t *testing.T) {
+	adapter, err := NewAdapter()
+	assert.NoError(t, err)
+
+	script := adapter.GetScript()
+
+	// The runtime's code table must agree with the Go JSON-RPC mapping
+	for _, code := range []mcpuiserver.ProtocolErrorCode{
+		mcpuiserver.ErrorCodeUnsupported,
+		mcpuiserver.ErrorCodeTimeout,
+		mcpuiserver.ErrorCodeDenied,
+		mcpuiserver.ErrorCodeInvalidParams,
+		mcpuiserver.ErrorCodeHostError,
+	} {
+		assert.Regexp(t, `"?`+regexp.QuoteMeta(string(code))+`"?: `+strconv.Itoa(code.JSONRPCCode())+`[,\s}]`, script, "code table should map %q", code)
+	}
+	assert.Contains(t, script, "protocolErrorFromJsonRpc(data.error)")
+	assert.NotContains(t, script, `error: "Timeout"`)
+}
+
 func TestAdapter_SerializableConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -281,6 +319,20 @@ func TestAdapter_SerializableConfig(t *testing.T) {
 				"timeout": 5000,
 			},
 		},
+		{
+			name: "identity, capabilities and log level",
+			opts: []Option{
+				WithAppInfo("weather-widget", "2.3.0"),
+				WithAppCapabilities(map[string]interface{}{"tools": map[string]interface{}{}}),
+				WithLogLevel(mcpuiserver.LogLevelWarn),
+			},
+			wantCfg: map[string]interface{}{
+				"timeout":         30000,
+				"appInfo":         &mcpuiserver.AppInfo{Name: "weather-widget", Version: "2.3.0"},
+				"appCapabilities": map[string]interface{}{"tools": map[string]interface{}{}},
+				"logLevel":        mcpuiserver.LogLevelWarn,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -316,3 +368,236 @@ func TestAdapter_JSONRPCConstants(t *testing.T) {
 	assert.Contains(t, script, `jsonrpc: "2.0"`)
 	assert.Contains(t, script, "METHODS")
 }
+
+func TestNewAdapterFromConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *mcpuiserver.ProtocolConfig
+		wantTimeout int
+		wantErr     error
+	}{
+		{
+			name:        "no client config uses defaults",
+			config:      &mcpuiserver.ProtocolConfig{Type: mcpuiserver.ProtocolTypeMCPApps},
+			wantTimeout: 30000,
+		},
+		{
+			name: "JSON decoded timeout",
+			config: &mcpuiserver.ProtocolConfig{
+				Type:   mcpuiserver.ProtocolTypeMCPApps,
+				Config: map[string]interface{}{"timeout": float64(8000)},
+			},
+			wantTimeout: 8000,
+		},
+		{
+			name:    "nil config",
+			config:  nil,
+			wantErr: adapters.ErrNilProtocolConfig,
+		},
+		{
+			name:    "mismatched protocol",
+			config:  &mcpuiserver.ProtocolConfig{Type: mcpuiserver.ProtocolTypeAppsSDK},
+			wantErr: adapters.ErrProtocolMismatch,
+		},
+		{
+			name: "key from another protocol",
+			config: &mcpuiserver.ProtocolConfig{
+				Config: map[string]interface{}{"intentHandling": "prompt"},
+			},
+			wantErr: mcpuiserver.ErrInvalidProtocolConfig,
+		},
+		{
+			name: "string timeout",
+			config: &mcpuiserver.ProtocolConfig{
+				Config: map[string]interface{}{"timeout": "8000"},
+			},
+			wantErr: mcpuiserver.ErrInvalidProtocolConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, err := NewAdapterFromConfig(tt.config)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, adapter)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTimeout, adapter.config.Timeout)
+
+			var _ adapters.Adapter = adapter
+		})
+	}
+}
+
+func TestNewAdapter_IdentityAndLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{
+			name: "valid app info",
+			opts: []Option{WithAppInfo("weather-widget", "2.3.0")},
+		},
+		{
+			name:    "app info without version",
+			opts:    []Option{WithAppInfo("weather-widget", "")},
+			wantErr: ErrInvalidAppInfo,
+		},
+		{
+			name:    "app info without name",
+			opts:    []Option{WithAppInfo("", "1.0.0")},
+			wantErr: ErrInvalidAppInfo,
+		},
+		{
+			name: "silent log level",
+			opts: []Option{WithLogLevel(mcpuiserver.LogLevelSilent)},
+		},
+		{
+			name:    "unknown log level",
+			opts:    []Option{WithLogLevel("verbose")},
+			wantErr: ErrInvalidLogLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, err := NewAdapter(tt.opts...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, adapter)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, adapter)
+			}
+		})
+	}
+}
+
+func TestAdapter_ScriptUsesConfiguredIdentity(t *testing.T) {
+	adapter, err := NewAdapter(
+		WithAppInfo("weather-widget", "2.3.0"),
+		WithLogLevel(mcpuiserver.LogLevelError),
+	)
+	assert.NoError(t, err)
+
+	script := adapter.GetScript()
+
+	assert.Contains(t, script, `"appInfo":{"name":"weather-widget","version":"2.3.0"}`)
+	assert.Contains(t, script, `"logLevel":"error"`)
+	assert.Contains(t, script, "appInfo: this.config.appInfo")
+	assert.Contains(t, script, "appCapabilities: this.config.appCapabilities")
+	assert.Contains(t, script, "createLeveledLogger")
+}
+
+func TestWithAppCapabilities_CopiesMap(t *testing.T) {
+	capabilities := map[string]interface{}{"tools": true}
+	adapter, err := NewAdapter(WithAppCapabilities(capabilities))
+	assert.NoError(t, err)
+
+	capabilities["resources"] = true
+	assert.NotContains(t, adapter.config.AppCapabilities, "resources")
+}
+
+func TestNewAdapterFromConfig_IdentityAndLogging(t *testing.T) {
+	adapter, err := NewAdapterFromConfig(&mcpuiserver.ProtocolConfig{
+		Type: mcpuiserver.ProtocolTypeMCPApps,
+		Config: map[string]interface{}{
+			"appInfo":         map[string]interface{}{"name": "weather-widget", "version": "2.3.0"},
+			"appCapabilities": map[string]interface{}{"tools": map[string]interface{}{}},
+			"logLevel":        "silent",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &mcpuiserver.AppInfo{Name: "weather-widget", Version: "2.3.0"}, adapter.config.AppInfo)
+	assert.Equal(t, map[string]interface{}{"tools": map[string]interface{}{}}, adapter.config.AppCapabilities)
+	assert.Equal(t, mcpuiserver.LogLevelSilent, adapter.config.LogLevel)
+}
+
+func TestWithIntentRoute(t *testing.T) {
+	adapter, err := NewAdapter(
+		WithIntentRoute("showSettings", mcpuiserver.IntentRoute{
+			Action:       mcpuiserver.IntentActionTool,
+			ToolName:     "openSettings",
+			ParamMapping: map[string]string{"section": "tab"},
+		}),
+		WithIntentRoutes(map[string]mcpuiserver.IntentRoute{
+			"openDocs":                      {Action: mcpuiserver.IntentActionLink, Template: "https://docs.example.com/{{page}}"},
+			mcpuiserver.IntentRouteWildcard: {Action: mcpuiserver.IntentActionDrop},
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, adapter.config.IntentRoutes, 3)
+
+	script := adapter.GetScript()
+	assert.Contains(t, script, `"intentRoutes":{`)
+	assert.Contains(t, script, `"showSettings":{"action":"tool","toolName":"openSettings","paramMapping":{"section":"tab"}}`)
+	assert.Contains(t, script, `"*":{"action":"drop"}`)
+	assert.Contains(t, script, "resolveIntentRoute")
+	assert.Contains(t, script, "handleRoutedIntent")
+
+	adapter, err = NewAdapter(WithIntentRoute("showSettings", mcpuiserver.IntentRoute{Action: mcpuiserver.IntentActionTool}))
+	assert.ErrorIs(t, err, mcpuiserver.ErrInvalidIntentRoute)
+	assert.Nil(t, adapter)
+}
+
+func TestNewAdapterFromConfig_IntentRoutes(t *testing.T) {
+	adapter, err := NewAdapterFromConfig(&mcpuiserver.ProtocolConfig{
+		Config: map[string]interface{}{
+			"intentRoutes": map[string]interface{}{
+				"showSettings": map[string]interface{}{"action": "prompt", "template": "Open {{tab}} settings"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mcpuiserver.IntentRoute{
+		Action:   mcpuiserver.IntentActionPrompt,
+		Template: "Open {{tab}} settings",
+	}, adapter.config.IntentRoutes["showSettings"])
+}
+
+func TestWithDataProvider(t *testing.T) {
+	adapter, err := NewAdapter(
+		WithDataProvider("userStats", mcpuiserver.DataProvider{
+			ToolName:     "getUserStats",
+			ParamMapping: map[string]string{"userId": "id"},
+		}),
+		WithDataProviders(map[string]mcpuiserver.DataProvider{
+			"orders": {ToolName: "listOrders"},
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, adapter.config.DataProviders, 2)
+
+	script := adapter.GetScript()
+	assert.Contains(t, script, `"dataProviders":{`)
+	assert.Contains(t, script, `"userStats":{"toolName":"getUserStats","paramMapping":{"userId":"id"}}`)
+	assert.Contains(t, script, `"orders":{"toolName":"listOrders"}`)
+	assert.Contains(t, script, "No data provider registered for request type")
+
+	adapter, err = NewAdapter(WithDataProvider("userStats", mcpuiserver.DataProvider{}))
+	assert.ErrorIs(t, err, mcpuiserver.ErrInvalidDataProvider)
+	assert.Nil(t, adapter)
+}
+
+func TestNewAdapterFromConfig_DataProviders(t *testing.T) {
+	adapter, err := NewAdapterFromConfig(&mcpuiserver.ProtocolConfig{
+		Config: map[string]interface{}{
+			"dataProviders": map[string]interface{}{
+				"userStats": map[string]interface{}{"toolName": "getUserStats"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mcpuiserver.DataProvider{ToolName: "getUserStats"}, adapter.config.DataProviders["userStats"])
+}
+
+func TestConfigErrors_ShareRootSentinels(t *testing.T) {
+	assert.ErrorIs(t, ErrInvalidTimeout, mcpuiserver.ErrInvalidTimeout)
+	assert.ErrorIs(t, ErrInvalidAppInfo, mcpuiserver.ErrInvalidAppInfo)
+	assert.ErrorIs(t, ErrInvalidLogLevel, mcpuiserver.ErrInvalidLogLevel)
+}
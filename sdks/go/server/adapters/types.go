@@ -2,6 +2,14 @@
 // to work in different host environments (Apps SDK, MCP Apps, etc.)
 package adapters
 
+import "errors"
+
+// Errors returned when constructing an adapter from a negotiated protocol config
+var (
+	ErrNilProtocolConfig = errors.New("protocol config must not be nil")
+	ErrProtocolMismatch  = errors.New("protocol config type does not match adapter")
+)
+
 // AdapterType identifies which adapter implementation to use.
 type AdapterType string
 
@@ -0,0 +1,148 @@
+package mcpuiserver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidAnnotations is returned by CreateUIResource for annotations
+// outside the ranges MCP allows, and reported by ValidateUIResource
+var ErrInvalidAnnotations = errors.New("invalid annotations")
+
+// Role is an MCP role, used as an annotation audience
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Annotations are the MCP annotations of an embedded UI resource, telling the
+// client who the resource is for and how important it is. Zero values are
+// omitted, so a priority of 0 is indistinguishable from no priority; to send
+// an explicit 0, set "priority": 0 in the WithEmbeddedResourceProps
+// annotations map, which WithAnnotations values without a priority keep.
+type Annotations struct {
+	// Audience lists who the resource is meant for. A widget for RoleUser
+	// only is shown to the user without its HTML being added to the model
+	// context, on clients that honor the audience.
+	Audience []Role `json:"audience,omitempty"`
+	// Priority ranges from 0 (optional) to 1 (effectively required). Zero
+	// is omitted.
+	Priority float64 `json:"priority,omitzero"`
+	// LastModified is when the resource content last changed
+	LastModified time.Time `json:"lastModified,omitzero"`
+}
+
+// validate checks the priority range and the audience roles
+func (a *Annotations) validate() error {
+	var errs []error
+	if !(a.Priority >= 0 && a.Priority <= 1) {
+		errs = append(errs, fmt.Errorf("%w: priority %v is not between 0 and 1", ErrInvalidAnnotations, a.Priority))
+	}
+	for _, role := range a.Audience {
+		if role != RoleUser && role != RoleAssistant {
+			errs = append(errs, fmt.Errorf("%w: audience role %q is not %q or %q",
+				ErrInvalidAnnotations, role, RoleUser, RoleAssistant))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// toMap returns the annotations in their JSON form, as UIResource holds them
+func (a *Annotations) toMap() map[string]interface{} {
+	var m map[string]interface{}
+	decodeMetadata(a, &m) // Annotations always encode
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// WithAnnotations sets the MCP annotations of the embedded resource. They are
+// validated by CreateUIResource, which returns ErrInvalidAnnotations for a
+// priority outside 0..1 or an unknown role, and take precedence over the same
+// keys in WithEmbeddedResourceProps annotations.
+// Example: WithAnnotations(Annotations{Audience: []Role{RoleUser}, Priority: 0.8})
+func WithAnnotations(annotations Annotations) Option {
+	annotations.Audience = append([]Role(nil), annotations.Audience...)
+	return func(o *CreateUIResourceOptions) {
+		copied := annotations
+		o.Annotations = &copied
+	}
+}
+
+// WithAudience sets the audience annotation, keeping any other annotations
+// from WithAnnotations. WithAudience(RoleUser) marks a widget for display
+// only, so clients that honor the audience do not spend model context on it.
+// Example: WithAudience(RoleUser)
+func WithAudience(roles ...Role) Option {
+	roles = append([]Role(nil), roles...)
+	return func(o *CreateUIResourceOptions) {
+		if o.Annotations == nil {
+			o.Annotations = &Annotations{}
+		}
+		o.Annotations.Audience = roles
+	}
+}
+
+// annotationsFromMap decodes annotations in their JSON form, as UIResource
+// and EmbeddedResourceProps hold them. Keys other than the MCP annotations
+// are ignored.
+func annotationsFromMap(annotations map[string]interface{}) (Annotations, error) {
+	var typed Annotations
+	if !decodeMetadata(annotations, &typed) {
+		return Annotations{}, fmt.Errorf("%w: want audience roles, a numeric priority and an RFC 3339 lastModified, got %v",
+			ErrInvalidAnnotations, annotations)
+	}
+	return typed, nil
+}
+
+// embeddedAnnotations returns the annotations for the embedded resource:
+// the EmbeddedResourceProps annotations, which may be a map or an
+// Annotations value, with the WithAnnotations values set over them. Typed
+// annotations are validated; maps are passed through as given and other
+// types are ignored, both checked only by WithStrict.
+func embeddedAnnotations(options *CreateUIResourceOptions) (map[string]interface{}, error) {
+	var annotations map[string]interface{}
+	switch props := options.EmbeddedResourceProps["annotations"].(type) {
+	case map[string]interface{}:
+		annotations = cloneMap(props)
+	case Annotations:
+		if err := props.validate(); err != nil {
+			return nil, err
+		}
+		annotations = props.toMap()
+	case *Annotations:
+		if props != nil {
+			if err := props.validate(); err != nil {
+				return nil, err
+			}
+			annotations = props.toMap()
+		}
+	}
+
+	if options.Annotations != nil {
+		if err := options.Annotations.validate(); err != nil {
+			return nil, err
+		}
+		annotations = mergeMap(annotations, options.Annotations.toMap())
+	}
+	return annotations, nil
+}
+
+// validateAnnotations checks the annotations of a UIResource
+func validateAnnotations(annotations map[string]interface{}, fail func(string, error)) {
+	if annotations == nil {
+		return
+	}
+	typed, err := annotationsFromMap(annotations)
+	if err != nil {
+		fail("annotations", err)
+		return
+	}
+	if err := typed.validate(); err != nil {
+		fail("annotations", err)
+	}
+}
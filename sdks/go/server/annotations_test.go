@@ -0,0 +1,175 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAnnotations(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	modified := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]interface{}
+	}{
+		{
+			name: "all fields",
+			opts: []Option{WithAnnotations(Annotations{Audience: []Role{RoleUser, RoleAssistant}, Priority: 0.8, LastModified: modified})},
+			want: map[string]interface{}{
+				"audience":     []interface{}{"user", "assistant"},
+				"priority":     0.8,
+				"lastModified": "2026-03-01T12:30:00Z",
+			},
+		},
+		{
+			name: "user only",
+			opts: []Option{WithAudience(RoleUser)},
+			want: map[string]interface{}{"audience": []interface{}{"user"}},
+		},
+		{
+			name: "audience keeps other annotations",
+			opts: []Option{WithAnnotations(Annotations{Audience: []Role{RoleAssistant}, Priority: 1}), WithAudience(RoleUser)},
+			want: map[string]interface{}{"audience": []interface{}{"user"}, "priority": 1.0},
+		},
+		{
+			name: "zero values are omitted",
+			opts: []Option{WithAnnotations(Annotations{})},
+			want: nil,
+		},
+		{
+			name: "overrides embedded resource props keys",
+			opts: []Option{
+				WithEmbeddedResourceProps(map[string]interface{}{"annotations": map[string]interface{}{"priority": 0.1, "custom": "x"}}),
+				WithAnnotations(Annotations{Priority: 0.9}),
+			},
+			want: map[string]interface{}{"priority": 0.9, "custom": "x"},
+		},
+		{
+			name: "typed embedded resource props",
+			opts: []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": &Annotations{Priority: 0.5}})},
+			want: map[string]interface{}{"priority": 0.5},
+		},
+		{
+			name: "explicit zero priority from embedded resource props",
+			opts: []Option{
+				WithEmbeddedResourceProps(map[string]interface{}{"annotations": map[string]interface{}{"priority": 0}}),
+				WithAudience(RoleUser),
+			},
+			want: map[string]interface{}{"priority": 0, "audience": []interface{}{"user"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource("ui://widget", html, EncodingText, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resource.Annotations)
+			assert.NoError(t, ValidateUIResource(resource))
+		})
+	}
+}
+
+func TestWithAnnotations_Invalid(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		errContains string
+	}{
+		{name: "priority above 1", opts: []Option{WithAnnotations(Annotations{Priority: 1.5})}, errContains: "priority 1.5"},
+		{name: "negative priority", opts: []Option{WithAnnotations(Annotations{Priority: -0.1})}, errContains: "priority -0.1"},
+		{name: "unknown role", opts: []Option{WithAudience(RoleUser, "model")}, errContains: `role "model"`},
+		{name: "empty role", opts: []Option{WithAudience("")}, errContains: `role ""`},
+		{
+			name:        "typed embedded resource props",
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": Annotations{Priority: 2}})},
+			errContains: "priority 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource("ui://widget", html, EncodingText, tt.opts...)
+			assert.ErrorIs(t, err, ErrInvalidAnnotations)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Nil(t, resource)
+		})
+	}
+}
+
+func TestWithAnnotations_DoesNotAliasAudience(t *testing.T) {
+	audience := []Role{RoleUser}
+	opt := WithAnnotations(Annotations{Audience: audience})
+	audience[0] = RoleAssistant
+
+	resource, err := CreateUIResource("ui://widget", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText, opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"user"}, resource.Annotations["audience"])
+}
+
+func TestValidateUIResource_Annotations(t *testing.T) {
+	resource, err := CreateUIResource("ui://widget", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		annotations map[string]interface{}
+		wantErr     bool
+	}{
+		{name: "none", annotations: nil},
+		{name: "valid", annotations: map[string]interface{}{"audience": []string{"user"}, "priority": 0, "lastModified": "2026-03-01T12:30:00+02:00"}},
+		{name: "priority out of range", annotations: map[string]interface{}{"priority": 3}, wantErr: true},
+		{name: "priority not a number", annotations: map[string]interface{}{"priority": "high"}, wantErr: true},
+		{name: "unknown role", annotations: map[string]interface{}{"audience": []string{"model"}}, wantErr: true},
+		{name: "invalid lastModified", annotations: map[string]interface{}{"lastModified": "yesterday"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUIResource(resource.WithAnnotations(tt.annotations))
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidAnnotations)
+			var fieldErr *FieldError
+			if assert.ErrorAs(t, err, &fieldErr) {
+				assert.Equal(t, "annotations", fieldErr.Field)
+			}
+		})
+	}
+}
+
+func TestAnnotations_JSONSchema(t *testing.T) {
+	schema := JSONSchemas()[SchemaNameUIResource]
+	resource, err := CreateUIResource("ui://widget", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText,
+		WithAnnotations(Annotations{Audience: []Role{RoleUser}, Priority: 0.3, LastModified: time.Now()}))
+	assert.NoError(t, err)
+	assert.NoError(t, validateJSONSchema(t, schema, resource))
+
+	annotations := schema["properties"].(JSONSchema)["annotations"]
+	data, err := json.Marshal(annotations)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"audience": {"type": "array", "items": {"type": "string", "enum": ["user", "assistant"]}},
+			"priority": {"type": "number", "minimum": 0, "maximum": 1},
+			"lastModified": {"type": "string", "format": "date-time"}
+		}
+	}`, string(data))
+
+	for _, invalid := range []map[string]interface{}{
+		{"priority": 1.5},
+		{"audience": []string{"model"}},
+	} {
+		assert.Error(t, validateJSONSchema(t, schema, resource.WithAnnotations(invalid)), invalid)
+	}
+}
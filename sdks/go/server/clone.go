@@ -0,0 +1,142 @@
+package mcpuiserver
+
+import "reflect"
+
+// Clone returns a deep copy of the resource. Its metadata and annotation maps,
+// and any maps and slices nested in them, are copied; content strings and
+// other values, such as pointers and structs, are shared. Clone of nil is nil.
+//
+// Resources returned by CreateUIResource and the other constructors never
+// alias maps passed in options, but a resource shared between calls, such as
+// one from a ResourceCache with WithSharedResources, must be cloned before it
+// is modified. WithMeta, WithAnnotations and WithURI clone for you.
+func (r *UIResource) Clone() *UIResource {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Resource.Meta = cloneMap(r.Resource.Meta)
+	c.Annotations = cloneMap(r.Annotations)
+	c.Meta = cloneMap(r.Meta)
+	return &c
+}
+
+// WithMeta returns a copy of the resource with the keys of meta set in its
+// resource _meta, the map WithUIMetadata and WithMetadata write to. The
+// resource is not modified.
+// Example: resource.WithMeta(map[string]interface{}{"requestId": id})
+func (r *UIResource) WithMeta(meta map[string]interface{}) *UIResource {
+	c := r.Clone()
+	c.Resource.Meta = mergeMap(c.Resource.Meta, meta)
+	return c
+}
+
+// WithAnnotations returns a copy of the resource with the keys of annotations
+// set in its MCP annotations. The resource is not modified. The keys are not
+// validated; use ValidateUIResource to check the result.
+// Example: resource.WithAnnotations(map[string]interface{}{"priority": 0.2})
+func (r *UIResource) WithAnnotations(annotations map[string]interface{}) *UIResource {
+	c := r.Clone()
+	c.Annotations = mergeMap(c.Annotations, annotations)
+	return c
+}
+
+// WithURI returns a copy of the resource with a new URI. A ResourceURIMetaKey
+// entry in the resource _meta is updated to match. The URI is not validated;
+// use ValidateUIResource to check the result. The resource is not modified.
+func (r *UIResource) WithURI(uri string) *UIResource {
+	c := r.Clone()
+	c.Resource.URI = uri
+	if _, ok := c.Resource.Meta[ResourceURIMetaKey]; ok {
+		c.Resource.Meta[ResourceURIMetaKey] = uri
+	}
+	return c
+}
+
+// mergeMap sets copies of the entries of src in dst, creating dst if needed,
+// and returns dst
+func mergeMap(dst, src map[string]interface{}) map[string]interface{} {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for k, v := range src {
+		dst[k] = cloneValue(v)
+	}
+	return dst
+}
+
+// cloneMap returns a deep copy of m, or nil when m is nil
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = cloneValue(v)
+	}
+	return c
+}
+
+// cloneValue returns v with any maps and slices in it copied, recursively
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, string, bool, float64, int:
+		return v
+	case map[string]interface{}:
+		return cloneMap(v)
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = cloneValue(e)
+		}
+		return c
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map && rv.Kind() != reflect.Slice {
+		return v
+	}
+	return cloneReflect(rv).Interface()
+}
+
+// cloneReflect is cloneValue for maps and slices of any type
+func cloneReflect(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), cloneReflect(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		if kind := v.Type().Elem().Kind(); kind != reflect.Map && kind != reflect.Slice && kind != reflect.Interface {
+			reflect.Copy(c, v)
+			return c
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneReflect(v.Index(i)))
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneReflect(v.Elem()))
+		return c
+	}
+	return v
+}
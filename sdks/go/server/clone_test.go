@@ -0,0 +1,158 @@
+package mcpuiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCloneTestResource(t *testing.T) *UIResource {
+	resource, err := CreateUIResource("ui://widget",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText,
+		WithUIMetadata(map[string]interface{}{"preferred-frame-size": []string{"800px", "600px"}}),
+		WithMetadata(map[string]interface{}{
+			ResourceURIMetaKey: "ui://widget",
+			"nested":           map[string]interface{}{"list": []interface{}{map[string]interface{}{"a": 1}}},
+		}),
+		WithEmbeddedResourceProps(map[string]interface{}{
+			"annotations": map[string]interface{}{"audience": []interface{}{"user"}},
+			"_meta":       map[string]interface{}{"tool": map[string]interface{}{"name": "chart"}},
+		}))
+	assert.NoError(t, err)
+	return resource
+}
+
+func TestUIResource_Clone(t *testing.T) {
+	resource := newCloneTestResource(t)
+	clone := resource.Clone()
+	assert.Equal(t, resource, clone)
+
+	clone.Resource.Text = "changed"
+	clone.Resource.Meta["new"] = true
+	clone.Resource.Meta[UIMetadataPrefix+"preferred-frame-size"].([]string)[0] = "1px"
+	clone.Resource.Meta["nested"].(map[string]interface{})["list"].([]interface{})[0].(map[string]interface{})["a"] = 2
+	clone.Annotations["audience"].([]interface{})[0] = "assistant"
+	clone.Meta["tool"].(map[string]interface{})["name"] = "changed"
+
+	assert.Equal(t, newCloneTestResource(t), resource, "original is unchanged")
+	assert.Nil(t, (*UIResource)(nil).Clone())
+}
+
+func TestUIResource_WithMeta(t *testing.T) {
+	resource := newCloneTestResource(t)
+	value := map[string]interface{}{"id": "1"}
+
+	updated := resource.WithMeta(map[string]interface{}{"requestId": value, "tool": "x"})
+	value["id"] = "2"
+
+	assert.Equal(t, map[string]interface{}{"id": "1"}, updated.Resource.Meta["requestId"])
+	assert.Equal(t, "x", updated.Resource.Meta["tool"])
+	assert.Equal(t, []string{"800px", "600px"}, updated.Resource.Meta[UIMetadataPrefix+"preferred-frame-size"])
+	assert.Equal(t, newCloneTestResource(t), resource, "original is unchanged")
+
+	bare, err := CreateUIResource("ui://bare", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"k": "v"}, bare.WithMeta(map[string]interface{}{"k": "v"}).Resource.Meta)
+	assert.Nil(t, bare.Resource.Meta)
+}
+
+func TestUIResource_WithAnnotations(t *testing.T) {
+	resource := newCloneTestResource(t)
+
+	updated := resource.WithAnnotations(map[string]interface{}{"priority": 0.2})
+
+	assert.Equal(t, map[string]interface{}{"audience": []interface{}{"user"}, "priority": 0.2}, updated.Annotations)
+	assert.NoError(t, ValidateUIResource(updated))
+	assert.ErrorIs(t, ValidateUIResource(resource.WithAnnotations(map[string]interface{}{"priority": 2})), ErrInvalidAnnotations,
+		"keys are not validated when set")
+	assert.Equal(t, newCloneTestResource(t), resource, "original is unchanged")
+}
+
+func TestUIResource_WithURI(t *testing.T) {
+	resource := newCloneTestResource(t)
+
+	updated := resource.WithURI("ui://other")
+
+	assert.Equal(t, "ui://other", updated.Resource.URI)
+	assert.Equal(t, "ui://other", updated.Resource.Meta[ResourceURIMetaKey])
+	assert.NoError(t, ValidateUIResource(updated))
+	assert.Equal(t, newCloneTestResource(t), resource, "original is unchanged")
+}
+
+func TestCreateUIResource_DoesNotAliasOptions(t *testing.T) {
+	uiMetadata := map[string]interface{}{"preferred-frame-size": []string{"800px", "600px"}}
+	metadata := map[string]interface{}{"nested": map[string]interface{}{"a": 1}}
+	resourceProps := map[string]interface{}{"_meta": map[string]interface{}{"list": []interface{}{1}}}
+	annotations := map[string]interface{}{"priority": 0.5}
+	outerMeta := map[string]interface{}{"tool": "chart"}
+	transformerMeta := map[string]interface{}{"build": map[string]interface{}{"version": "1"}}
+
+	create := func() *UIResource {
+		resource, err := CreateUIResource("ui://widget",
+			&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText,
+			WithUIMetadata(uiMetadata),
+			WithMetadata(metadata),
+			WithResourceProps(resourceProps),
+			WithEmbeddedResourceProps(map[string]interface{}{"annotations": annotations, "_meta": outerMeta}),
+			WithTransformers(AddMetadata(transformerMeta)))
+		assert.NoError(t, err)
+		return resource
+	}
+	resource := create()
+	want := create()
+
+	uiMetadata["preferred-frame-size"].([]string)[0] = "1px"
+	metadata["nested"].(map[string]interface{})["a"] = 2
+	resourceProps["_meta"].(map[string]interface{})["list"].([]interface{})[0] = 2
+	annotations["priority"] = 1.0
+	outerMeta["tool"] = "changed"
+	transformerMeta["build"].(map[string]interface{})["version"] = "2"
+	assert.Equal(t, want, resource, "changing the caller's maps does not change the resource")
+
+	resource.Resource.Meta[UIMetadataPrefix+"preferred-frame-size"].([]string)[1] = "1px"
+	resource.Resource.Meta["nested"].(map[string]interface{})["a"] = 3
+	resource.Annotations["priority"] = 0.0
+	resource.Meta["tool"] = "other"
+	assert.Equal(t, []string{"1px", "600px"}, uiMetadata["preferred-frame-size"], "changing the resource does not change the caller's maps")
+	assert.Equal(t, 2, metadata["nested"].(map[string]interface{})["a"])
+	assert.Equal(t, 1.0, annotations["priority"])
+	assert.Equal(t, "changed", outerMeta["tool"])
+}
+
+func TestUIResourceStream_UIResourceDoesNotAlias(t *testing.T) {
+	stream, err := NewUIResourceStream("ui://widget",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText,
+		WithMetadata(map[string]interface{}{"build": "1"}))
+	assert.NoError(t, err)
+
+	stream.UIResource().Resource.Meta["build"] = "changed"
+	assert.Equal(t, "1", stream.UIResource().Resource.Meta["build"])
+}
+
+func TestCloneValue(t *testing.T) {
+	type custom map[string][]int
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "nil", value: nil},
+		{name: "scalar", value: 1.5},
+		{name: "typed map", value: map[string]string{"a": "b"}},
+		{name: "typed slice", value: []int{1, 2}},
+		{name: "named map of slices", value: custom{"a": {1, 2}}},
+		{name: "slice of maps", value: []map[string]interface{}{{"a": []interface{}{1}}}},
+		{name: "nil map", value: map[string]interface{}(nil)},
+		{name: "nil slice", value: []string(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.value, cloneValue(tt.value))
+		})
+	}
+
+	original := custom{"a": {1, 2}}
+	clone := cloneValue(original).(custom)
+	clone["a"][0] = 9
+	assert.Equal(t, 1, original["a"][0])
+}
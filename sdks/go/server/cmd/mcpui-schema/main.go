@@ -0,0 +1,52 @@
+// Command mcpui-schema writes the JSON Schemas of the MCP-UI wire formats,
+// generated from the Go SDK types.
+//
+// Usage:
+//
+//	go run github.com/MCP-UI-Org/mcp-ui/sdks/go/server/cmd/mcpui-schema -out schemas
+//
+// With -out, each schema is written to <dir>/<name>.schema.json, for example
+// schemas/ui-size-change.schema.json or schemas/UIResource.schema.json.
+// Without it, a single JSON object mapping names to schemas is printed to
+// stdout.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+)
+
+func main() {
+	out := flag.String("out", "", "directory to write one <name>.schema.json file per schema to")
+	flag.Parse()
+
+	schemas := mcpuiserver.JSONSchemas()
+	if *out == "" {
+		data, err := json.MarshalIndent(schemas, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	for name, schema := range schemas {
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		path := filepath.Join(*out, name+".schema.json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package mcpuiserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Content hash errors
+var (
+	// ErrContentHashMismatch is reported by ValidateUIResource when the
+	// recorded content hash does not match the content
+	ErrContentHashMismatch = errors.New("content hash does not match the content")
+	// ErrInvalidURIVersionStyle is returned by CreateUIResource when
+	// WithVersionedURI is given a style other than the URIVersion constants
+	ErrInvalidURIVersionStyle = errors.New("URI version style must be 'suffix' or 'query'")
+)
+
+// ContentHashMetaKey is the _meta key holding the content hash recorded by
+// WithContentHash
+const ContentHashMetaKey = UIMetadataPrefix + UIMetadataKeyContentHash
+
+// URIVersionLength is the number of hex digits of the content hash used in
+// versioned URIs
+const URIVersionLength = 16
+
+// URIVersionStyle selects how WithVersionedURI adds the content hash to the
+// resource URI
+type URIVersionStyle string
+
+const (
+	// URIVersionSuffix appends the hash to the path: ui://name@<hash>
+	URIVersionSuffix URIVersionStyle = "suffix"
+	// URIVersionQuery adds the hash as a query parameter: ui://name?v=<hash>
+	URIVersionQuery URIVersionStyle = "query"
+)
+
+// validate reports whether the style is one of the URIVersion constants. The
+// empty style, which leaves the URI unversioned, is valid.
+func (s URIVersionStyle) validate() error {
+	switch s {
+	case "", URIVersionSuffix, URIVersionQuery:
+		return nil
+	default:
+		return fmt.Errorf("%w: got %q", ErrInvalidURIVersionStyle, s)
+	}
+}
+
+// uriVersionParam is the query parameter used by URIVersionQuery
+const uriVersionParam = "v"
+
+// WithContentHash records the SHA-256 hash of the final decoded content,
+// after protocol adapters and transformers, in _meta under
+// ContentHashMetaKey. The hash does not depend on the encoding, so text and
+// blob resources with the same content share it.
+func WithContentHash() Option {
+	return func(o *CreateUIResourceOptions) {
+		o.ContentHash = true
+	}
+}
+
+// WithVersionedURI records the content hash like WithContentHash and adds its
+// first URIVersionLength hex digits to the resource URI, so hosts that cache
+// templates by URI load the new content after a deploy.
+// Example: WithVersionedURI(URIVersionSuffix) turns ui://chart into
+// ui://chart@3f2a9c0d1b7e4a55
+// CreateUIResource returns ErrInvalidURIVersionStyle for any other style.
+func WithVersionedURI(style URIVersionStyle) Option {
+	return func(o *CreateUIResourceOptions) {
+		o.ContentHash = true
+		o.URIVersion = style
+	}
+}
+
+// hashContent returns the hex SHA-256 hash of the content
+func hashContent(content contentParts) string {
+	h := sha256.New()
+	_ = content.writeTo(h) // hash.Hash never fails
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// applyContentHash records the hash of content in meta, which must not be
+// nil, and returns uri versioned in style, or uri unchanged when style is
+// empty. A ResourceURIMetaKey entry naming uri is updated to the versioned
+// URI.
+func applyContentHash(meta map[string]interface{}, uri string, content contentParts, style URIVersionStyle) string {
+	hash := hashContent(content)
+	meta[ContentHashMetaKey] = hash
+	if style == "" {
+		return uri
+	}
+	versioned := VersionedURI(uri, hash, style)
+	if meta[ResourceURIMetaKey] == uri {
+		meta[ResourceURIMetaKey] = versioned
+	}
+	return versioned
+}
+
+// uriVersionStyle returns the style of a URI versioned by VersionedURI, or ""
+func uriVersionStyle(uri string) URIVersionStyle {
+	_, version := SplitVersionedURI(uri)
+	switch {
+	case version == "":
+		return ""
+	case strings.Contains(uri, "@"+version):
+		return URIVersionSuffix
+	default:
+		return URIVersionQuery
+	}
+}
+
+// VersionedURI adds the first URIVersionLength digits of hash to uri in the
+// given style, replacing any existing version. The suffix goes before any
+// query or fragment. An unknown style returns uri without a version.
+func VersionedURI(uri, hash string, style URIVersionStyle) string {
+	base, _ := SplitVersionedURI(uri)
+	version := hash
+	if len(version) > URIVersionLength {
+		version = version[:URIVersionLength]
+	}
+
+	path, rest := base, ""
+	if i := strings.IndexAny(base, "?#"); i >= 0 {
+		path, rest = base[:i], base[i:]
+	}
+
+	switch style {
+	case URIVersionSuffix:
+		return path + "@" + version + rest
+	case URIVersionQuery:
+		query, fragment := rest, ""
+		if i := strings.IndexByte(rest, '#'); i >= 0 {
+			query, fragment = rest[:i], rest[i:]
+		}
+		if query == "" {
+			query = "?"
+		} else {
+			query += "&"
+		}
+		return path + query + uriVersionParam + "=" + version + fragment
+	default:
+		return base
+	}
+}
+
+// SplitVersionedURI returns uri without the version added by VersionedURI,
+// and the version, or uri and "" when it is not versioned. Use it to look up
+// the widget a versioned resources/read request refers to.
+func SplitVersionedURI(uri string) (base, version string) {
+	path, rest := uri, ""
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		path, rest = uri[:i], uri[i:]
+	}
+
+	if i := strings.LastIndexByte(path, '@'); i >= 0 && isURIVersion(path[i+1:]) {
+		return path[:i] + rest, path[i+1:]
+	}
+
+	query, fragment := rest, ""
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		query, fragment = rest[:i], rest[i:]
+	}
+	if query == "" {
+		return uri, ""
+	}
+	params := strings.Split(query[1:], "&")
+	for i, param := range params {
+		value, ok := strings.CutPrefix(param, uriVersionParam+"=")
+		if !ok || !isURIVersion(value) {
+			continue
+		}
+		params = append(params[:i], params[i+1:]...)
+		if len(params) == 0 {
+			return path + fragment, value
+		}
+		return path + "?" + strings.Join(params, "&") + fragment, value
+	}
+	return uri, ""
+}
+
+// isURIVersion reports whether s has the form of a version added by
+// VersionedURI
+func isURIVersion(s string) bool {
+	return len(s) == URIVersionLength && isHex(s)
+}
+
+// isHex reports whether s is a non-empty lowercase hex string
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !('0' <= s[i] && s[i] <= '9' || 'a' <= s[i] && s[i] <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentHash returns the content hash recorded in the resource's _meta, or
+// computes it from the text or blob when none is recorded
+func (r *UIResource) ContentHash() (string, error) {
+	if r == nil {
+		return "", ErrNilResource
+	}
+	if hash, ok := r.Resource.Meta[ContentHashMetaKey].(string); ok && hash != "" {
+		return hash, nil
+	}
+	return r.computeContentHash()
+}
+
+// computeContentHash hashes the decoded text or blob
+func (r *UIResource) computeContentHash() (string, error) {
+	if r.Resource.Blob == "" {
+		return hashContent(contentParts{r.Resource.Text}), nil
+	}
+	h := sha256.New()
+	dec := base64.NewDecoder(base64.StdEncoding, strings.NewReader(r.Resource.Blob))
+	if _, err := io.Copy(h, dec); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ETag returns the content hash as a strong HTTP entity tag
+func (r *UIResource) ETag() (string, error) {
+	hash, err := r.ContentHash()
+	if err != nil {
+		return "", err
+	}
+	return `"` + hash + `"`, nil
+}
+
+// MatchesContentHash reports whether the resource content has the given hash,
+// for answering conditional reads. hash may be a bare hash, an ETag (quoted,
+// optionally weak), or the version from a versioned URI, which matches as a
+// prefix.
+func (r *UIResource) MatchesContentHash(hash string) bool {
+	hash = strings.Trim(strings.TrimPrefix(hash, "W/"), `"`)
+	current, err := r.ContentHash()
+	if err != nil || len(hash) < URIVersionLength {
+		return false
+	}
+	return strings.HasPrefix(current, hash)
+}
+
+// ContentChanged reports whether two resources have different content, for
+// sending resources/updated notifications only after real changes. A nil
+// resource differs from any non-nil one.
+func ContentChanged(previous, current *UIResource) bool {
+	if previous == nil || current == nil {
+		return previous != current
+	}
+	previousHash, err := previous.ContentHash()
+	if err != nil {
+		return true
+	}
+	currentHash, err := current.ContentHash()
+	if err != nil {
+		return true
+	}
+	return previousHash != currentHash
+}
@@ -0,0 +1,222 @@
+package mcpuiserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestWithContentHash(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Grüße</p>"}
+	want := sha256Hex("<p>Grüße</p>")
+
+	text, err := CreateUIResource("ui://test/widget", html, EncodingText, WithContentHash())
+	assert.NoError(t, err)
+	blob, err := CreateUIResource("ui://test/widget", html, EncodingBlob, WithContentHash())
+	assert.NoError(t, err)
+
+	assert.Equal(t, want, text.Resource.Meta[ContentHashMetaKey])
+	assert.Equal(t, want, blob.Resource.Meta[ContentHashMetaKey], "hash does not depend on the encoding")
+	assert.Equal(t, "ui://test/widget", text.Resource.URI)
+	assert.NoError(t, ValidateUIResource(text))
+	assert.NoError(t, ValidateUIResource(blob))
+}
+
+func TestWithContentHash_FinalContent(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	resource, err := CreateUIResource("ui://test/widget", html, EncodingText,
+		WithProtocol(ProtocolTypeMCPApps),
+		WithTransformers(InjectHead(`<meta name="build" content="2">`)),
+		WithContentHash())
+
+	assert.NoError(t, err)
+	assert.Equal(t, sha256Hex(resource.Resource.Text), resource.Resource.Meta[ContentHashMetaKey],
+		"hash covers the adapter and transformer output")
+}
+
+func TestWithVersionedURI(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	version := sha256Hex("<p>Hi</p>")[:URIVersionLength]
+
+	tests := []struct {
+		name    string
+		uri     string
+		style   URIVersionStyle
+		wantURI string
+	}{
+		{name: "suffix", uri: "ui://chart", style: URIVersionSuffix, wantURI: "ui://chart@" + version},
+		{name: "suffix before query", uri: "ui://chart?x=1", style: URIVersionSuffix, wantURI: "ui://chart@" + version + "?x=1"},
+		{name: "query", uri: "ui://chart", style: URIVersionQuery, wantURI: "ui://chart?v=" + version},
+		{name: "query with existing query", uri: "ui://chart?x=1#top", style: URIVersionQuery, wantURI: "ui://chart?x=1&v=" + version + "#top"},
+		{name: "replaces old version", uri: "ui://chart@0123456789abcdef", style: URIVersionSuffix, wantURI: "ui://chart@" + version},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource(tt.uri, html, EncodingText, WithVersionedURI(tt.style),
+				WithMetadata(map[string]interface{}{ResourceURIMetaKey: tt.uri}))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantURI, resource.Resource.URI)
+			assert.Equal(t, tt.wantURI, resource.Resource.Meta[ResourceURIMetaKey])
+			assert.NoError(t, ValidateUIResource(resource))
+
+			base, gotVersion := SplitVersionedURI(resource.Resource.URI)
+			assert.Equal(t, version, gotVersion)
+			b, _ := SplitVersionedURI(tt.uri)
+			assert.Equal(t, b, base)
+		})
+	}
+}
+
+func TestWithVersionedURI_UnknownStyle(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	for _, style := range []URIVersionStyle{"bogus", "Suffix"} {
+		t.Run(string(style), func(t *testing.T) {
+			resource, err := CreateUIResource("ui://chart", html, EncodingText, WithVersionedURI(style))
+
+			assert.ErrorIs(t, err, ErrInvalidURIVersionStyle)
+			assert.Contains(t, err.Error(), `"`+string(style)+`"`)
+			assert.Nil(t, resource)
+		})
+	}
+}
+
+func TestSplitVersionedURI(t *testing.T) {
+	tests := []struct {
+		uri         string
+		wantBase    string
+		wantVersion string
+	}{
+		{uri: "ui://chart", wantBase: "ui://chart"},
+		{uri: "ui://chart@0123456789abcdef", wantBase: "ui://chart", wantVersion: "0123456789abcdef"},
+		{uri: "ui://chart@latest", wantBase: "ui://chart@latest"},
+		{uri: "ui://chart@0123", wantBase: "ui://chart@0123"},
+		{uri: "ui://chart?v=0123456789abcdef", wantBase: "ui://chart", wantVersion: "0123456789abcdef"},
+		{uri: "ui://chart?a=1&v=0123456789abcdef&b=2#f", wantBase: "ui://chart?a=1&b=2#f", wantVersion: "0123456789abcdef"},
+		{uri: "ui://chart?v=2", wantBase: "ui://chart?v=2"},
+	}
+
+	for _, tt := range tests {
+		base, version := SplitVersionedURI(tt.uri)
+		assert.Equal(t, tt.wantBase, base, tt.uri)
+		assert.Equal(t, tt.wantVersion, version, tt.uri)
+	}
+}
+
+func TestUIResource_ContentHash(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	want := sha256Hex("<p>Hi</p>")
+
+	for _, encoding := range []Encoding{EncodingText, EncodingBlob} {
+		resource, err := CreateUIResource("ui://test", html, encoding)
+		assert.NoError(t, err)
+
+		hash, err := resource.ContentHash()
+		assert.NoError(t, err, "computed when not recorded")
+		assert.Equal(t, want, hash)
+
+		etag, err := resource.ETag()
+		assert.NoError(t, err)
+		assert.Equal(t, `"`+want+`"`, etag)
+	}
+
+	_, err := (&UIResource{Resource: ResourceContent{Blob: "not base64!"}}).ContentHash()
+	assert.ErrorIs(t, err, ErrInvalidBase64)
+
+	_, err = (*UIResource)(nil).ContentHash()
+	assert.ErrorIs(t, err, ErrNilResource)
+}
+
+func TestUIResource_MatchesContentHash(t *testing.T) {
+	resource, err := CreateUIResource("ui://test",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText, WithContentHash())
+	assert.NoError(t, err)
+	hash := sha256Hex("<p>Hi</p>")
+
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{name: "bare hash", hash: hash, want: true},
+		{name: "strong ETag", hash: `"` + hash + `"`, want: true},
+		{name: "weak ETag", hash: `W/"` + hash + `"`, want: true},
+		{name: "URI version", hash: hash[:URIVersionLength], want: true},
+		{name: "too short", hash: hash[:4], want: false},
+		{name: "other content", hash: sha256Hex("<p>Bye</p>"), want: false},
+		{name: "empty", hash: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resource.MatchesContentHash(tt.hash))
+		})
+	}
+}
+
+func TestContentChanged(t *testing.T) {
+	create := func(html string, encoding Encoding, opts ...Option) *UIResource {
+		resource, err := CreateUIResource("ui://test", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: html}, encoding, opts...)
+		assert.NoError(t, err)
+		return resource
+	}
+
+	tests := []struct {
+		name     string
+		previous *UIResource
+		current  *UIResource
+		want     bool
+	}{
+		{name: "same content", previous: create("<p>Hi</p>", EncodingText), current: create("<p>Hi</p>", EncodingText), want: false},
+		{name: "same content, other encoding", previous: create("<p>Hi</p>", EncodingText, WithContentHash()), current: create("<p>Hi</p>", EncodingBlob), want: false},
+		{name: "changed content", previous: create("<p>Hi</p>", EncodingText), current: create("<p>Bye</p>", EncodingText, WithContentHash()), want: true},
+		{name: "new resource", previous: nil, current: create("<p>Hi</p>", EncodingText), want: true},
+		{name: "both nil", previous: nil, current: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ContentChanged(tt.previous, tt.current))
+		})
+	}
+}
+
+func TestForProtocol_UpdatesContentHash(t *testing.T) {
+	generic, err := CreateUIResource("ui://chart",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingBlob, WithVersionedURI(URIVersionQuery))
+	assert.NoError(t, err)
+
+	converted, err := generic.ForProtocol(ProtocolTypeMCPApps)
+	assert.NoError(t, err)
+
+	want, err := CreateUIResource("ui://chart",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingBlob,
+		WithProtocol(ProtocolTypeMCPApps), WithVersionedURI(URIVersionQuery))
+	assert.NoError(t, err)
+	assert.Equal(t, want, converted)
+	assert.NotEqual(t, generic.Resource.URI, converted.Resource.URI)
+	assert.NoError(t, ValidateUIResource(converted))
+}
+
+func TestValidateUIResource_ContentHash(t *testing.T) {
+	resource, err := CreateUIResource("ui://test",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText, WithContentHash())
+	assert.NoError(t, err)
+
+	resource.Resource.Text = "<p>Changed</p>"
+	err = ValidateUIResource(resource)
+	assert.ErrorIs(t, err, ErrContentHashMismatch)
+
+	resource.Resource.Meta[ContentHashMetaKey] = "abc"
+	err = ValidateUIResource(resource)
+	assert.ErrorIs(t, err, ErrInvalidMetadataValue)
+}
@@ -0,0 +1,268 @@
+package mcpuiserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRequestTimeout is the default time a correlated request waits for
+// its response
+const DefaultRequestTimeout = 30 * time.Second
+
+// Correlation errors
+var (
+	ErrRequestTimeout     = errors.New("request timed out")
+	ErrUnknownMessageID   = errors.New("no pending request for message ID")
+	ErrDuplicateMessageID = errors.New("message ID is already pending")
+	ErrRequestCancelled   = errors.New("request cancelled")
+	ErrCorrelatorClosed   = errors.New("correlator closed")
+)
+
+// RequestTimeoutError reports a request whose response did not arrive before
+// its deadline
+type RequestTimeoutError struct {
+	MessageID string
+	Timeout   time.Duration
+}
+
+func (e *RequestTimeoutError) Error() string {
+	return fmt.Sprintf("request %q timed out after %s", e.MessageID, e.Timeout)
+}
+
+func (e *RequestTimeoutError) Is(target error) bool {
+	return target == ErrRequestTimeout
+}
+
+// CorrelatorOption is a functional option for configuring a Correlator
+type CorrelatorOption func(*Correlator)
+
+// WithRequestTimeout sets how long requests wait for a response. Non-positive
+// values disable the deadline.
+func WithRequestTimeout(timeout time.Duration) CorrelatorOption {
+	return func(c *Correlator) {
+		c.timeout = timeout
+	}
+}
+
+// WithIDPrefix sets the prefix of generated message IDs. IDs are only unique
+// across correlators with distinct prefixes.
+func WithIDPrefix(prefix string) CorrelatorOption {
+	return func(c *Correlator) {
+		c.prefix = prefix
+	}
+}
+
+// Correlator issues message IDs and matches ui-message-response replies to
+// the requests that carried them. It is safe for concurrent use.
+type Correlator struct {
+	prefix  string
+	timeout time.Duration
+	counter atomic.Uint64
+
+	mu      sync.Mutex
+	pending map[string]*PendingRequest
+	closed  bool
+}
+
+// NewCorrelator creates a new Correlator.
+// Default configuration:
+//   - Timeout: 30s
+//   - ID prefix: random per correlator
+func NewCorrelator(opts ...CorrelatorOption) *Correlator {
+	c := &Correlator{
+		prefix:  randomIDPrefix(),
+		timeout: DefaultRequestTimeout,
+		pending: make(map[string]*PendingRequest),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NextID returns a new message ID without tracking it
+func (c *Correlator) NextID() string {
+	return c.prefix + "-" + strconv.FormatUint(c.counter.Add(1), 10)
+}
+
+// Begin generates a message ID and tracks a pending request for it.
+//
+// Example:
+//
+//	req, err := correlator.Begin()
+//	if err != nil {
+//	    return err
+//	}
+//	send(mcpuiserver.NewRequestDataMessage("userStats", nil, req.ID()))
+//	payload, err := req.Wait(ctx)
+func (c *Correlator) Begin() (*PendingRequest, error) {
+	return c.Track(c.NextID())
+}
+
+// Track starts tracking a request whose message ID was issued elsewhere
+func (c *Correlator) Track(messageID string) (*PendingRequest, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("%w: messageId is required", ErrInvalidMessage)
+	}
+
+	req := &PendingRequest{
+		id:         messageID,
+		correlator: c,
+		done:       make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, ErrCorrelatorClosed
+	}
+	if _, exists := c.pending[messageID]; exists {
+		return nil, fmt.Errorf("%w: %q", ErrDuplicateMessageID, messageID)
+	}
+	c.pending[messageID] = req
+
+	if c.timeout > 0 {
+		timeout := c.timeout
+		req.deadline = time.Now().Add(timeout)
+		req.timer = time.AfterFunc(timeout, func() {
+			c.complete(messageID, MessageResponsePayload{}, &RequestTimeoutError{MessageID: messageID, Timeout: timeout})
+		})
+	}
+	return req, nil
+}
+
+// Resolve completes the pending request named by payload.MessageID. It returns
+// an error wrapping ErrUnknownMessageID for unknown, late or duplicate
+// responses.
+func (c *Correlator) Resolve(payload MessageResponsePayload) error {
+	if !c.complete(payload.MessageID, payload, nil) {
+		return fmt.Errorf("%w: %q", ErrUnknownMessageID, payload.MessageID)
+	}
+	return nil
+}
+
+// ResolveMessage completes the pending request answered by a
+// ui-message-response message
+func (c *Correlator) ResolveMessage(msg *MCPUIMessageResponseMessage) error {
+	return c.Resolve(msg.Payload)
+}
+
+// Cancel stops tracking a pending request; its waiters receive
+// ErrRequestCancelled. Cancelling an unknown ID is a no-op.
+func (c *Correlator) Cancel(messageID string) {
+	c.complete(messageID, MessageResponsePayload{}, fmt.Errorf("%w: %q", ErrRequestCancelled, messageID))
+}
+
+// Pending returns the number of requests awaiting a response
+func (c *Correlator) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// Close fails every pending request with ErrCorrelatorClosed and rejects new
+// ones
+func (c *Correlator) Close() {
+	c.mu.Lock()
+	c.closed = true
+	pending := c.pending
+	c.pending = make(map[string]*PendingRequest)
+	c.mu.Unlock()
+
+	for _, req := range pending {
+		req.finish(MessageResponsePayload{}, ErrCorrelatorClosed)
+	}
+}
+
+// complete removes the request from the pending set and hands it its result.
+// It reports whether the request was still pending.
+func (c *Correlator) complete(messageID string, payload MessageResponsePayload, err error) bool {
+	c.mu.Lock()
+	req, ok := c.pending[messageID]
+	if ok {
+		delete(c.pending, messageID)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	req.finish(payload, err)
+	return true
+}
+
+// PendingRequest is a request awaiting its ui-message-response
+type PendingRequest struct {
+	id         string
+	deadline   time.Time
+	correlator *Correlator
+	timer      *time.Timer
+
+	done    chan struct{}
+	payload MessageResponsePayload
+	err     error
+}
+
+// ID returns the message ID to send with the request
+func (r *PendingRequest) ID() string {
+	return r.id
+}
+
+// Deadline returns when the request times out; ok is false when the
+// correlator has no timeout
+func (r *PendingRequest) Deadline() (deadline time.Time, ok bool) {
+	return r.deadline, !r.deadline.IsZero()
+}
+
+// Done returns a channel that is closed once the request is resolved, times
+// out or is cancelled
+func (r *PendingRequest) Done() <-chan struct{} {
+	return r.done
+}
+
+// Result returns the response payload and error once Done is closed. The
+// payload's Error field carries any error reported by the other side.
+func (r *PendingRequest) Result() (MessageResponsePayload, error) {
+	<-r.done
+	return r.payload, r.err
+}
+
+// Wait blocks until the request completes or ctx ends. If ctx ends first the
+// request is cancelled and ctx.Err() is returned.
+func (r *PendingRequest) Wait(ctx context.Context) (MessageResponsePayload, error) {
+	select {
+	case <-r.done:
+		return r.payload, r.err
+	case <-ctx.Done():
+		r.correlator.Cancel(r.id)
+		return MessageResponsePayload{}, ctx.Err()
+	}
+}
+
+// finish records the result and wakes waiters. It is called exactly once, by
+// whoever removed the request from the pending set.
+func (r *PendingRequest) finish(payload MessageResponsePayload, err error) {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.payload = payload
+	r.err = err
+	close(r.done)
+}
+
+// randomIDPrefix returns a short random prefix so that IDs from different
+// correlators, such as one per widget, do not collide
+func randomIDPrefix() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "mcpui"
+	}
+	return "mcpui-" + hex.EncodeToString(b)
+}
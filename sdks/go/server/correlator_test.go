@@ -0,0 +1,224 @@
+package mcpuiserver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelator_NextID(t *testing.T) {
+	c := NewCorrelator(WithIDPrefix("widget"))
+	assert.Equal(t, "widget-1", c.NextID())
+	assert.Equal(t, "widget-2", c.NextID())
+
+	a, b := NewCorrelator(), NewCorrelator()
+	assert.True(t, strings.HasPrefix(a.NextID(), "mcpui-"))
+	assert.NotEqual(t, a.NextID(), b.NextID(), "default prefixes are random per correlator")
+}
+
+func TestCorrelator_Resolve(t *testing.T) {
+	c := NewCorrelator()
+	req, err := c.Begin()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, c.Pending())
+
+	deadline, ok := req.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(DefaultRequestTimeout), deadline, time.Second)
+
+	msg := NewMessageResponseMessage(req.ID(), map[string]interface{}{"ok": true}, nil, nil)
+	assert.NoError(t, c.ResolveMessage(msg))
+	assert.Equal(t, 0, c.Pending())
+
+	payload, err := req.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, msg.Payload, payload)
+
+	// A second response for the same ID is rejected
+	assert.ErrorIs(t, c.Resolve(msg.Payload), ErrUnknownMessageID)
+}
+
+func TestCorrelator_ResolveErrorResponse(t *testing.T) {
+	c := NewCorrelator()
+	req, err := c.Track("msg-1")
+	assert.NoError(t, err)
+
+	respErr := map[string]interface{}{"message": "denied"}
+	assert.NoError(t, c.Resolve(MessageResponsePayload{MessageID: "msg-1", Error: respErr}))
+
+	payload, err := req.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, respErr, payload.Error)
+}
+
+func TestCorrelator_Track(t *testing.T) {
+	c := NewCorrelator()
+
+	_, err := c.Track("")
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = c.Track("msg-1")
+	assert.NoError(t, err)
+	_, err = c.Track("msg-1")
+	assert.ErrorIs(t, err, ErrDuplicateMessageID)
+}
+
+func TestCorrelator_UnknownResponse(t *testing.T) {
+	c := NewCorrelator()
+	err := c.Resolve(MessageResponsePayload{MessageID: "missing"})
+	assert.ErrorIs(t, err, ErrUnknownMessageID)
+}
+
+func TestCorrelator_Timeout(t *testing.T) {
+	c := NewCorrelator(WithRequestTimeout(10 * time.Millisecond))
+	req, err := c.Begin()
+	assert.NoError(t, err)
+
+	_, err = req.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrRequestTimeout)
+
+	var timeoutErr *RequestTimeoutError
+	assert.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, req.ID(), timeoutErr.MessageID)
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	assert.Equal(t, 0, c.Pending())
+
+	// A late response is reported as unknown
+	assert.ErrorIs(t, c.Resolve(MessageResponsePayload{MessageID: req.ID()}), ErrUnknownMessageID)
+}
+
+func TestCorrelator_NoTimeout(t *testing.T) {
+	c := NewCorrelator(WithRequestTimeout(0))
+	req, err := c.Begin()
+	assert.NoError(t, err)
+
+	_, ok := req.Deadline()
+	assert.False(t, ok)
+
+	select {
+	case <-req.Done():
+		t.Fatal("request without a deadline must stay pending")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestCorrelator_WaitContextCancelled(t *testing.T) {
+	c := NewCorrelator()
+	req, err := c.Begin()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = req.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, c.Pending())
+
+	_, err = req.Result()
+	assert.ErrorIs(t, err, ErrRequestCancelled)
+}
+
+func TestCorrelator_Cancel(t *testing.T) {
+	c := NewCorrelator()
+	req, err := c.Begin()
+	assert.NoError(t, err)
+
+	c.Cancel(req.ID())
+	c.Cancel(req.ID())
+	c.Cancel("unknown")
+
+	_, err = req.Result()
+	assert.ErrorIs(t, err, ErrRequestCancelled)
+}
+
+func TestCorrelator_Close(t *testing.T) {
+	c := NewCorrelator()
+	first, err := c.Begin()
+	assert.NoError(t, err)
+	second, err := c.Begin()
+	assert.NoError(t, err)
+
+	c.Close()
+
+	for _, req := range []*PendingRequest{first, second} {
+		_, err := req.Result()
+		assert.ErrorIs(t, err, ErrCorrelatorClosed)
+	}
+	_, err = c.Begin()
+	assert.ErrorIs(t, err, ErrCorrelatorClosed)
+	assert.Equal(t, 0, c.Pending())
+}
+
+func TestCorrelator_Concurrent(t *testing.T) {
+	c := NewCorrelator()
+	const requests = 200
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := c.Begin()
+			if !assert.NoError(t, err) {
+				return
+			}
+			go func() {
+				_ = c.Resolve(MessageResponsePayload{MessageID: req.ID(), Response: i})
+			}()
+			payload, err := req.Wait(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, i, payload.Response)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, c.Pending())
+}
+
+func BenchmarkCorrelator_NextID(b *testing.B) {
+	c := NewCorrelator()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = c.NextID()
+		}
+	})
+}
+
+func BenchmarkCorrelator_RoundTrip(b *testing.B) {
+	for _, widgets := range []int{1, 100, 10000} {
+		b.Run("widgets="+strconv.Itoa(widgets), func(b *testing.B) {
+			c := NewCorrelator()
+
+			// Keep other widgets' requests outstanding while measuring
+			for i := 0; i < widgets; i++ {
+				if _, err := c.Begin(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.Cleanup(c.Close)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					req, err := c.Begin()
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					if err := c.Resolve(MessageResponsePayload{MessageID: req.ID()}); err != nil {
+						b.Error(err)
+						return
+					}
+					<-req.Done()
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package mcpuiserver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Data provider errors
+var (
+	ErrInvalidDataProvider = errors.New("invalid data provider")
+)
+
+// DataProvider answers ui-request-data messages of one request type by calling
+// a tool through the host's tool-calling API. The tool result is returned to
+// the widget in a ui-message-response.
+type DataProvider struct {
+	// ToolName is the tool that produces the data
+	ToolName string `json:"toolName"`
+	// ParamMapping maps tool argument names to RequestDataPayload.Params names.
+	// When nil the request parameters are passed unchanged.
+	ParamMapping map[string]string `json:"paramMapping,omitempty"`
+}
+
+// Validate checks that the provider names a tool
+func (p DataProvider) Validate() error {
+	if p.ToolName == "" {
+		return fmt.Errorf("%w: toolName is required", ErrInvalidDataProvider)
+	}
+	return nil
+}
+
+// ValidateDataProviders validates every provider keyed by request type.
+// Providers are checked in request type order so the reported error is deterministic.
+func ValidateDataProviders(providers map[string]DataProvider) error {
+	for _, requestType := range sortedKeys(providers) {
+		if requestType == "" {
+			return fmt.Errorf("%w: request type must not be empty", ErrInvalidDataProvider)
+		}
+		if err := providers[requestType].Validate(); err != nil {
+			return fmt.Errorf("request type %q: %w", requestType, err)
+		}
+	}
+	return nil
+}
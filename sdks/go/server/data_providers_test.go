@@ -0,0 +1,57 @@
+package mcpuiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataProvider_Validate(t *testing.T) {
+	assert.NoError(t, DataProvider{ToolName: "getUserStats"}.Validate())
+	assert.NoError(t, DataProvider{
+		ToolName:     "getUserStats",
+		ParamMapping: map[string]string{"userId": "id"},
+	}.Validate())
+	assert.ErrorIs(t, DataProvider{}.Validate(), ErrInvalidDataProvider)
+}
+
+func TestValidateDataProviders(t *testing.T) {
+	assert.NoError(t, ValidateDataProviders(nil))
+	assert.NoError(t, ValidateDataProviders(map[string]DataProvider{
+		"userStats": {ToolName: "getUserStats"},
+	}))
+
+	err := ValidateDataProviders(map[string]DataProvider{
+		"userStats": {ToolName: "getUserStats"},
+		"orders":    {},
+	})
+	assert.ErrorIs(t, err, ErrInvalidDataProvider)
+	assert.Contains(t, err.Error(), `request type "orders"`)
+
+	err = ValidateDataProviders(map[string]DataProvider{"": {ToolName: "getUserStats"}})
+	assert.ErrorIs(t, err, ErrInvalidDataProvider)
+}
+
+func TestDecodeShimConfig_DataProviders(t *testing.T) {
+	config, err := DecodeMcpAppsShimConfig(map[string]interface{}{
+		"dataProviders": map[string]interface{}{
+			"userStats": map[string]interface{}{
+				"toolName":     "getUserStats",
+				"paramMapping": map[string]interface{}{"userId": "id"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, DataProvider{
+		ToolName:     "getUserStats",
+		ParamMapping: map[string]string{"userId": "id"},
+	}, config.DataProviders["userStats"])
+
+	_, err = DecodeAppsSdkShimConfig(map[string]interface{}{
+		"dataProviders": map[string]interface{}{
+			"userStats": map[string]interface{}{},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidDataProvider)
+	assert.ErrorIs(t, err, ErrInvalidProtocolConfig)
+}
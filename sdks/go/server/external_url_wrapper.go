@@ -0,0 +1,80 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+)
+
+// externalURLFrameID is the id of the iframe loading the URL in a wrapper
+// page
+const externalURLFrameID = "mcpui-external-url"
+
+// externalURLOrigin returns the origin messages to the wrapped page are sent
+// to, rejecting URLs that are not absolute http or https
+func externalURLOrigin(iframeURL string) (string, error) {
+	u, err := url.Parse(iframeURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIframeURL, iframeURL)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// generateExternalURLWrapperPage wraps an external URL in an HTML page for
+// hosts that do not render text/uri-list. The URL is loaded in a full-size
+// iframe and a relay forwards MCP-UI messages in both directions:
+//   - messages from the inner page go to window.parent.postMessage, where the
+//     protocol adapter intercepts and translates them
+//   - MCP-UI messages the adapter dispatches on the wrapper window (responses,
+//     render data, lifecycle events) are posted to the inner page's origin
+//
+// Raw host messages, whose source is the parent window, are left to the
+// adapter and never reach the inner page.
+func generateExternalURLWrapperPage(payload *ExternalURLPayload, adapterScriptTag string) (string, error) {
+	origin, err := externalURLOrigin(payload.IframeURL)
+	if err != nil {
+		return "", err
+	}
+	// json.Marshal escapes <, > and &, so the value is safe inside <script>
+	originJSON, err := json.Marshal(origin)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+%s
+<style>html, body { margin: 0; height: 100%%; overflow: hidden; } iframe { border: 0; width: 100%%; height: 100%%; display: block; }</style>
+</head>
+<body>
+<iframe id="%s" src="%s" allow="clipboard-write"></iframe>
+<script>
+(function () {
+  var frame = document.getElementById("%s");
+  var origin = %s;
+  window.addEventListener("message", function (event) {
+    var data = event.data;
+    if (!data || typeof data !== "object" || typeof data.type !== "string") {
+      return;
+    }
+    if (event.source === frame.contentWindow) {
+      if (event.origin === origin) {
+        window.parent.postMessage(data, "*");
+      }
+    } else if (event.source === window || event.source === null) {
+      frame.contentWindow.postMessage(data, origin);
+    }
+  });
+})();
+</script>
+</body>
+</html>`,
+		adapterScriptTag,
+		externalURLFrameID, html.EscapeString(payload.IframeURL),
+		externalURLFrameID,
+		originJSON,
+	), nil
+}
@@ -0,0 +1,134 @@
+package mcpuiserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUIResource_ExternalURLWrapping(t *testing.T) {
+	tests := []struct {
+		name          string
+		iframeURL     string
+		opts          []Option
+		wantMimeType  string
+		wantAdapter   string
+		wantOrigin    string
+		wantUnwrapped bool
+	}{
+		{
+			name:         "MCP Apps",
+			iframeURL:    "https://example.com/app?a=1&b=2",
+			opts:         []Option{WithProtocol(ProtocolTypeMCPApps), WithExternalURLWrapping()},
+			wantMimeType: MimeTypeMCPAppsAdapter,
+			wantAdapter:  DefaultAdapterBaseURL + "/mcpapps-" + DefaultAdapterVersion + ".js",
+			wantOrigin:   "https://example.com",
+		},
+		{
+			name:      "Apps SDK with custom config",
+			iframeURL: "http://localhost:8080/widget",
+			opts: []Option{
+				WithExternalURLWrapping(),
+				WithProtocolConfig(&ProtocolConfig{
+					Type:    ProtocolTypeAppsSDK,
+					BaseURL: "https://cdn.example.com",
+					Version: "v2",
+					Config:  map[string]interface{}{"intentHandling": "ignore"},
+				}),
+			},
+			wantMimeType: MimeTypeAppsSdkAdapter,
+			wantAdapter:  "https://cdn.example.com/appssdk-v2.js",
+			wantOrigin:   "http://localhost:8080",
+		},
+		{
+			name:          "protocol without wrapping",
+			iframeURL:     "https://example.com/app",
+			opts:          []Option{WithProtocol(ProtocolTypeMCPApps)},
+			wantMimeType:  MimeTypeURIList,
+			wantUnwrapped: true,
+		},
+		{
+			name:          "wrapping with generic protocol",
+			iframeURL:     "https://example.com/app",
+			opts:          []Option{WithProtocol(ProtocolTypeGeneric), WithExternalURLWrapping()},
+			wantMimeType:  MimeTypeURIList,
+			wantUnwrapped: true,
+		},
+		{
+			name:          "wrapping without protocol",
+			iframeURL:     "https://example.com/app",
+			opts:          []Option{WithExternalURLWrapping()},
+			wantMimeType:  MimeTypeURIList,
+			wantUnwrapped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: tt.iframeURL}
+			resource, err := CreateUIResource("ui://test/external", content, EncodingText, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMimeType, resource.Resource.MimeType)
+			assert.NoError(t, ValidateUIResource(resource))
+
+			page := resource.Resource.Text
+			if tt.wantUnwrapped {
+				assert.Equal(t, tt.iframeURL, page)
+				return
+			}
+
+			assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+			assert.Contains(t, page, `<script src="`+tt.wantAdapter+`"`)
+			assert.Contains(t, page, `src="`+strings.ReplaceAll(tt.iframeURL, "&", "&amp;")+`"`)
+			assert.Contains(t, page, `var origin = "`+tt.wantOrigin+`";`)
+			assert.Contains(t, page, "window.parent.postMessage(data")
+			assert.Contains(t, page, "frame.contentWindow.postMessage(data, origin)")
+			assert.Less(t, strings.Index(page, tt.wantAdapter), strings.Index(page, "<iframe"),
+				"adapter must load before the inner page")
+		})
+	}
+}
+
+func TestCreateUIResource_ExternalURLWrappingErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		iframeURL string
+		config    *ProtocolConfig
+		wantErr   error
+	}{
+		{
+			name:      "relative URL",
+			iframeURL: "/app",
+			config:    &ProtocolConfig{Type: ProtocolTypeMCPApps},
+			wantErr:   ErrInvalidIframeURL,
+		},
+		{
+			name:      "javascript URL",
+			iframeURL: "javascript:alert(1)",
+			config:    &ProtocolConfig{Type: ProtocolTypeAppsSDK},
+			wantErr:   ErrInvalidIframeURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: tt.iframeURL}
+			resource, err := CreateUIResource("ui://test/external", content, EncodingText,
+				WithProtocolConfig(tt.config), WithExternalURLWrapping())
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, resource)
+		})
+	}
+}
+
+func TestCreateUIResource_ExternalURLWrappingInvalidProtocolConfig(t *testing.T) {
+	content := &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com"}
+	_, err := CreateUIResource("ui://test/external", content, EncodingText, WithExternalURLWrapping(),
+		WithProtocolConfig(&ProtocolConfig{Type: ProtocolTypeAppsSDK, Config: map[string]interface{}{"timeout": -1}}))
+
+	var configErr *ProtocolConfigError
+	assert.ErrorAs(t, err, &configErr)
+}
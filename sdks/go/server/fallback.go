@@ -0,0 +1,263 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrNoFallbackText is returned by NewToolResult when no text can be derived
+// from the resource and none was given with WithFallbackText
+var ErrNoFallbackText = errors.New("no fallback text for the UI resource")
+
+// UIExtensionName is the client capabilities extension that declares UI
+// resource support, following the SEP-1724 extensions pattern used by the
+// MCP-UI client SDK
+const UIExtensionName = "io.modelcontextprotocol/ui"
+
+// ContentBlock is an MCP content block of a tool result: *TextContent or
+// *UIResource
+type ContentBlock interface {
+	contentBlock()
+}
+
+// TextContent is an MCP text content block
+type TextContent struct {
+	Type        string                 `json:"type"` // Always "text"
+	Text        string                 `json:"text"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+}
+
+// NewTextContent creates a text content block
+func NewTextContent(text string) *TextContent {
+	return &TextContent{Type: "text", Text: text}
+}
+
+func (*TextContent) contentBlock() {}
+func (*UIResource) contentBlock()  {}
+
+// ToolResult is the result of an MCP tools/call request. It marshals to the
+// MCP CallToolResult shape.
+type ToolResult struct {
+	Content []ContentBlock `json:"content"`
+}
+
+// ToolResultOption is a functional option for NewToolResult
+type ToolResultOption func(*toolResultOptions)
+
+type toolResultOptions struct {
+	text         string
+	format       TextFormat
+	protocol     ProtocolType
+	protocolSet  bool
+	capabilities map[string]interface{}
+}
+
+// WithFallbackText sets the text shown to clients without UI support instead
+// of converting the resource HTML
+func WithFallbackText(text string) ToolResultOption {
+	return func(o *toolResultOptions) {
+		o.text = text
+	}
+}
+
+// WithFallbackFormat sets the format of text converted from the resource
+// HTML. Default: TextFormatMarkdown
+func WithFallbackFormat(format TextFormat) ToolResultOption {
+	return func(o *toolResultOptions) {
+		o.format = format
+	}
+}
+
+// WithClientProtocol sets the UI protocol negotiated with the client. Any of
+// ProtocolTypes means the client renders UI resources; an empty protocol
+// means it does not.
+// Example: WithClientProtocol(session.Protocol)
+func WithClientProtocol(protocol ProtocolType) ToolResultOption {
+	return func(o *toolResultOptions) {
+		o.protocol = protocol
+		o.protocolSet = true
+	}
+}
+
+// WithClientCapabilities sets the capabilities from the client's MCP
+// initialize request, which decide UI support as ClientRendersUI does. They
+// take precedence over WithClientProtocol.
+func WithClientCapabilities(capabilities map[string]interface{}) ToolResultOption {
+	return func(o *toolResultOptions) {
+		o.capabilities = capabilities
+	}
+}
+
+// NewToolResult builds a tool result with a text alternative to a UI resource
+// for clients that do not render ui:// resources. The text is given with
+// WithFallbackText or converted from the resource: HTML with HTMLToText and
+// external URLs, including those wrapped with WithExternalURLWrapping, to a
+// link. Remote DOM resources, bare or in a host page, need explicit text.
+//
+// The content depends on what is known about the client:
+//   - It renders UI (WithClientCapabilities or WithClientProtocol): the text,
+//     annotated for the assistant audience so the model reads it instead of
+//     the widget, followed by the resource
+//   - It does not render UI: the text only
+//   - Nothing is known: the text followed by the resource, unannotated
+//
+// Example:
+//
+//	result, err := NewToolResult(resource,
+//	    WithClientCapabilities(session.ClientCapabilities),
+//	    WithFallbackText("Revenue grew 12% to $1.2M."))
+func NewToolResult(resource *UIResource, opts ...ToolResultOption) (*ToolResult, error) {
+	if resource == nil {
+		return nil, ErrNilResource
+	}
+	options := &toolResultOptions{format: TextFormatMarkdown}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	text := options.text
+	if text == "" {
+		var err error
+		if text, err = fallbackText(resource, options.format); err != nil {
+			return nil, err
+		}
+	}
+	content := NewTextContent(text)
+
+	switch {
+	case options.capabilities != nil:
+		if !ClientRendersUI(options.capabilities, resource.Resource.MimeType) {
+			return &ToolResult{Content: []ContentBlock{content}}, nil
+		}
+	case options.protocolSet:
+		if !containsProtocol(ProtocolTypes, options.protocol) {
+			return &ToolResult{Content: []ContentBlock{content}}, nil
+		}
+	default:
+		return &ToolResult{Content: []ContentBlock{content, resource}}, nil
+	}
+	assistant := Annotations{Audience: []Role{RoleAssistant}}
+	content.Annotations = assistant.toMap()
+	return &ToolResult{Content: []ContentBlock{content, resource}}, nil
+}
+
+// fallbackText converts the resource content into text
+func fallbackText(resource *UIResource, format TextFormat) (string, error) {
+	mimeType := resource.Resource.MimeType
+	if !strings.HasPrefix(mimeType, MimeTypeHTML) && mimeType != MimeTypeURIList {
+		return "", fmt.Errorf("%w: cannot convert %q content, use WithFallbackText", ErrNoFallbackText, mimeType)
+	}
+	content, err := resource.decodedText()
+	if err != nil {
+		return "", err
+	}
+
+	var payload ResourceContentPayload
+	var doc *html.Node
+	if mimeType == MimeTypeURIList {
+		payload = &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: firstURI(content)}
+	} else {
+		doc = parseHTML(content)
+		if !sameMimeType(mimeType, MimeTypeHTML) {
+			payload = unwrapHostPage(doc)
+		}
+	}
+
+	var text string
+	switch payload := payload.(type) {
+	case *ExternalURLPayload:
+		text = payload.IframeURL
+		if text != "" && format == TextFormatMarkdown {
+			text = "<" + text + ">"
+		}
+	case *RemoteDOMPayload:
+		return "", fmt.Errorf("%w: cannot convert the Remote DOM script of the host page, use WithFallbackText", ErrNoFallbackText)
+	default:
+		// Adapter resources carry the widget HTML; the adapter script is
+		// dropped like any other script
+		text = documentText(doc, format)
+	}
+	if text == "" {
+		return "", fmt.Errorf("%w: the content has no text, use WithFallbackText", ErrNoFallbackText)
+	}
+	return text, nil
+}
+
+// unwrapHostPage returns the payload of a page generated for hosts that only
+// render HTML: an external URL wrapped with WithExternalURLWrapping or a
+// Remote DOM host page. It returns nil for other documents.
+func unwrapHostPage(doc *html.Node) ResourceContentPayload {
+	if frame := elementByID(doc, "iframe", externalURLFrameID); frame != nil {
+		return &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: strings.TrimSpace(attr(frame, "src"))}
+	}
+	if data := elementByID(doc, "script", remoteDOMHostDataID); data != nil {
+		var options remoteDOMHostOptions
+		if json.Unmarshal([]byte(textContent(data)), &options) == nil {
+			return &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: options.Code, Framework: options.Framework}
+		}
+	}
+	return nil
+}
+
+// ClientRendersUI reports whether client capabilities, as sent in the MCP
+// initialize request, declare the UIExtensionName extension for mimeType:
+//
+//	{"extensions": {"io.modelcontextprotocol/ui": {"mimeTypes": ["text/html;profile=mcp-app"]}}}
+//
+// A declaration without mimeTypes accepts every MIME type. MIME types match
+// by type and parameters, ignoring case and spacing.
+func ClientRendersUI(capabilities map[string]interface{}, mimeType string) bool {
+	var declared struct {
+		Extensions map[string]*struct {
+			MimeTypes []string `json:"mimeTypes"`
+		} `json:"extensions"`
+	}
+	if !decodeMetadata(capabilities, &declared) {
+		return false
+	}
+	extension, ok := declared.Extensions[UIExtensionName]
+	if !ok {
+		return false
+	}
+	if extension == nil || extension.MimeTypes == nil {
+		return true
+	}
+	for _, supported := range extension.MimeTypes {
+		if sameMimeType(supported, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameMimeType compares MIME types by type and parameters
+func sameMimeType(a, b string) bool {
+	aType, aParams, aErr := mime.ParseMediaType(a)
+	bType, bParams, bErr := mime.ParseMediaType(b)
+	if aErr != nil || bErr != nil {
+		return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+	}
+	if aType != bType || len(aParams) != len(bParams) {
+		return false
+	}
+	for k, v := range aParams {
+		if bParams[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func containsProtocol(protocols []ProtocolType, protocol ProtocolType) bool {
+	for _, p := range protocols {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToolResult(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: `<h2>Orders</h2><p>3 open, see <a href="https://shop.example.com/orders">all orders</a></p>`}
+	generic, err := CreateUIResource("ui://orders", html, EncodingText)
+	assert.NoError(t, err)
+	mcpApps, err := CreateUIResource("ui://orders", html, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+	assert.NoError(t, err)
+
+	markdown := "## Orders\n\n3 open, see [all orders](https://shop.example.com/orders)"
+	forModel := map[string]interface{}{"audience": []interface{}{"assistant"}}
+	uiCapabilities := map[string]interface{}{
+		"roots":      map[string]interface{}{"listChanged": true},
+		"extensions": map[string]interface{}{UIExtensionName: map[string]interface{}{"mimeTypes": []interface{}{MimeTypeMCPAppsAdapter}}},
+	}
+
+	tests := []struct {
+		name     string
+		resource *UIResource
+		opts     []ToolResultOption
+		want     []ContentBlock
+	}{
+		{
+			name:     "client unknown",
+			resource: generic,
+			want:     []ContentBlock{NewTextContent(markdown), generic},
+		},
+		{
+			name:     "negotiated protocol",
+			resource: mcpApps,
+			opts:     []ToolResultOption{WithClientProtocol(ProtocolTypeMCPApps)},
+			want:     []ContentBlock{&TextContent{Type: "text", Text: markdown, Annotations: forModel}, mcpApps},
+		},
+		{
+			name:     "no negotiated protocol",
+			resource: generic,
+			opts:     []ToolResultOption{WithClientProtocol("")},
+			want:     []ContentBlock{NewTextContent(markdown)},
+		},
+		{
+			name:     "capabilities with UI extension",
+			resource: mcpApps,
+			opts:     []ToolResultOption{WithClientCapabilities(uiCapabilities)},
+			want:     []ContentBlock{&TextContent{Type: "text", Text: markdown, Annotations: forModel}, mcpApps},
+		},
+		{
+			name:     "capabilities without the resource MIME type",
+			resource: generic,
+			opts:     []ToolResultOption{WithClientCapabilities(uiCapabilities), WithClientProtocol(ProtocolTypeGeneric)},
+			want:     []ContentBlock{NewTextContent(markdown)},
+		},
+		{
+			name:     "capabilities without UI extension",
+			resource: generic,
+			opts:     []ToolResultOption{WithClientCapabilities(map[string]interface{}{"sampling": map[string]interface{}{}})},
+			want:     []ContentBlock{NewTextContent(markdown)},
+		},
+		{
+			name:     "plain text",
+			resource: generic,
+			opts:     []ToolResultOption{WithFallbackFormat(TextFormatPlain), WithClientProtocol("")},
+			want:     []ContentBlock{NewTextContent("Orders\n\n3 open, see all orders (https://shop.example.com/orders)")},
+		},
+		{
+			name:     "explicit text",
+			resource: generic,
+			opts:     []ToolResultOption{WithFallbackText("3 open orders."), WithClientProtocol("")},
+			want:     []ContentBlock{NewTextContent("3 open orders.")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewToolResult(tt.resource, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Content)
+		})
+	}
+}
+
+func TestNewToolResult_ExternalURL(t *testing.T) {
+	resource, err := CreateUIResource("ui://docs",
+		&ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://docs.example.com"}, EncodingText)
+	assert.NoError(t, err)
+
+	result, err := NewToolResult(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, NewTextContent("<https://docs.example.com>"), result.Content[0])
+
+	result, err = NewToolResult(resource, WithFallbackFormat(TextFormatPlain))
+	assert.NoError(t, err)
+	assert.Equal(t, NewTextContent("https://docs.example.com"), result.Content[0])
+}
+
+func TestNewToolResult_AdapterWrapped(t *testing.T) {
+	externalURL := &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://docs.example.com/guide?a=1&b=2"}
+	remoteDOM := &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkReact}
+
+	tests := []struct {
+		name      string
+		content   ResourceContentPayload
+		encoding  Encoding
+		protocol  ProtocolType
+		format    TextFormat
+		wantText  string
+		wantError string
+	}{
+		{name: "wrapped external URL for MCP Apps", content: externalURL, encoding: EncodingText, protocol: ProtocolTypeMCPApps,
+			format: TextFormatMarkdown, wantText: "<https://docs.example.com/guide?a=1&b=2>"},
+		{name: "wrapped external URL for the Apps SDK as a blob", content: externalURL, encoding: EncodingBlob, protocol: ProtocolTypeAppsSDK,
+			format: TextFormatMarkdown, wantText: "<https://docs.example.com/guide?a=1&b=2>"},
+		{name: "wrapped external URL as plain text", content: externalURL, encoding: EncodingText, protocol: ProtocolTypeMCPApps,
+			format: TextFormatPlain, wantText: "https://docs.example.com/guide?a=1&b=2"},
+		{name: "Remote DOM host page", content: remoteDOM, encoding: EncodingText, protocol: ProtocolTypeMCPApps,
+			format: TextFormatMarkdown, wantError: "Remote DOM"},
+		{name: "Remote DOM host page as a blob", content: remoteDOM, encoding: EncodingBlob, protocol: ProtocolTypeAppsSDK,
+			format: TextFormatMarkdown, wantError: "Remote DOM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource("ui://wrapped", tt.content, tt.encoding,
+				WithProtocol(tt.protocol), WithExternalURLWrapping())
+			assert.NoError(t, err)
+
+			result, err := NewToolResult(resource, WithFallbackFormat(tt.format))
+			if tt.wantError != "" {
+				assert.ErrorIs(t, err, ErrNoFallbackText)
+				assert.Contains(t, err.Error(), tt.wantError)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []ContentBlock{NewTextContent(tt.wantText), resource}, result.Content)
+		})
+	}
+}
+
+func TestNewToolResult_Errors(t *testing.T) {
+	remoteDOM, err := CreateUIResource("ui://remote",
+		&RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "render()", Framework: FrameworkReact}, EncodingText)
+	assert.NoError(t, err)
+	scriptOnly, err := CreateUIResource("ui://app",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: `<div id="root"></div><script src="app.js"></script>`}, EncodingText)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		resource *UIResource
+		wantErr  error
+	}{
+		{name: "nil resource", resource: nil, wantErr: ErrNilResource},
+		{name: "remote DOM", resource: remoteDOM, wantErr: ErrNoFallbackText},
+		{name: "HTML without text", resource: scriptOnly, wantErr: ErrNoFallbackText},
+		{name: "invalid blob", resource: &UIResource{Type: "resource", Resource: ResourceContent{URI: "ui://x", MimeType: MimeTypeHTML, Blob: "%%%"}}, wantErr: ErrInvalidBase64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewToolResult(tt.resource)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, result)
+		})
+	}
+
+	result, err := NewToolResult(remoteDOM, WithFallbackText("A counter widget."))
+	assert.NoError(t, err, "explicit text works for any content")
+	assert.Len(t, result.Content, 2)
+}
+
+func TestToolResult_JSON(t *testing.T) {
+	resource, err := CreateUIResource("ui://hi", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText)
+	assert.NoError(t, err)
+	result, err := NewToolResult(resource, WithClientProtocol(ProtocolTypeGeneric))
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"content": [
+		{"type": "text", "text": "Hi", "annotations": {"audience": ["assistant"]}},
+		{"type": "resource", "resource": {"uri": "ui://hi", "mimeType": "text/html", "text": "<p>Hi</p>"}}
+	]}`, string(data))
+}
+
+func TestClientRendersUI(t *testing.T) {
+	extension := func(config interface{}) map[string]interface{} {
+		return map[string]interface{}{"extensions": map[string]interface{}{UIExtensionName: config}}
+	}
+
+	tests := []struct {
+		name         string
+		capabilities map[string]interface{}
+		mimeType     string
+		want         bool
+	}{
+		{name: "no capabilities", capabilities: nil, mimeType: MimeTypeHTML, want: false},
+		{name: "other extension", capabilities: map[string]interface{}{"extensions": map[string]interface{}{"example/other": map[string]interface{}{}}}, mimeType: MimeTypeHTML, want: false},
+		{name: "any MIME type", capabilities: extension(map[string]interface{}{}), mimeType: MimeTypeRemoteDomReact, want: true},
+		{name: "listed MIME type", capabilities: extension(map[string]interface{}{"mimeTypes": []string{MimeTypeMCPAppsAdapter}}), mimeType: MimeTypeMCPAppsAdapter, want: true},
+		{name: "MIME type spacing and case", capabilities: extension(map[string]interface{}{"mimeTypes": []string{"Text/HTML; profile=mcp-app"}}), mimeType: MimeTypeMCPAppsAdapter, want: true},
+		{name: "unlisted MIME type", capabilities: extension(map[string]interface{}{"mimeTypes": []string{MimeTypeMCPAppsAdapter}}), mimeType: MimeTypeHTML, want: false},
+		{name: "malformed", capabilities: map[string]interface{}{"extensions": "ui"}, mimeType: MimeTypeHTML, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClientRendersUI(tt.capabilities, tt.mimeType))
+		})
+	}
+}
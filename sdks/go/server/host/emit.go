@@ -0,0 +1,32 @@
+package host
+
+import (
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+)
+
+// EmitRenderData sends ui-lifecycle-iframe-render-data to the widget, for
+// example when the theme or tool output changes.
+func (r *Router) EmitRenderData(renderData mcpuiserver.RenderData) error {
+	return r.sender.Send(mcpuiserver.NewRenderDataMessage(renderData, nil))
+}
+
+// EmitToolInputPartial sends the tool arguments streamed so far, letting the
+// widget render a preview before the tool runs.
+func (r *Router) EmitToolInputPartial(arguments map[string]interface{}) error {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	return r.sender.Send(mcpuiserver.NewToolInputPartialMessage(arguments, nil))
+}
+
+// EmitToolCancelled tells the widget that the tool call it renders was
+// cancelled. The reason is optional.
+func (r *Router) EmitToolCancelled(reason string) error {
+	return r.sender.Send(mcpuiserver.NewToolCancelledMessage(reason, nil))
+}
+
+// EmitTeardown tells the widget it is about to be removed so it can release
+// resources and persist state. The reason is optional.
+func (r *Router) EmitTeardown(reason string) error {
+	return r.sender.Send(mcpuiserver.NewTeardownMessage(reason, nil))
+}
@@ -0,0 +1,85 @@
+package host
+
+import (
+	"errors"
+	"testing"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Emit(t *testing.T) {
+	tests := []struct {
+		name string
+		emit func(r *Router) error
+		want map[string]interface{}
+	}{
+		{
+			name: "render data",
+			emit: func(r *Router) error {
+				return r.EmitRenderData(mcpuiserver.RenderData{Theme: "dark"})
+			},
+			want: map[string]interface{}{
+				"type":    "ui-lifecycle-iframe-render-data",
+				"payload": map[string]interface{}{"renderData": map[string]interface{}{"theme": "dark"}},
+			},
+		},
+		{
+			name: "tool input partial",
+			emit: func(r *Router) error {
+				return r.EmitToolInputPartial(map[string]interface{}{"city": "Pa"})
+			},
+			want: map[string]interface{}{
+				"type":    "ui-lifecycle-tool-input-partial",
+				"payload": map[string]interface{}{"arguments": map[string]interface{}{"city": "Pa"}},
+			},
+		},
+		{
+			name: "tool input partial without arguments",
+			emit: func(r *Router) error {
+				return r.EmitToolInputPartial(nil)
+			},
+			want: map[string]interface{}{
+				"type":    "ui-lifecycle-tool-input-partial",
+				"payload": map[string]interface{}{"arguments": map[string]interface{}{}},
+			},
+		},
+		{
+			name: "tool cancelled",
+			emit: func(r *Router) error {
+				return r.EmitToolCancelled("user")
+			},
+			want: map[string]interface{}{
+				"type":    "ui-lifecycle-tool-cancelled",
+				"payload": map[string]interface{}{"reason": "user"},
+			},
+		},
+		{
+			name: "teardown",
+			emit: func(r *Router) error {
+				return r.EmitTeardown("")
+			},
+			want: map[string]interface{}{
+				"type":    "ui-lifecycle-teardown",
+				"payload": map[string]interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			router := NewRouter(sender)
+
+			assert.NoError(t, tt.emit(router))
+			assert.Equal(t, []map[string]interface{}{tt.want}, sender.sent())
+		})
+	}
+}
+
+func TestRouter_EmitSendError(t *testing.T) {
+	sendErr := errors.New("frame closed")
+	router := NewRouter(&recordingSender{err: sendErr})
+
+	assert.ErrorIs(t, router.EmitTeardown("closed"), sendErr)
+}
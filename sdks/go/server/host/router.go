@@ -0,0 +1,286 @@
+// Package host implements the host side of the MCP-UI protocol for Go
+// applications that embed widgets, such as desktop apps built on a webview.
+// It decodes widget messages, dispatches them to registered handlers and
+// replies with the acknowledgment and response messages widgets expect.
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+)
+
+// DefaultTimeout is the default time a handler has to answer a request
+const DefaultTimeout = 30 * time.Second
+
+// Router errors
+var (
+	ErrNoHandler = errors.New("no handler registered for message type")
+)
+
+// Sender delivers host-to-widget messages, for example by serializing them
+// to JSON and calling postMessage on the widget frame.
+type Sender interface {
+	Send(msg interface{}) error
+}
+
+// SenderFunc adapts an ordinary function to the Sender interface.
+type SenderFunc func(msg interface{}) error
+
+// Send calls f(msg)
+func (f SenderFunc) Send(msg interface{}) error {
+	return f(msg)
+}
+
+// Handler types for each widget action. The returned value is sent to the
+// widget as the response; a returned error is sent as the response error.
+type (
+	ToolCallHandler    func(ctx context.Context, payload mcpuiserver.ToolCallPayload) (interface{}, error)
+	PromptHandler      func(ctx context.Context, payload mcpuiserver.PromptPayload) (interface{}, error)
+	LinkHandler        func(ctx context.Context, payload mcpuiserver.LinkPayload) (interface{}, error)
+	IntentHandler      func(ctx context.Context, payload mcpuiserver.IntentPayload) (interface{}, error)
+	NotifyHandler      func(ctx context.Context, payload mcpuiserver.NotificationPayload) error
+	SizeChangeHandler  func(ctx context.Context, payload mcpuiserver.SizeChangePayload) error
+	RequestDataHandler func(ctx context.Context, payload mcpuiserver.RequestDataPayload) (interface{}, error)
+)
+
+// Option is a functional option for configuring a Router.
+type Option func(*Router)
+
+// WithTimeout sets how long a handler has to answer a request before the
+// widget receives a timeout error. Non-positive values disable the timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Router) {
+		r.timeout = timeout
+	}
+}
+
+// Router dispatches widget messages to registered handlers. For every message
+// that carries a message ID it sends a ui-message-received acknowledgment
+// before running the handler and a ui-message-response with the handler's
+// result or error afterwards. It is safe for concurrent use.
+type Router struct {
+	sender  Sender
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	handlers handlers
+}
+
+// handlers is the set of registered handlers, copied out under the lock
+// so handlers may register other handlers without deadlocking.
+type handlers struct {
+	toolCall    ToolCallHandler
+	prompt      PromptHandler
+	link        LinkHandler
+	intent      IntentHandler
+	notify      NotifyHandler
+	sizeChange  SizeChangeHandler
+	requestData RequestDataHandler
+}
+
+// NewRouter creates a Router that replies to the widget through sender.
+// Default configuration:
+//   - Timeout: 30s
+func NewRouter(sender Sender, opts ...Option) *Router {
+	r := &Router{
+		sender:  sender,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// OnToolCall registers the handler for "tool" messages.
+func (r *Router) OnToolCall(h ToolCallHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.toolCall = h
+}
+
+// OnPrompt registers the handler for "prompt" messages.
+func (r *Router) OnPrompt(h PromptHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.prompt = h
+}
+
+// OnLink registers the handler for "link" messages.
+func (r *Router) OnLink(h LinkHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.link = h
+}
+
+// OnIntent registers the handler for "intent" messages.
+func (r *Router) OnIntent(h IntentHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.intent = h
+}
+
+// OnNotify registers the handler for "notify" messages.
+func (r *Router) OnNotify(h NotifyHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.notify = h
+}
+
+// OnSizeChange registers the handler for "ui-size-change" messages.
+func (r *Router) OnSizeChange(h SizeChangeHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.sizeChange = h
+}
+
+// OnRequestData registers the handler for "ui-request-data" messages.
+func (r *Router) OnRequestData(h RequestDataHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers.requestData = h
+}
+
+// HandleMessage decodes a raw widget message and dispatches it. It blocks
+// until the handler has answered, the per-request timeout has expired or ctx
+// is cancelled, and the response has been sent; call it in a goroutine to
+// handle messages concurrently.
+//
+// Messages without a message ID are dispatched but not acknowledged, as the
+// widget has no way to correlate a reply. Failures are answered with an
+// *mcpuiserver.ProtocolError: actions without a registered handler are
+// unsupported, expired requests time out and other handler errors become
+// host errors unless the handler returns a ProtocolError itself. Lifecycle
+// and response messages are ignored. The returned error reports decoding and
+// send failures; messages outside the protocol return an
+// *mcpuiserver.UnknownMessageTypeError so callers can forward them.
+func (r *Router) HandleMessage(ctx context.Context, data []byte) error {
+	msg, err := mcpuiserver.ParseMessage(data)
+	if err != nil {
+		return err
+	}
+	return r.Dispatch(ctx, msg)
+}
+
+// Dispatch routes an already decoded message. See HandleMessage.
+func (r *Router) Dispatch(ctx context.Context, msg mcpuiserver.Message) error {
+	r.mu.RLock()
+	h := r.handlers
+	r.mu.RUnlock()
+
+	switch m := msg.(type) {
+	case *mcpuiserver.UIActionResultToolCallType:
+		return r.run(ctx, m.MessageID, m.MessageType(), h.toolCall != nil, func(ctx context.Context) (interface{}, error) {
+			return h.toolCall(ctx, m.Payload)
+		})
+	case *mcpuiserver.UIActionResultPromptType:
+		return r.run(ctx, m.MessageID, m.MessageType(), h.prompt != nil, func(ctx context.Context) (interface{}, error) {
+			return h.prompt(ctx, m.Payload)
+		})
+	case *mcpuiserver.UIActionResultLinkType:
+		return r.run(ctx, m.MessageID, m.MessageType(), h.link != nil, func(ctx context.Context) (interface{}, error) {
+			return h.link(ctx, m.Payload)
+		})
+	case *mcpuiserver.UIActionResultIntentType:
+		return r.run(ctx, m.MessageID, m.MessageType(), h.intent != nil, func(ctx context.Context) (interface{}, error) {
+			return h.intent(ctx, m.Payload)
+		})
+	case *mcpuiserver.UIActionResultNotificationType:
+		return r.run(ctx, m.MessageID, m.MessageType(), h.notify != nil, func(ctx context.Context) (interface{}, error) {
+			return acknowledged(h.notify(ctx, m.Payload))
+		})
+	case *mcpuiserver.MCPUISizeChangeMessage:
+		return r.run(ctx, m.MessageID, m.MessageType(), h.sizeChange != nil, func(ctx context.Context) (interface{}, error) {
+			return acknowledged(h.sizeChange(ctx, m.Payload))
+		})
+	case *mcpuiserver.MCPUIRequestDataMessage:
+		return r.run(ctx, &m.MessageID, m.MessageType(), h.requestData != nil, func(ctx context.Context) (interface{}, error) {
+			return h.requestData(ctx, m.Payload)
+		})
+	default:
+		return nil
+	}
+}
+
+// run acknowledges the request, runs the handler under the request timeout
+// and sends its result.
+func (r *Router) run(ctx context.Context, messageID *string, msgType mcpuiserver.ProtocolMessageType, registered bool, handle func(context.Context) (interface{}, error)) error {
+	replyTo := ""
+	if messageID != nil {
+		replyTo = *messageID
+	}
+
+	if replyTo != "" {
+		if err := r.sender.Send(mcpuiserver.NewMessageReceivedMessage(replyTo, nil)); err != nil {
+			return err
+		}
+	}
+
+	var response interface{}
+	var err error
+	if registered {
+		response, err = r.call(ctx, handle)
+	} else {
+		err = fmt.Errorf("%w: %s", ErrNoHandler, msgType)
+	}
+
+	if replyTo == "" {
+		return nil
+	}
+	if err != nil {
+		return r.sender.Send(mcpuiserver.NewMessageResponseMessage(replyTo, nil, errorPayload(err), nil))
+	}
+	return r.sender.Send(mcpuiserver.NewMessageResponseMessage(replyTo, response, nil, nil))
+}
+
+// call runs handle in its own goroutine so that a handler ignoring its
+// context still cannot hold the widget past the request timeout.
+func (r *Router) call(ctx context.Context, handle func(context.Context) (interface{}, error)) (interface{}, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		response interface{}
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := handle(ctx)
+		done <- result{response, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.response, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// acknowledged turns the error-only result of notify and size change
+// handlers into the response sent to the widget.
+func acknowledged(err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"acknowledged": true}, nil
+}
+
+// errorPayload converts a handler error into the error shape used by the
+// adapter runtimes. Missing handlers are reported as unsupported and
+// timeouts as timeout; handlers can return an *mcpuiserver.ProtocolError to
+// choose the code themselves.
+func errorPayload(err error) *mcpuiserver.ProtocolError {
+	if errors.Is(err, ErrNoHandler) {
+		return mcpuiserver.NewUnsupportedError(err.Error())
+	}
+	return mcpuiserver.AsProtocolError(err)
+}
@@ -0,0 +1,345 @@
+package host
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSender collects every message sent to the widget as decoded JSON
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []map[string]interface{}
+	err      error
+}
+
+func (s *recordingSender) Send(msg interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, decoded)
+	return nil
+}
+
+func (s *recordingSender) sent() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]interface{}(nil), s.messages...)
+}
+
+func TestRouter_ToolCall(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+
+	var got mcpuiserver.ToolCallPayload
+	router.OnToolCall(func(ctx context.Context, payload mcpuiserver.ToolCallPayload) (interface{}, error) {
+		got = payload
+		return map[string]interface{}{"rows": 3}, nil
+	})
+
+	err := router.HandleMessage(context.Background(),
+		[]byte(`{"type":"tool","messageId":"msg-1","payload":{"toolName":"fetchData","params":{"query":"stats"}}}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "fetchData", got.ToolName)
+	assert.Equal(t, map[string]interface{}{"query": "stats"}, got.Params)
+
+	sent := sender.sent()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, "ui-message-received", sent[0]["type"])
+	assert.Equal(t, map[string]interface{}{"messageId": "msg-1"}, sent[0]["payload"])
+	assert.Equal(t, "ui-message-response", sent[1]["type"])
+	assert.Equal(t, map[string]interface{}{
+		"messageId": "msg-1",
+		"response":  map[string]interface{}{"rows": float64(3)},
+	}, sent[1]["payload"])
+}
+
+func TestRouter_AllHandlers(t *testing.T) {
+	tests := []struct {
+		name         string
+		register     func(r *Router, called *bool)
+		data         string
+		wantResponse interface{}
+	}{
+		{
+			name: "prompt",
+			register: func(r *Router, called *bool) {
+				r.OnPrompt(func(ctx context.Context, p mcpuiserver.PromptPayload) (interface{}, error) {
+					*called = p.Prompt == "Hi"
+					return "sent", nil
+				})
+			},
+			data:         `{"type":"prompt","messageId":"m","payload":{"prompt":"Hi"}}`,
+			wantResponse: "sent",
+		},
+		{
+			name: "link",
+			register: func(r *Router, called *bool) {
+				r.OnLink(func(ctx context.Context, p mcpuiserver.LinkPayload) (interface{}, error) {
+					*called = p.URL == "https://example.com"
+					return nil, nil
+				})
+			},
+			data: `{"type":"link","messageId":"m","payload":{"url":"https://example.com"}}`,
+		},
+		{
+			name: "intent",
+			register: func(r *Router, called *bool) {
+				r.OnIntent(func(ctx context.Context, p mcpuiserver.IntentPayload) (interface{}, error) {
+					*called = p.Intent == "showSettings"
+					return true, nil
+				})
+			},
+			data:         `{"type":"intent","messageId":"m","payload":{"intent":"showSettings"}}`,
+			wantResponse: true,
+		},
+		{
+			name: "notify",
+			register: func(r *Router, called *bool) {
+				r.OnNotify(func(ctx context.Context, p mcpuiserver.NotificationPayload) error {
+					*called = p.Message == "Saved"
+					return nil
+				})
+			},
+			data:         `{"type":"notify","messageId":"m","payload":{"message":"Saved"}}`,
+			wantResponse: map[string]interface{}{"acknowledged": true},
+		},
+		{
+			name: "size change",
+			register: func(r *Router, called *bool) {
+				r.OnSizeChange(func(ctx context.Context, p mcpuiserver.SizeChangePayload) error {
+					*called = p.Height != nil && *p.Height == 300
+					return nil
+				})
+			},
+			data:         `{"type":"ui-size-change","messageId":"m","payload":{"height":300}}`,
+			wantResponse: map[string]interface{}{"acknowledged": true},
+		},
+		{
+			name: "request data",
+			register: func(r *Router, called *bool) {
+				r.OnRequestData(func(ctx context.Context, p mcpuiserver.RequestDataPayload) (interface{}, error) {
+					*called = p.RequestType == "userStats"
+					return []interface{}{"a"}, nil
+				})
+			},
+			data:         `{"type":"ui-request-data","messageId":"m","payload":{"requestType":"userStats"}}`,
+			wantResponse: []interface{}{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			router := NewRouter(sender)
+			called := false
+			tt.register(router, &called)
+
+			assert.NoError(t, router.HandleMessage(context.Background(), []byte(tt.data)))
+			assert.True(t, called)
+
+			sent := sender.sent()
+			assert.Len(t, sent, 2)
+			payload := sent[1]["payload"].(map[string]interface{})
+			assert.Equal(t, "m", payload["messageId"])
+			assert.Equal(t, tt.wantResponse, payload["response"])
+			assert.NotContains(t, payload, "error")
+		})
+	}
+}
+
+func TestRouter_HandlerError(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+	router.OnToolCall(func(ctx context.Context, payload mcpuiserver.ToolCallPayload) (interface{}, error) {
+		return nil, errors.New("tool failed")
+	})
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"tool","messageId":"m","payload":{"toolName":"x"}}`))
+	assert.NoError(t, err)
+
+	sent := sender.sent()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, map[string]interface{}{
+		"messageId": "m",
+		"error":     map[string]interface{}{"code": "host-error", "message": "tool failed"},
+	}, sent[1]["payload"])
+}
+
+func TestRouter_HandlerProtocolError(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+	router.OnLink(func(ctx context.Context, payload mcpuiserver.LinkPayload) (interface{}, error) {
+		return nil, fmt.Errorf("open link: %w", mcpuiserver.NewDeniedError("blocked by policy"))
+	})
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"link","messageId":"m","payload":{"url":"https://example.com"}}`))
+	assert.NoError(t, err)
+
+	sent := sender.sent()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, map[string]interface{}{"code": "denied", "message": "blocked by policy"}, sent[1]["payload"].(map[string]interface{})["error"])
+}
+
+func TestRouter_NoHandler(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"link","messageId":"m","payload":{"url":"https://example.com"}}`))
+	assert.NoError(t, err)
+
+	sent := sender.sent()
+	assert.Len(t, sent, 2)
+	errPayload := sent[1]["payload"].(map[string]interface{})["error"].(map[string]interface{})
+	assert.Equal(t, "unsupported", errPayload["code"])
+	assert.Contains(t, errPayload["message"], ErrNoHandler.Error())
+}
+
+func TestRouter_WithoutMessageID(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+	called := false
+	router.OnNotify(func(ctx context.Context, payload mcpuiserver.NotificationPayload) error {
+		called = true
+		return nil
+	})
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"notify","payload":{"message":"hi"}}`))
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Empty(t, sender.sent(), "messages without an ID are not acknowledged")
+}
+
+func TestRouter_Timeout(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender, WithTimeout(20*time.Millisecond))
+
+	release := make(chan struct{})
+	defer close(release)
+	router.OnToolCall(func(ctx context.Context, payload mcpuiserver.ToolCallPayload) (interface{}, error) {
+		// Ignores ctx on purpose: the router must still answer in time
+		<-release
+		return "late", nil
+	})
+
+	start := time.Now()
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"tool","messageId":"m","payload":{"toolName":"slow"}}`))
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	sent := sender.sent()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, map[string]interface{}{
+		"code":    "timeout",
+		"message": context.DeadlineExceeded.Error(),
+	}, sent[1]["payload"].(map[string]interface{})["error"])
+}
+
+func TestRouter_ContextCancellation(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender, WithTimeout(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	router.OnRequestData(func(ctx context.Context, payload mcpuiserver.RequestDataPayload) (interface{}, error) {
+		cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	err := router.HandleMessage(ctx, []byte(`{"type":"ui-request-data","messageId":"m","payload":{"requestType":"x"}}`))
+	assert.NoError(t, err)
+
+	sent := sender.sent()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, map[string]interface{}{
+		"code":    "host-error",
+		"message": context.Canceled.Error(),
+	}, sent[1]["payload"].(map[string]interface{})["error"])
+}
+
+func TestRouter_DecodeErrors(t *testing.T) {
+	router := NewRouter(&recordingSender{})
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"ui-custom"}`))
+	assert.ErrorIs(t, err, mcpuiserver.ErrUnknownMessageType)
+
+	err = router.HandleMessage(context.Background(), []byte(`{"type":"tool","payload":{}}`))
+	assert.ErrorIs(t, err, mcpuiserver.ErrInvalidMessage)
+}
+
+func TestRouter_IgnoresLifecycleMessages(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"ui-lifecycle-iframe-ready","messageId":"m"}`))
+	assert.NoError(t, err)
+	assert.Empty(t, sender.sent())
+}
+
+func TestRouter_SendError(t *testing.T) {
+	sendErr := errors.New("frame closed")
+	router := NewRouter(&recordingSender{err: sendErr})
+	router.OnPrompt(func(ctx context.Context, payload mcpuiserver.PromptPayload) (interface{}, error) {
+		t.Fatal("handler must not run when the acknowledgment cannot be sent")
+		return nil, nil
+	})
+
+	err := router.HandleMessage(context.Background(), []byte(`{"type":"prompt","messageId":"m","payload":{"prompt":"hi"}}`))
+	assert.ErrorIs(t, err, sendErr)
+}
+
+func TestRouter_ConcurrentMessages(t *testing.T) {
+	sender := &recordingSender{}
+	router := NewRouter(sender)
+	router.OnToolCall(func(ctx context.Context, payload mcpuiserver.ToolCallPayload) (interface{}, error) {
+		return payload.ToolName, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = router.HandleMessage(context.Background(), []byte(`{"type":"tool","messageId":"m","payload":{"toolName":"x"}}`))
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			router.OnPrompt(func(ctx context.Context, payload mcpuiserver.PromptPayload) (interface{}, error) {
+				return nil, nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, sender.sent(), 100)
+}
+
+func TestSenderFunc(t *testing.T) {
+	var got interface{}
+	sender := SenderFunc(func(msg interface{}) error {
+		got = msg
+		return nil
+	})
+
+	assert.NoError(t, sender.Send("hello"))
+	assert.Equal(t, "hello", got)
+}
@@ -0,0 +1,466 @@
+package mcpuiserver
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// TextFormat selects the format of text generated from HTML
+type TextFormat string
+
+const (
+	// TextFormatPlain is plain text: links are written as "text (url)"
+	TextFormatPlain TextFormat = "plain"
+	// TextFormatMarkdown is GitHub-flavored markdown, with headings,
+	// emphasis, [text](url) links and pipe tables
+	TextFormatMarkdown TextFormat = "markdown"
+)
+
+// HTMLToText converts an HTML document or fragment into readable text for
+// clients that do not render UI resources. Scripts, styles, the <head> and
+// form controls are dropped; headings, paragraphs, lists and block quotes
+// become text blocks, links keep their URL and tables become aligned text
+// tables. Markdown special characters in the text are not escaped.
+//
+// The HTML is parsed with the HTML5 algorithm of golang.org/x/net/html, so
+// unclosed and misnested elements are handled as browsers handle them. The
+// conversion is meant for the simple, mostly static markup of widget
+// templates; it does not run scripts, so content rendered by JavaScript is
+// lost. Provide the text explicitly for such widgets.
+//
+// Example:
+//
+//	text := HTMLToText(`<h1>Sales</h1><table><tr><th>Region</th><th>Total</th></tr>
+//	    <tr><td>EMEA</td><td>1.2M</td></tr></table>`, TextFormatMarkdown)
+//	// # Sales
+//	//
+//	// | Region | Total |
+//	// | ------ | ----- |
+//	// | EMEA   | 1.2M  |
+func HTMLToText(htmlContent string, format TextFormat) string {
+	return documentText(parseHTML(htmlContent), format)
+}
+
+// documentText renders a parsed document as HTMLToText does
+func documentText(doc *html.Node, format TextFormat) string {
+	r := &textRenderer{markdown: format == TextFormatMarkdown}
+	text := r.container(doc, "\n\n")
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimRight(strings.TrimLeft(text, "\n"), " \n")
+}
+
+// parseHTML parses an HTML document or fragment with the HTML5 parsing
+// algorithm, closing elements and recovering from errors as browsers do
+func parseHTML(s string) *html.Node {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		// html.Parse only fails when reading fails, which a string reader
+		// never does
+		return &html.Node{Type: html.DocumentNode}
+	}
+	return doc
+}
+
+// attr returns the value of an attribute of n, or "" without it
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// findElement returns the first element in n, depth first, for which match
+// reports true, or nil
+func findElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	for d := range n.Descendants() {
+		if d.Type == html.ElementNode && match(d) {
+			return d
+		}
+	}
+	return nil
+}
+
+// elementByID returns the first element in n with the given tag and id, or
+// nil
+func elementByID(n *html.Node, tag, id string) *html.Node {
+	return findElement(n, func(e *html.Node) bool {
+		return e.Data == tag && attr(e, "id") == id
+	})
+}
+
+// elementName returns the element name of n, or "" for other nodes
+func elementName(n *html.Node) string {
+	if n.Type != html.ElementNode {
+		return ""
+	}
+	return n.Data
+}
+
+// blockElements start a new text block
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true, "caption": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true, "html": true, "li": true, "main": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "section": true, "summary": true, "table": true,
+	"ul": true,
+}
+
+// skippedElements produce no text
+var skippedElements = map[string]bool{
+	"button": true, "canvas": true, "head": true, "iframe": true, "input": true, "noscript": true,
+	"script": true, "select": true, "style": true, "svg": true, "template": true, "textarea": true,
+	"title": true,
+}
+
+// textRenderer renders an element tree as text blocks separated by blank
+// lines, with inline content collapsed as browsers collapse whitespace
+type textRenderer struct {
+	markdown bool
+}
+
+// container renders the children of n, grouping runs of inline content into
+// blocks
+func (r *textRenderer) container(n *html.Node, sep string) string {
+	var blocks []string
+	var inline strings.Builder
+	flush := func() {
+		if text := cleanInline(inline.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+	for child := range n.ChildNodes() {
+		if !blockElements[elementName(child)] {
+			r.inline(&inline, child)
+			continue
+		}
+		flush()
+		if block := r.block(child); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	flush()
+	return strings.Join(blocks, sep)
+}
+
+// block renders a block element
+func (r *textRenderer) block(n *html.Node) string {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := strings.ReplaceAll(r.inlineText(n), "\n", " ")
+		if text == "" || !r.markdown {
+			return text
+		}
+		return strings.Repeat("#", int(n.Data[1]-'0')) + " " + text
+	case "ul", "ol":
+		return r.list(n)
+	case "table":
+		return r.table(n)
+	case "pre":
+		text := strings.TrimPrefix(textContent(n), "\n")
+		text = strings.TrimRight(text, " \t\n")
+		if text == "" || !r.markdown {
+			return text
+		}
+		return "```\n" + text + "\n```"
+	case "blockquote":
+		prefix := "  "
+		if r.markdown {
+			prefix = "> "
+		}
+		return prefixLines(r.container(n, "\n\n"), prefix, prefix)
+	case "hr":
+		if r.markdown {
+			return "---"
+		}
+		return strings.Repeat("-", 10)
+	case "li", "dt", "dd":
+		return r.container(n, "\n")
+	default:
+		return r.container(n, "\n\n")
+	}
+}
+
+// list renders the <li> items of a list, numbering ordered lists from their
+// start attribute
+func (r *textRenderer) list(n *html.Node) string {
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		number = start
+	}
+	var items []string
+	for child := range n.ChildNodes() {
+		if elementName(child) != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		content := r.container(child, "\n")
+		items = append(items, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table as aligned columns: a pipe table in markdown, where
+// the first row is the header, and columns separated by " | " in plain text,
+// where a header row is underlined when it has <th> cells
+func (r *textRenderer) table(n *html.Node) string {
+	var caption string
+	var rows [][]string
+	header := false
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for child := range n.ChildNodes() {
+			switch elementName(child) {
+			case "caption":
+				caption = strings.ReplaceAll(r.inlineText(child), "\n", " ")
+			case "thead", "tbody", "tfoot":
+				collect(child)
+			case "tr":
+				var cells []string
+				for cell := range child.ChildNodes() {
+					if elementName(cell) != "td" && elementName(cell) != "th" {
+						continue
+					}
+					if elementName(cell) == "th" && len(rows) == 0 {
+						header = true
+					}
+					text := strings.ReplaceAll(r.inlineText(cell), "\n", " ")
+					if r.markdown {
+						text = strings.ReplaceAll(text, "|", `\|`)
+					}
+					cells = append(cells, text)
+				}
+				if len(cells) > 0 {
+					rows = append(rows, cells)
+				}
+			}
+		}
+	}
+	collect(n)
+	if len(rows) == 0 {
+		return caption
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	widths := make([]int, columns)
+	for i := range rows {
+		for len(rows[i]) < columns {
+			rows[i] = append(rows[i], "")
+		}
+		for c, cell := range rows[i] {
+			widths[c] = max(widths[c], utf8.RuneCountInString(cell))
+		}
+	}
+	if r.markdown {
+		for c := range widths {
+			widths[c] = max(widths[c], 3)
+		}
+	}
+
+	var b strings.Builder
+	if caption != "" {
+		b.WriteString(caption + "\n\n")
+	}
+	writeRow := func(cells []string) {
+		var line strings.Builder
+		if r.markdown {
+			line.WriteString("| ")
+		}
+		for c, cell := range cells {
+			if c > 0 {
+				line.WriteString(" | ")
+			}
+			line.WriteString(cell + strings.Repeat(" ", widths[c]-utf8.RuneCountInString(cell)))
+		}
+		if r.markdown {
+			line.WriteString(" |")
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	for i, row := range rows {
+		writeRow(row)
+		if i > 0 || (!r.markdown && !header) {
+			continue
+		}
+		dashes := make([]string, columns)
+		for c, width := range widths {
+			dashes[c] = strings.Repeat("-", width)
+		}
+		if r.markdown {
+			writeRow(dashes)
+		} else {
+			b.WriteString(strings.Join(dashes, "-+-") + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// inline appends the inline rendering of n, with whitespace collapsed to
+// single spaces and <br> as a line break
+func (r *textRenderer) inline(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(collapseSpace(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return // Comments and doctypes
+	}
+	if skippedElements[n.Data] {
+		return
+	}
+	switch n.Data {
+	case "br":
+		b.WriteString("\n")
+	case "img":
+		b.WriteString(attr(n, "alt"))
+	case "a":
+		b.WriteString(r.link(n))
+	case "strong", "b":
+		b.WriteString(r.emphasis(n, "**"))
+	case "em", "i":
+		b.WriteString(r.emphasis(n, "*"))
+	case "code", "kbd", "samp":
+		b.WriteString(r.emphasis(n, "`"))
+	default:
+		if blockElements[n.Data] {
+			b.WriteString("\n")
+		}
+		for child := range n.ChildNodes() {
+			r.inline(b, child)
+		}
+		if blockElements[n.Data] {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// inlineText renders the children of n as one cleaned inline run
+func (r *textRenderer) inlineText(n *html.Node) string {
+	var b strings.Builder
+	for child := range n.ChildNodes() {
+		r.inline(&b, child)
+	}
+	return cleanInline(b.String())
+}
+
+// link renders a link as [text](url) in markdown and "text (url)" in plain
+// text. Fragment and javascript: links keep only their text.
+func (r *textRenderer) link(n *html.Node) string {
+	text := strings.ReplaceAll(r.inlineText(n), "\n", " ")
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return text
+	}
+	switch {
+	case r.markdown && text == "":
+		return "<" + href + ">"
+	case r.markdown:
+		return "[" + text + "](" + href + ")"
+	case text == "" || text == href:
+		return href
+	default:
+		return text + " (" + href + ")"
+	}
+}
+
+// emphasis wraps the text of n in mark in markdown, keeping surrounding
+// spaces outside the mark
+func (r *textRenderer) emphasis(n *html.Node, mark string) string {
+	var b strings.Builder
+	for child := range n.ChildNodes() {
+		r.inline(&b, child)
+	}
+	raw := b.String()
+	text := strings.TrimSpace(raw)
+	if !r.markdown || text == "" {
+		return raw
+	}
+	leading := raw[:len(raw)-len(strings.TrimLeft(raw, " \n"))]
+	trailing := raw[len(strings.TrimRight(raw, " \n")):]
+	return leading + mark + text + mark + trailing
+}
+
+// textContent returns the text of n and its descendants as written
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if elementName(n) == "br" {
+		return "\n"
+	}
+	var b strings.Builder
+	for child := range n.ChildNodes() {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+// collapseSpace replaces each run of HTML whitespace with one space
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for i := 0; i < len(s); i++ {
+		if isHTMLSpace(s[i]) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(s[i])
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// isHTMLSpace reports whether c is ASCII whitespace as HTML defines it
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// cleanInline collapses spaces in each line of an inline run, trims the
+// lines and drops blank lines at the start and end
+func cleanInline(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.Join(strings.Fields(line), " "))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// prefixLines prefixes the first line of s with first and the others with
+// rest, leaving blank lines empty
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
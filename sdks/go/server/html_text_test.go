@@ -0,0 +1,117 @@
+package mcpuiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name         string
+		html         string
+		wantMarkdown string
+		wantPlain    string
+	}{
+		{
+			name:         "document",
+			html:         "<!DOCTYPE html><html><head><title>Widget</title><style>p{color:red}</style><script>render()</script></head><body><h1>Weather</h1><p>Sunny,\n   <b>24&deg;C</b></p><!-- updated hourly --></body></html>",
+			wantMarkdown: "# Weather\n\nSunny, **24°C**",
+			wantPlain:    "Weather\n\nSunny, 24°C",
+		},
+		{
+			name:         "links",
+			html:         `<p>See <a href="https://example.com/report?a=1&amp;b=2">the report</a>, <a href="https://example.com">https://example.com</a> or <a href="#top">top</a>.</p>`,
+			wantMarkdown: "See [the report](https://example.com/report?a=1&b=2), [https://example.com](https://example.com) or top.",
+			wantPlain:    "See the report (https://example.com/report?a=1&b=2), https://example.com or top.",
+		},
+		{
+			name:         "lists",
+			html:         `<ul><li>Apples<li>Pears<ul><li>Conference</li></ul></li></ul><ol start="3"><li>Third</li><li>Fourth</li></ol>`,
+			wantMarkdown: "- Apples\n- Pears\n  - Conference\n\n3. Third\n4. Fourth",
+			wantPlain:    "- Apples\n- Pears\n  - Conference\n\n3. Third\n4. Fourth",
+		},
+		{
+			name: "table",
+			html: `<table><caption>Q1 sales</caption><thead><tr><th>Region</th><th>Total</th></tr></thead>
+				<tbody><tr><td>EMEA</td><td>1.2M</td><tr><td>Americas | North</td><td><a href="https://example.com/us">3.4M</a></td></tbody></table>`,
+			wantMarkdown: "Q1 sales\n\n" +
+				"| Region            | Total                          |\n" +
+				"| ----------------- | ------------------------------ |\n" +
+				"| EMEA              | 1.2M                           |\n" +
+				"| Americas \\| North | [3.4M](https://example.com/us) |",
+			wantPlain: "Q1 sales\n\n" +
+				"Region           | Total\n" +
+				"-----------------+------------------------------\n" +
+				"EMEA             | 1.2M\n" +
+				"Americas | North | 3.4M (https://example.com/us)",
+		},
+		{
+			name:         "table without header",
+			html:         `<table><tr><td>a</td><td>b</td></tr><tr><td>ccc</td></tr></table>`,
+			wantMarkdown: "| a   | b   |\n| --- | --- |\n| ccc |     |",
+			wantPlain:    "a   | b\nccc |",
+		},
+		{
+			name:         "line breaks and whitespace",
+			html:         "<div>First line<br>Second   line</div><div>\n\n</div><p>Last</p>",
+			wantMarkdown: "First line\nSecond line\n\nLast",
+			wantPlain:    "First line\nSecond line\n\nLast",
+		},
+		{
+			name:         "preformatted",
+			html:         "<pre>\nif x &lt; 1 {\n    return\n}\n</pre>",
+			wantMarkdown: "```\nif x < 1 {\n    return\n}\n```",
+			wantPlain:    "if x < 1 {\n    return\n}",
+		},
+		{
+			name:         "blockquote and rule",
+			html:         "<blockquote><p>Quoted</p><p>Twice</p></blockquote><hr><p>After</p>",
+			wantMarkdown: "> Quoted\n>\n> Twice\n\n---\n\nAfter",
+			wantPlain:    "  Quoted\n\n  Twice\n\n----------\n\nAfter",
+		},
+		{
+			name:         "form controls and images",
+			html:         `<form><label>Name <input name="q" value="x"></label><select><option>One<option>Two</select><button onclick="go()">Go</button></form><img src="chart.png" alt="Revenue chart">`,
+			wantMarkdown: "Name\n\nRevenue chart",
+			wantPlain:    "Name\n\nRevenue chart",
+		},
+		{
+			name:         "unclosed paragraphs and stray tags",
+			html:         "<p>One<p>Two</span> 3 < 4<div>Block</div>",
+			wantMarkdown: "One\n\nTwo 3 < 4\n\nBlock",
+			wantPlain:    "One\n\nTwo 3 < 4\n\nBlock",
+		},
+		{
+			name:         "emphasis keeps spaces outside",
+			html:         "<p><em>very </em>good <code>x := 1</code></p>",
+			wantMarkdown: "*very* good `x := 1`",
+			wantPlain:    "very good x := 1",
+		},
+		{
+			name:         "misnested formatting",
+			html:         "<p><b>bold <i>both</b> italic</i></p>",
+			wantMarkdown: "**bold *both*** *italic*",
+			wantPlain:    "bold both italic",
+		},
+		{
+			name:         "entities and text after a list",
+			html:         "<ul><li>One<li>Two</ul>x &lt;y&gt; &amp;amp; &copy",
+			wantMarkdown: "- One\n- Two\n\nx <y> &amp; ©",
+			wantPlain:    "- One\n- Two\n\nx <y> &amp; ©",
+		},
+		{
+			name:         "script only",
+			html:         `<div id="root"></div><script>document.getElementById("root").textContent = "<p>hi</p>"</script>`,
+			wantMarkdown: "",
+			wantPlain:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantMarkdown, HTMLToText(tt.html, TextFormatMarkdown))
+			assert.Equal(t, tt.wantPlain, HTMLToText(tt.html, TextFormatPlain))
+		})
+	}
+}
@@ -540,3 +540,21 @@ func TestResourceURIMetaKeyUsage(t *testing.T) {
 	assert.Equal(t, "ui://my-widget", meta[ResourceURIMetaKey])
 	assert.Equal(t, "ui/resourceUri", ResourceURIMetaKey)
 }
+
+func TestCreateUIResource_InvalidProtocolConfig(t *testing.T) {
+	resource, err := CreateUIResource(
+		"ui://test",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<div/>"},
+		EncodingText,
+		WithProtocolConfig(&ProtocolConfig{
+			Type: ProtocolTypeAppsSDK,
+			Config: map[string]interface{}{
+				"intentHandling": "execute",
+			},
+		}),
+	)
+
+	assert.Nil(t, resource)
+	assert.ErrorIs(t, err, ErrInvalidProtocolConfig)
+	assert.ErrorIs(t, err, ErrInvalidIntentHandling)
+}
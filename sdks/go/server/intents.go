@@ -0,0 +1,183 @@
+package mcpuiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IntentRouteWildcard is the routing table key that matches any intent
+// without a route of its own.
+const IntentRouteWildcard = "*"
+
+// IntentAction selects what an adapter does with a routed intent
+type IntentAction string
+
+const (
+	// IntentActionTool calls a tool with parameters mapped from the intent
+	IntentActionTool IntentAction = "tool"
+	// IntentActionPrompt sends a follow-up prompt rendered from a template
+	IntentActionPrompt IntentAction = "prompt"
+	// IntentActionLink opens a URL rendered from a template
+	IntentActionLink IntentAction = "link"
+	// IntentActionDrop acknowledges the intent without doing anything
+	IntentActionDrop IntentAction = "drop"
+)
+
+// Intent routing errors
+var (
+	ErrInvalidIntentRoute = errors.New("invalid intent route")
+)
+
+// IntentRoute declares how an adapter handles one intent name, so a widget
+// emitting UIActionResultIntent behaves the same on every host.
+//
+// Templates substitute {{name}} with the intent parameter "name" (strings as-is,
+// other values as JSON), {{$intent}} with the intent name and {{$params}} with
+// all parameters as JSON. Link templates URL-encode substituted values.
+type IntentRoute struct {
+	// Action is the routing action to perform
+	Action IntentAction `json:"action"`
+	// ToolName is the tool to call for IntentActionTool
+	ToolName string `json:"toolName,omitempty"`
+	// ParamMapping maps tool argument names to intent parameter names for
+	// IntentActionTool. When nil the intent parameters are passed unchanged.
+	ParamMapping map[string]string `json:"paramMapping,omitempty"`
+	// Template is the prompt text for IntentActionPrompt or the URL for IntentActionLink
+	Template string `json:"template,omitempty"`
+}
+
+// Validate checks that the route has the fields its action requires
+func (r IntentRoute) Validate() error {
+	switch r.Action {
+	case IntentActionTool:
+		if r.ToolName == "" {
+			return fmt.Errorf("%w: toolName is required for action %q", ErrInvalidIntentRoute, r.Action)
+		}
+		if r.Template != "" {
+			return fmt.Errorf("%w: template is not used by action %q", ErrInvalidIntentRoute, r.Action)
+		}
+	case IntentActionPrompt, IntentActionLink:
+		if r.Template == "" {
+			return fmt.Errorf("%w: template is required for action %q", ErrInvalidIntentRoute, r.Action)
+		}
+		if r.ToolName != "" || r.ParamMapping != nil {
+			return fmt.Errorf("%w: toolName and paramMapping are only used by action %q", ErrInvalidIntentRoute, IntentActionTool)
+		}
+	case IntentActionDrop:
+		if r.ToolName != "" || r.ParamMapping != nil || r.Template != "" {
+			return fmt.Errorf("%w: action %q takes no other fields", ErrInvalidIntentRoute, r.Action)
+		}
+	default:
+		return fmt.Errorf("%w: action must be 'tool', 'prompt', 'link' or 'drop' but got %q", ErrInvalidIntentRoute, r.Action)
+	}
+	return nil
+}
+
+// ValidateIntentRoutes validates every route in an intent routing table.
+// Routes are checked in name order so the reported error is deterministic.
+func ValidateIntentRoutes(routes map[string]IntentRoute) error {
+	for _, name := range sortedKeys(routes) {
+		if name == "" {
+			return fmt.Errorf("%w: intent name must not be empty", ErrInvalidIntentRoute)
+		}
+		if err := routes[name].Validate(); err != nil {
+			return fmt.Errorf("intent %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResolveIntentRoute returns the route for intent, falling back to the
+// wildcard route
+func ResolveIntentRoute(routes map[string]IntentRoute, intent string) (IntentRoute, bool) {
+	if route, ok := routes[intent]; ok {
+		return route, true
+	}
+	route, ok := routes[IntentRouteWildcard]
+	return route, ok
+}
+
+var intentTemplatePlaceholder = regexp.MustCompile(`\{\{\s*([$\w.-]+)\s*\}\}`)
+
+// RenderTemplate substitutes the intent and its parameters into the route
+// template. Substituted values are URL-encoded for IntentActionLink routes.
+func (r IntentRoute) RenderTemplate(intent string, params map[string]interface{}) string {
+	return intentTemplatePlaceholder.ReplaceAllStringFunc(r.Template, func(match string) string {
+		key := intentTemplatePlaceholder.FindStringSubmatch(match)[1]
+
+		var value interface{}
+		switch key {
+		case "$intent":
+			value = intent
+		case "$params":
+			if params == nil {
+				value = map[string]interface{}{}
+			} else {
+				value = params
+			}
+		default:
+			value = params[key]
+		}
+
+		var text string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			text = v
+		default:
+			// Match JSON.stringify, which does not escape HTML characters
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(v); err == nil {
+				text = strings.TrimSuffix(buf.String(), "\n")
+			}
+		}
+		if r.Action == IntentActionLink {
+			return encodeURIComponent(text)
+		}
+		return text
+	})
+}
+
+// MapParams renames parameters according to mapping, which maps target names
+// to source names. Source parameters that are absent are skipped. A nil
+// mapping passes the parameters through unchanged.
+func MapParams(mapping map[string]string, params map[string]interface{}) map[string]interface{} {
+	if mapping == nil {
+		if params == nil {
+			return map[string]interface{}{}
+		}
+		return params
+	}
+	result := make(map[string]interface{}, len(mapping))
+	for target, source := range mapping {
+		if value, ok := params[source]; ok {
+			result[target] = value
+		}
+	}
+	return result
+}
+
+// encodeURIComponent escapes s like the JavaScript function of the same name,
+// which leaves letters, digits and -_.!~*'() unescaped. url.QueryEscape also
+// escapes !*'() and writes spaces as +.
+func encodeURIComponent(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-_.!~*'()", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
@@ -0,0 +1,215 @@
+package mcpuiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntentRoute_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   IntentRoute
+		wantErr bool
+	}{
+		{
+			name: "tool route with mapping",
+			route: IntentRoute{
+				Action:       IntentActionTool,
+				ToolName:     "openSettings",
+				ParamMapping: map[string]string{"section": "tab"},
+			},
+		},
+		{
+			name:  "tool route passing params through",
+			route: IntentRoute{Action: IntentActionTool, ToolName: "openSettings"},
+		},
+		{
+			name:    "tool route without tool name",
+			route:   IntentRoute{Action: IntentActionTool},
+			wantErr: true,
+		},
+		{
+			name:    "tool route with template",
+			route:   IntentRoute{Action: IntentActionTool, ToolName: "openSettings", Template: "x"},
+			wantErr: true,
+		},
+		{
+			name:  "prompt route",
+			route: IntentRoute{Action: IntentActionPrompt, Template: "Show the {{tab}} settings"},
+		},
+		{
+			name:    "prompt route without template",
+			route:   IntentRoute{Action: IntentActionPrompt},
+			wantErr: true,
+		},
+		{
+			name:  "link route",
+			route: IntentRoute{Action: IntentActionLink, Template: "https://example.com/settings?tab={{tab}}"},
+		},
+		{
+			name:    "link route with tool name",
+			route:   IntentRoute{Action: IntentActionLink, Template: "https://example.com", ToolName: "x"},
+			wantErr: true,
+		},
+		{
+			name:  "drop route",
+			route: IntentRoute{Action: IntentActionDrop},
+		},
+		{
+			name:    "drop route with template",
+			route:   IntentRoute{Action: IntentActionDrop, Template: "x"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			route:   IntentRoute{Action: "forward"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.route.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidIntentRoute)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateIntentRoutes(t *testing.T) {
+	assert.NoError(t, ValidateIntentRoutes(nil))
+	assert.NoError(t, ValidateIntentRoutes(map[string]IntentRoute{
+		"showSettings":      {Action: IntentActionTool, ToolName: "openSettings"},
+		IntentRouteWildcard: {Action: IntentActionDrop},
+	}))
+
+	err := ValidateIntentRoutes(map[string]IntentRoute{
+		"b": {Action: IntentActionPrompt},
+		"a": {Action: IntentActionLink},
+	})
+	assert.ErrorIs(t, err, ErrInvalidIntentRoute)
+	assert.Contains(t, err.Error(), `intent "a"`, "first route in name order is reported")
+
+	err = ValidateIntentRoutes(map[string]IntentRoute{"": {Action: IntentActionDrop}})
+	assert.ErrorIs(t, err, ErrInvalidIntentRoute)
+}
+
+func TestDecodeAppsSdkShimConfig_IntentRoutes(t *testing.T) {
+	config, err := DecodeAppsSdkShimConfig(map[string]interface{}{
+		"intentRoutes": map[string]interface{}{
+			"showSettings": map[string]interface{}{
+				"action":       "tool",
+				"toolName":     "openSettings",
+				"paramMapping": map[string]interface{}{"section": "tab"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, IntentRoute{
+		Action:       IntentActionTool,
+		ToolName:     "openSettings",
+		ParamMapping: map[string]string{"section": "tab"},
+	}, config.IntentRoutes["showSettings"])
+
+	_, err = DecodeMcpAppsShimConfig(map[string]interface{}{
+		"intentRoutes": map[string]interface{}{
+			"showSettings": map[string]interface{}{"action": "link"},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidIntentRoute)
+	assert.ErrorIs(t, err, ErrInvalidProtocolConfig)
+}
+
+func TestResolveIntentRoute(t *testing.T) {
+	routes := map[string]IntentRoute{
+		"showSettings":      {Action: IntentActionTool, ToolName: "openSettings"},
+		IntentRouteWildcard: {Action: IntentActionDrop},
+	}
+
+	route, ok := ResolveIntentRoute(routes, "showSettings")
+	assert.True(t, ok)
+	assert.Equal(t, IntentActionTool, route.Action)
+
+	route, ok = ResolveIntentRoute(routes, "other")
+	assert.True(t, ok)
+	assert.Equal(t, IntentActionDrop, route.Action)
+
+	_, ok = ResolveIntentRoute(nil, "other")
+	assert.False(t, ok)
+}
+
+func TestIntentRoute_RenderTemplate(t *testing.T) {
+	params := map[string]interface{}{"query": "a b&c", "page": 2}
+
+	tests := []struct {
+		name   string
+		route  IntentRoute
+		params map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "prompt substitutes values as-is",
+			route:  IntentRoute{Action: IntentActionPrompt, Template: "Search {{query}} on page {{ page }} for {{$intent}}"},
+			params: params,
+			want:   "Search a b&c on page 2 for search",
+		},
+		{
+			name:   "link encodes values",
+			route:  IntentRoute{Action: IntentActionLink, Template: "https://example.com/?q={{query}}"},
+			params: params,
+			want:   "https://example.com/?q=a%20b%26c",
+		},
+		{
+			name:   "all params as JSON",
+			route:  IntentRoute{Action: IntentActionPrompt, Template: "{{$params}}"},
+			params: params,
+			want:   `{"page":2,"query":"a b&c"}`,
+		},
+		{
+			name:  "missing values render empty",
+			route: IntentRoute{Action: IntentActionPrompt, Template: "[{{missing}}] {{$params}}"},
+			want:  "[] {}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.route.RenderTemplate("search", tt.params))
+		})
+	}
+}
+
+func TestEncodeURIComponent(t *testing.T) {
+	// Expected values from JavaScript's encodeURIComponent
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "abcXYZ019-_.~", want: "abcXYZ019-_.~"},
+		{in: "!'()*", want: "!'()*"},
+		{in: "a b", want: "a%20b"},
+		{in: "a+b&c=d/e?f#g", want: "a%2Bb%26c%3Dd%2Fe%3Ff%23g"},
+		{in: "Grüße €", want: "Gr%C3%BC%C3%9Fe%20%E2%82%AC"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, encodeURIComponent(tt.in))
+		})
+	}
+}
+
+func TestMapParams(t *testing.T) {
+	params := map[string]interface{}{"tab": "account", "extra": true}
+
+	assert.Equal(t, params, MapParams(nil, params))
+	assert.Equal(t, map[string]interface{}{}, MapParams(nil, nil))
+	assert.Equal(t,
+		map[string]interface{}{"section": "account"},
+		MapParams(map[string]string{"section": "tab", "missing": "nope"}, params))
+}
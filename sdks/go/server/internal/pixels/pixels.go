@@ -0,0 +1,29 @@
+// Package pixels converts CSS pixel lengths decoded from JSON to integers.
+// Browsers report lengths such as 412.5 on zoomed or scaled pages.
+package pixels
+
+import (
+	"fmt"
+	"math"
+)
+
+// Round rounds a CSS pixel length to the nearest integer, rejecting values
+// that do not fit a 32-bit int
+func Round(v float64) (int, error) {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, fmt.Errorf("pixel value %v out of range", v)
+	}
+	return int(math.Round(v)), nil
+}
+
+// RoundPtr is Round for optional values
+func RoundPtr(v *float64) (*int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	rounded, err := Round(*v)
+	if err != nil {
+		return nil, err
+	}
+	return &rounded, nil
+}
@@ -0,0 +1,52 @@
+package pixels
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in      float64
+		want    int
+		wantErr bool
+	}{
+		{in: 0, want: 0},
+		{in: 412.5, want: 413},
+		{in: 299.4, want: 299},
+		{in: -0.4, want: 0},
+		{in: -0.5, want: -1},
+		{in: math.MaxInt32, want: math.MaxInt32},
+		{in: math.MaxInt32 + 0.5, wantErr: true},
+		{in: math.MinInt32 - 1, wantErr: true},
+		{in: 1e12, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Round(tt.in)
+		if tt.wantErr {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestRoundPtr(t *testing.T) {
+	got, err := RoundPtr(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	v := 599.6
+	got, err = RoundPtr(&v)
+	assert.NoError(t, err)
+	assert.Equal(t, 600, *got)
+
+	v = 1e12
+	got, err = RoundPtr(&v)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
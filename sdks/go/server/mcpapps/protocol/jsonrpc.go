@@ -0,0 +1,321 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+)
+
+// JSONRPCVersion is the value of the "jsonrpc" member of every envelope
+const JSONRPCVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = mcpuiserver.JSONRPCCodeParseError
+	CodeInvalidRequest = mcpuiserver.JSONRPCCodeInvalidRequest
+	CodeMethodNotFound = mcpuiserver.JSONRPCCodeMethodNotFound
+	CodeInvalidParams  = mcpuiserver.JSONRPCCodeInvalidParams
+	CodeInternalError  = mcpuiserver.JSONRPCCodeInternalError
+)
+
+// Server error codes for protocol errors without a standard JSON-RPC code
+const (
+	CodeTimeout = mcpuiserver.JSONRPCCodeTimeout
+	CodeDenied  = mcpuiserver.JSONRPCCodeDenied
+)
+
+// Envelope errors
+var (
+	ErrInvalidEnvelope = errors.New("invalid JSON-RPC envelope")
+	ErrUnknownMethod   = errors.New("unknown MCP Apps method")
+)
+
+// ID is a JSON-RPC request ID, either a number or a string
+type ID struct {
+	num   int64
+	str   string
+	isStr bool
+}
+
+// NumberID creates a numeric request ID, as issued by the adapter runtime
+func NumberID(n int64) ID {
+	return ID{num: n}
+}
+
+// StringID creates a string request ID
+func StringID(s string) ID {
+	return ID{str: s, isStr: true}
+}
+
+// String returns the ID in the form used to key pending requests
+func (id ID) String() string {
+	if id.isStr {
+		return id.str
+	}
+	return strconv.FormatInt(id.num, 10)
+}
+
+// MarshalJSON encodes the ID as a JSON number or string
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.isStr {
+		return json.Marshal(id.str)
+	}
+	return []byte(strconv.FormatInt(id.num, 10)), nil
+}
+
+// UnmarshalJSON decodes a JSON number or string ID
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = StringID(s)
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("id must be a string or an integer, got %s", data)
+	}
+	*id = NumberID(n)
+	return nil
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// ProtocolError converts the JSON-RPC error into the error shape sent to
+// widgets, mapping the code with mcpuiserver.ProtocolErrorCodeFromJSONRPC
+func (e *Error) ProtocolError() *mcpuiserver.ProtocolError {
+	return mcpuiserver.NewProtocolError(mcpuiserver.ProtocolErrorCodeFromJSONRPC(e.Code), e.Message, e.Data)
+}
+
+// ErrorFromProtocolError converts a protocol error into a JSON-RPC error
+// object for MCP Apps hosts
+func ErrorFromProtocolError(err *mcpuiserver.ProtocolError) *Error {
+	return &Error{Code: err.Code.JSONRPCCode(), Message: err.Message, Data: err.Data}
+}
+
+// Kind classifies a JSON-RPC message
+type Kind string
+
+const (
+	KindRequest      Kind = "request"
+	KindNotification Kind = "notification"
+	KindResponse     Kind = "response"
+)
+
+// Envelope is a decoded JSON-RPC 2.0 message. Params and Result are kept raw
+// until the caller decodes them into the struct for the method.
+type Envelope struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *ID             `json:"id,omitempty"`
+	Method  Method          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the envelope. Error responses without an ID are sent
+// with "id": null, as JSON-RPC requires for parse errors and invalid requests.
+func (e Envelope) MarshalJSON() ([]byte, error) {
+	type plain Envelope
+	if e.ID != nil || e.Method != "" {
+		return json.Marshal(plain(e))
+	}
+	return json.Marshal(struct {
+		plain
+		ID *ID `json:"id"`
+	}{plain: plain(e)})
+}
+
+// Kind reports whether the envelope is a request, a notification or a response
+func (e *Envelope) Kind() Kind {
+	switch {
+	case e.Method != "" && e.ID != nil:
+		return KindRequest
+	case e.Method != "":
+		return KindNotification
+	default:
+		return KindResponse
+	}
+}
+
+// DecodeParams decodes the envelope params into v. Absent params leave v
+// untouched.
+func (e *Envelope) DecodeParams(v interface{}) error {
+	if len(e.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(e.Params, v); err != nil {
+		return fmt.Errorf("%w: params of %s: %v", ErrInvalidEnvelope, e.Method, err)
+	}
+	return nil
+}
+
+// DecodeResult decodes the envelope result into v
+func (e *Envelope) DecodeResult(v interface{}) error {
+	if len(e.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(e.Result, v); err != nil {
+		return fmt.Errorf("%w: result: %v", ErrInvalidEnvelope, err)
+	}
+	return nil
+}
+
+// TypedParams decodes the params into the struct for the envelope's method,
+// for example *ToolInputParams for ui/notifications/tool-input. Methods
+// outside the protocol return an error wrapping ErrUnknownMethod.
+func (e *Envelope) TypedParams() (interface{}, error) {
+	params := e.Method.NewParams()
+	if params == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMethod, e.Method)
+	}
+	if err := e.DecodeParams(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// Decode parses and validates a JSON-RPC 2.0 envelope.
+//
+// Example:
+//
+//	env, err := protocol.Decode(data)
+//	if err != nil {
+//	    return err
+//	}
+//	if env.Method == protocol.MethodToolsCall {
+//	    var params protocol.CallToolParams
+//	    if err := env.DecodeParams(&params); err != nil {
+//	        return err
+//	    }
+//	}
+func Decode(data []byte) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	if err := env.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	return &env, nil
+}
+
+func (e *Envelope) validate() error {
+	if e.JSONRPC != JSONRPCVersion {
+		return fmt.Errorf("jsonrpc must be %q", JSONRPCVersion)
+	}
+	if e.Method != "" {
+		if e.Result != nil || e.Error != nil {
+			return errors.New("request must not carry result or error")
+		}
+		return nil
+	}
+	if (e.Result != nil) == (e.Error != nil) {
+		if e.ID == nil && e.Result == nil {
+			return errors.New("message must have a method or an id")
+		}
+		return errors.New("response must carry exactly one of result or error")
+	}
+	// Parse error and invalid request responses carry "id": null
+	if e.ID == nil && e.Error == nil {
+		return errors.New("result response must have an id")
+	}
+	return nil
+}
+
+// NewRequest creates a request envelope
+func NewRequest(id ID, method Method, params interface{}) (*Envelope, error) {
+	raw, err := marshalRaw(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{JSONRPC: JSONRPCVersion, ID: &id, Method: method, Params: raw}, nil
+}
+
+// NewNotification creates a notification envelope
+func NewNotification(method Method, params interface{}) (*Envelope, error) {
+	raw, err := marshalRaw(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{JSONRPC: JSONRPCVersion, Method: method, Params: raw}, nil
+}
+
+// NewResult creates a successful response envelope. A nil result is sent as
+// an empty object.
+func NewResult(id ID, result interface{}) (*Envelope, error) {
+	raw, err := marshalRaw(result)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = json.RawMessage("{}")
+	}
+	return &Envelope{JSONRPC: JSONRPCVersion, ID: &id, Result: raw}, nil
+}
+
+// NewErrorResponse creates an error response envelope
+func NewErrorResponse(id ID, rpcErr *Error) (*Envelope, error) {
+	if rpcErr == nil {
+		return nil, errors.New("error response requires an error")
+	}
+	return &Envelope{JSONRPC: JSONRPCVersion, ID: &id, Error: rpcErr}, nil
+}
+
+// EncodeRequest encodes a request envelope
+func EncodeRequest(id ID, method Method, params interface{}) ([]byte, error) {
+	return encode(NewRequest(id, method, params))
+}
+
+// EncodeNotification encodes a notification envelope
+func EncodeNotification(method Method, params interface{}) ([]byte, error) {
+	return encode(NewNotification(method, params))
+}
+
+// EncodeResult encodes a successful response envelope. A nil result is
+// encoded as an empty object.
+func EncodeResult(id ID, result interface{}) ([]byte, error) {
+	return encode(NewResult(id, result))
+}
+
+// EncodeError encodes an error response envelope
+func EncodeError(id ID, rpcErr *Error) ([]byte, error) {
+	return encode(NewErrorResponse(id, rpcErr))
+}
+
+func encode(env *Envelope, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(env)
+}
+
+// marshalRaw encodes v, treating nil and JSON null as absent
+func marshalRaw(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	return data, nil
+}
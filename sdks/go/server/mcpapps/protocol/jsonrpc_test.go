@@ -0,0 +1,320 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestID_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ID
+		json string
+		str  string
+	}{
+		{name: "number", id: NumberID(42), json: `42`, str: "42"},
+		{name: "string", id: StringID("req-1"), json: `"req-1"`, str: "req-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.id)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(data))
+			assert.Equal(t, tt.str, tt.id.String())
+
+			var decoded ID
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.id, decoded)
+		})
+	}
+
+	var id ID
+	assert.Error(t, json.Unmarshal([]byte(`1.5`), &id))
+	assert.Error(t, json.Unmarshal([]byte(`{}`), &id))
+}
+
+func TestEncodeRequest(t *testing.T) {
+	data, err := EncodeRequest(NumberID(1), MethodInitialize, &InitializeParams{
+		AppInfo:         mcpuiserver.AppInfo{Name: "mcp-ui-adapter", Version: "1.0.0"},
+		AppCapabilities: map[string]interface{}{},
+		ProtocolVersion: ProtocolVersion,
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "ui/initialize",
+		"params": {
+			"appInfo": {"name": "mcp-ui-adapter", "version": "1.0.0"},
+			"appCapabilities": {},
+			"protocolVersion": "2025-11-21"
+		}
+	}`, string(data))
+}
+
+func TestEncodeNotification(t *testing.T) {
+	height := 240
+	data, err := EncodeNotification(MethodSizeChanged, &SizeChangedParams{Height: &height})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"ui/notifications/size-changed","params":{"height":240}}`, string(data))
+
+	data, err = EncodeNotification(MethodInitialized, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"ui/notifications/initialized"}`, string(data))
+}
+
+func TestEncodeResponse(t *testing.T) {
+	data, err := EncodeResult(NumberID(3), nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"result":{}}`, string(data))
+
+	data, err = EncodeResult(StringID("a"), &OpenLinkResult{IsError: true})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"a","result":{"isError":true}}`, string(data))
+
+	data, err = EncodeError(NumberID(4), &Error{Code: CodeMethodNotFound, Message: "nope"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":4,"error":{"code":-32601,"message":"nope"}}`, string(data))
+
+	_, err = EncodeError(NumberID(4), nil)
+	assert.Error(t, err)
+
+	data, err = json.Marshal(&Envelope{JSONRPC: JSONRPCVersion, Error: &Error{Code: CodeParseError, Message: "Parse error"}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`, string(data))
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantKind Kind
+		wantID   string
+	}{
+		{
+			name:     "request",
+			data:     `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"x"}}`,
+			wantKind: KindRequest,
+			wantID:   "7",
+		},
+		{
+			name:     "notification",
+			data:     `{"jsonrpc":"2.0","method":"ui/notifications/tool-input","params":{"arguments":{}}}`,
+			wantKind: KindNotification,
+		},
+		{
+			name:     "result",
+			data:     `{"jsonrpc":"2.0","id":"x","result":{}}`,
+			wantKind: KindResponse,
+			wantID:   "x",
+		},
+		{
+			name:     "null result",
+			data:     `{"jsonrpc":"2.0","id":1,"result":null}`,
+			wantKind: KindResponse,
+			wantID:   "1",
+		},
+		{
+			name:     "error",
+			data:     `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"boom"}}`,
+			wantKind: KindResponse,
+			wantID:   "1",
+		},
+		{
+			name:     "parse error with null id",
+			data:     `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`,
+			wantKind: KindResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := Decode([]byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantKind, env.Kind())
+			if tt.wantID == "" {
+				assert.Nil(t, env.ID)
+			} else {
+				assert.Equal(t, tt.wantID, env.ID.String())
+			}
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not JSON", data: `nope`},
+		{name: "missing version", data: `{"method":"ui/message"}`},
+		{name: "wrong version", data: `{"jsonrpc":"1.0","method":"ui/message"}`},
+		{name: "no method or id", data: `{"jsonrpc":"2.0"}`},
+		{name: "request with result", data: `{"jsonrpc":"2.0","id":1,"method":"ui/message","result":{}}`},
+		{name: "response without result", data: `{"jsonrpc":"2.0","id":1}`},
+		{name: "response with both", data: `{"jsonrpc":"2.0","id":1,"result":{},"error":{"code":1,"message":"x"}}`},
+		{name: "result with null id", data: `{"jsonrpc":"2.0","id":null,"result":{}}`},
+		{name: "fractional id", data: `{"jsonrpc":"2.0","id":1.5,"result":{}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := Decode([]byte(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidEnvelope)
+			assert.Nil(t, env)
+		})
+	}
+}
+
+func TestEnvelope_TypedParams(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want interface{}
+	}{
+		{
+			name: "tool input",
+			data: `{"jsonrpc":"2.0","method":"ui/notifications/tool-input","params":{"arguments":{"city":"Paris"}}}`,
+			want: &ToolInputParams{Arguments: map[string]interface{}{"city": "Paris"}},
+		},
+		{
+			name: "tool result",
+			data: `{"jsonrpc":"2.0","method":"ui/notifications/tool-result","params":{"content":[{"type":"text","text":"22C"}],"structuredContent":{"temp":22}}}`,
+			want: &ToolResultParams{
+				Content:           []ContentBlock{TextContent("22C")},
+				StructuredContent: map[string]interface{}{"temp": float64(22)},
+			},
+		},
+		{
+			name: "host context changed",
+			data: `{"jsonrpc":"2.0","method":"ui/notifications/host-context-changed","params":{"theme":"dark","viewport":{"maxHeight":600}}}`,
+			want: &HostContextChangedParams{Theme: "dark", Viewport: &Viewport{MaxHeight: intPtr(600)}},
+		},
+		{
+			name: "fractional viewport",
+			data: `{"jsonrpc":"2.0","method":"ui/notifications/host-context-changed","params":{"viewport":{"width":412.5,"height":914.4,"maxHeight":599.6}}}`,
+			want: &HostContextChangedParams{Viewport: &Viewport{Width: intPtr(413), Height: intPtr(914), MaxHeight: intPtr(600)}},
+		},
+		{
+			name: "fractional size",
+			data: `{"jsonrpc":"2.0","method":"ui/notifications/size-changed","params":{"width":320.25,"height":480.75}}`,
+			want: &SizeChangedParams{Width: intPtr(320), Height: intPtr(481)},
+		},
+		{
+			name: "teardown",
+			data: `{"jsonrpc":"2.0","id":9,"method":"ui/resource-teardown","params":{"reason":"closed"}}`,
+			want: &ResourceTeardownParams{Reason: "closed"},
+		},
+		{
+			name: "message",
+			data: `{"jsonrpc":"2.0","id":2,"method":"ui/message","params":{"role":"user","content":[{"type":"text","text":"hi"}]}}`,
+			want: &MessageParams{Role: RoleUser, Content: []ContentBlock{TextContent("hi")}},
+		},
+		{
+			name: "initialized without params",
+			data: `{"jsonrpc":"2.0","method":"ui/notifications/initialized"}`,
+			want: &InitializedParams{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := Decode([]byte(tt.data))
+			assert.NoError(t, err)
+
+			params, err := env.TypedParams()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, params)
+		})
+	}
+}
+
+func TestEnvelope_TypedParams_Errors(t *testing.T) {
+	env, err := Decode([]byte(`{"jsonrpc":"2.0","method":"ui/custom"}`))
+	assert.NoError(t, err)
+	_, err = env.TypedParams()
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+
+	env, err = Decode([]byte(`{"jsonrpc":"2.0","method":"ui/open-link","id":1,"params":{"url":42}}`))
+	assert.NoError(t, err)
+	_, err = env.TypedParams()
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+
+	env, err = Decode([]byte(`{"jsonrpc":"2.0","method":"ui/notifications/size-changed","params":{"height":1e12}}`))
+	assert.NoError(t, err)
+	_, err = env.TypedParams()
+	assert.ErrorIs(t, err, ErrInvalidEnvelope, "out of range pixel values are rejected")
+}
+
+func TestEnvelope_DecodeResult(t *testing.T) {
+	env, err := Decode([]byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"result": {
+			"protocolVersion": "2025-11-21",
+			"hostCapabilities": {"openLinks": {}},
+			"hostContext": {"theme": "light", "displayMode": "inline", "locale": "en-US"}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	var result InitializeResult
+	assert.NoError(t, env.DecodeResult(&result))
+	assert.Equal(t, InitializeResult{
+		ProtocolVersion:  ProtocolVersion,
+		HostCapabilities: map[string]interface{}{"openLinks": map[string]interface{}{}},
+		HostContext: &HostContext{
+			Theme:       "light",
+			DisplayMode: mcpuiserver.DisplayModeInline,
+			Locale:      "en-US",
+		},
+	}, result)
+}
+
+func TestError_ProtocolError(t *testing.T) {
+	tests := []struct {
+		name    string
+		rpcErr  *Error
+		want    *mcpuiserver.ProtocolError
+		rpcBack int
+	}{
+		{
+			name:    "method not found",
+			rpcErr:  &Error{Code: CodeMethodNotFound, Message: "no such method"},
+			want:    mcpuiserver.NewUnsupportedError("no such method"),
+			rpcBack: CodeMethodNotFound,
+		},
+		{
+			name:    "denied with data",
+			rpcErr:  &Error{Code: CodeDenied, Message: "blocked", Data: "policy"},
+			want:    mcpuiserver.NewProtocolError(mcpuiserver.ErrorCodeDenied, "blocked", "policy"),
+			rpcBack: CodeDenied,
+		},
+		{
+			name:    "parse error",
+			rpcErr:  &Error{Code: CodeParseError, Message: "bad json"},
+			want:    mcpuiserver.NewHostError("bad json"),
+			rpcBack: CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rpcErr.ProtocolError()
+			assert.Equal(t, tt.want, got)
+
+			back := ErrorFromProtocolError(got)
+			assert.Equal(t, tt.rpcBack, back.Code)
+			assert.Equal(t, tt.rpcErr.Message, back.Message)
+			assert.Equal(t, tt.rpcErr.Data, back.Data)
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,130 @@
+// Package protocol provides a typed Go model of the MCP Apps JSON-RPC protocol
+// spoken between an app's iframe and its host: method constants, the request,
+// notification and result structs for each method, and JSON-RPC 2.0 envelope
+// encoding and decoding. Go hosts and test fakes can use it to talk to apps
+// wrapped with the MCP Apps adapter.
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+)
+
+// ProtocolVersion is the MCP Apps protocol version modelled by this package
+const ProtocolVersion = mcpuiserver.ProtocolVersion
+
+// ErrUnsupportedVersion is returned for protocol versions this package does not model
+var ErrUnsupportedVersion = errors.New("unsupported MCP Apps protocol version")
+
+// Method is an MCP Apps JSON-RPC method name
+type Method string
+
+// Methods defined by ProtocolVersion
+const (
+	// App to host
+	MethodInitialize           Method = "ui/initialize"
+	MethodInitialized          Method = "ui/notifications/initialized"
+	MethodSizeChanged          Method = "ui/notifications/size-changed"
+	MethodOpenLink             Method = "ui/open-link"
+	MethodMessage              Method = "ui/message"
+	MethodToolsCall            Method = "tools/call"
+	MethodNotificationsMessage Method = "notifications/message"
+
+	// Host to app
+	MethodToolInput          Method = "ui/notifications/tool-input"
+	MethodToolInputPartial   Method = "ui/notifications/tool-input-partial"
+	MethodToolResult         Method = "ui/notifications/tool-result"
+	MethodToolCancelled      Method = "ui/notifications/tool-cancelled"
+	MethodHostContextChanged Method = "ui/notifications/host-context-changed"
+	MethodResourceTeardown   Method = "ui/resource-teardown"
+)
+
+// methodSpec describes a method: how it is called and the types of its
+// params and result
+type methodSpec struct {
+	kind      Kind
+	newParams func() interface{}
+	newResult func() interface{}
+}
+
+// methodsByVersion lists the methods of every supported protocol version
+var methodsByVersion = map[string]map[Method]methodSpec{
+	ProtocolVersion: {
+		MethodInitialize:           request(func() interface{} { return &InitializeParams{} }, func() interface{} { return &InitializeResult{} }),
+		MethodInitialized:          notification(func() interface{} { return &InitializedParams{} }),
+		MethodSizeChanged:          notification(func() interface{} { return &SizeChangedParams{} }),
+		MethodOpenLink:             request(func() interface{} { return &OpenLinkParams{} }, func() interface{} { return &OpenLinkResult{} }),
+		MethodMessage:              request(func() interface{} { return &MessageParams{} }, func() interface{} { return &MessageResult{} }),
+		MethodToolsCall:            request(func() interface{} { return &CallToolParams{} }, func() interface{} { return &CallToolResult{} }),
+		MethodNotificationsMessage: notification(func() interface{} { return &LoggingMessageParams{} }),
+		MethodToolInput:            notification(func() interface{} { return &ToolInputParams{} }),
+		MethodToolInputPartial:     notification(func() interface{} { return &ToolInputPartialParams{} }),
+		MethodToolResult:           notification(func() interface{} { return &ToolResultParams{} }),
+		MethodToolCancelled:        notification(func() interface{} { return &ToolCancelledParams{} }),
+		MethodHostContextChanged:   notification(func() interface{} { return &HostContextChangedParams{} }),
+		MethodResourceTeardown:     request(func() interface{} { return &ResourceTeardownParams{} }, func() interface{} { return &ResourceTeardownResult{} }),
+	},
+}
+
+func request(newParams, newResult func() interface{}) methodSpec {
+	return methodSpec{kind: KindRequest, newParams: newParams, newResult: newResult}
+}
+
+func notification(newParams func() interface{}) methodSpec {
+	return methodSpec{kind: KindNotification, newParams: newParams}
+}
+
+// SupportedVersions returns the protocol versions modelled by this package
+func SupportedVersions() []string {
+	return []string{ProtocolVersion}
+}
+
+// MethodsForVersion returns the methods defined by a protocol version
+func MethodsForVersion(version string) ([]Method, error) {
+	specs, ok := methodsByVersion[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, version)
+	}
+	methods := make([]Method, 0, len(specs))
+	for m := range specs {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i] < methods[j] })
+	return methods, nil
+}
+
+// Kind returns how the method is called in ProtocolVersion; ok is false for
+// methods outside the protocol
+func (m Method) Kind() (kind Kind, ok bool) {
+	spec, ok := methodsByVersion[ProtocolVersion][m]
+	return spec.kind, ok
+}
+
+// IsKnown reports whether the method is defined by ProtocolVersion
+func (m Method) IsKnown() bool {
+	_, ok := methodsByVersion[ProtocolVersion][m]
+	return ok
+}
+
+// NewParams returns a pointer to a zero params struct for the method, or nil
+// for methods outside the protocol
+func (m Method) NewParams() interface{} {
+	spec, ok := methodsByVersion[ProtocolVersion][m]
+	if !ok {
+		return nil
+	}
+	return spec.newParams()
+}
+
+// NewResult returns a pointer to a zero result struct for the method, or nil
+// for notifications and methods outside the protocol
+func (m Method) NewResult() interface{} {
+	spec, ok := methodsByVersion[ProtocolVersion][m]
+	if !ok || spec.newResult == nil {
+		return nil
+	}
+	return spec.newResult()
+}
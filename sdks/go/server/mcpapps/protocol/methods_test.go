@@ -0,0 +1,76 @@
+package protocol
+
+import (
+	"testing"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocolVersion(t *testing.T) {
+	assert.Equal(t, mcpuiserver.ProtocolVersion, ProtocolVersion)
+	assert.Equal(t, []string{ProtocolVersion}, SupportedVersions())
+}
+
+func TestMethodsForVersion(t *testing.T) {
+	methods, err := MethodsForVersion(ProtocolVersion)
+	assert.NoError(t, err)
+	assert.Equal(t, []Method{
+		MethodNotificationsMessage,
+		MethodToolsCall,
+		MethodInitialize,
+		MethodMessage,
+		MethodHostContextChanged,
+		MethodInitialized,
+		MethodSizeChanged,
+		MethodToolCancelled,
+		MethodToolInput,
+		MethodToolInputPartial,
+		MethodToolResult,
+		MethodOpenLink,
+		MethodResourceTeardown,
+	}, methods)
+
+	_, err = MethodsForVersion("2024-01-01")
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestMethod_Kind(t *testing.T) {
+	tests := []struct {
+		method     Method
+		want       Kind
+		wantResult bool
+	}{
+		{method: MethodInitialize, want: KindRequest, wantResult: true},
+		{method: MethodOpenLink, want: KindRequest, wantResult: true},
+		{method: MethodMessage, want: KindRequest, wantResult: true},
+		{method: MethodToolsCall, want: KindRequest, wantResult: true},
+		{method: MethodResourceTeardown, want: KindRequest, wantResult: true},
+		{method: MethodInitialized, want: KindNotification},
+		{method: MethodSizeChanged, want: KindNotification},
+		{method: MethodNotificationsMessage, want: KindNotification},
+		{method: MethodToolInput, want: KindNotification},
+		{method: MethodToolInputPartial, want: KindNotification},
+		{method: MethodToolResult, want: KindNotification},
+		{method: MethodToolCancelled, want: KindNotification},
+		{method: MethodHostContextChanged, want: KindNotification},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			kind, ok := tt.method.Kind()
+			assert.True(t, ok)
+			assert.True(t, tt.method.IsKnown())
+			assert.Equal(t, tt.want, kind)
+			assert.NotNil(t, tt.method.NewParams())
+			assert.Equal(t, tt.wantResult, tt.method.NewResult() != nil)
+		})
+	}
+
+	unknown := Method("ui/custom")
+	_, ok := unknown.Kind()
+	assert.False(t, ok)
+	assert.False(t, unknown.IsKnown())
+	assert.Nil(t, unknown.NewParams())
+	assert.Nil(t, unknown.NewResult())
+}
@@ -0,0 +1,485 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+)
+
+// Translator errors
+var (
+	ErrUnknownRequest     = errors.New("no pending request for JSON-RPC id")
+	ErrInitializeRejected = errors.New("host rejected ui/initialize")
+)
+
+// pending request kinds
+const (
+	pendingInit        = "init"
+	pendingTool        = "tool"
+	pendingLink        = "link"
+	pendingPrompt      = "prompt"
+	pendingIntent      = "intent"
+	pendingRequestData = "request-data"
+)
+
+// Output collects the messages produced by one translation step
+type Output struct {
+	// Host holds JSON-RPC messages to send to the MCP Apps host
+	Host []*Envelope
+	// Widget holds MCP-UI messages to deliver to the widget
+	Widget []mcpuiserver.Message
+}
+
+// TranslatorOption is a functional option for configuring a Translator
+type TranslatorOption func(*Translator)
+
+// WithAppInfo sets the app identity sent in ui/initialize
+func WithAppInfo(name, version string) TranslatorOption {
+	return func(t *Translator) {
+		t.appInfo = mcpuiserver.AppInfo{Name: name, Version: version}
+	}
+}
+
+// WithAppCapabilities sets the capabilities sent in ui/initialize
+func WithAppCapabilities(capabilities map[string]interface{}) TranslatorOption {
+	return func(t *Translator) {
+		t.appCapabilities = capabilities
+	}
+}
+
+// WithIntentRoutes sets the intent routing table, see mcpuiserver.IntentRoute
+func WithIntentRoutes(routes map[string]mcpuiserver.IntentRoute) TranslatorOption {
+	return func(t *Translator) {
+		t.intentRoutes = routes
+	}
+}
+
+// WithDataProviders sets the tools that answer ui-request-data messages, keyed
+// by request type
+func WithDataProviders(providers map[string]mcpuiserver.DataProvider) TranslatorOption {
+	return func(t *Translator) {
+		t.dataProviders = providers
+	}
+}
+
+// pendingRequest links a JSON-RPC request sent to the host to the MCP-UI
+// message that caused it
+type pendingRequest struct {
+	messageID string
+	kind      string
+}
+
+// Translator converts between MCP-UI widget messages and the MCP Apps
+// JSON-RPC protocol, the same way the MCP Apps adapter runtime does in the
+// browser. A Go bridge can use it to connect an MCP-UI widget to an MCP Apps
+// host without running the JavaScript adapter.
+//
+// The Translator keeps the render data assembled from host notifications and
+// the JSON-RPC requests awaiting a response. It does not do I/O or enforce
+// timeouts; call Expire for requests the host has not answered in time. It is
+// safe for concurrent use.
+type Translator struct {
+	appInfo         mcpuiserver.AppInfo
+	appCapabilities map[string]interface{}
+	intentRoutes    map[string]mcpuiserver.IntentRoute
+	dataProviders   map[string]mcpuiserver.DataProvider
+
+	mu               sync.Mutex
+	counter          int64
+	pending          map[string]pendingRequest
+	renderData       mcpuiserver.RenderData
+	hostCapabilities map[string]interface{}
+}
+
+// NewTranslator creates a new Translator.
+// Default configuration:
+//   - App info: mcp-ui-adapter 1.0.0
+//   - App capabilities: none
+func NewTranslator(opts ...TranslatorOption) *Translator {
+	t := &Translator{
+		appInfo:         mcpuiserver.AppInfo{Name: "mcp-ui-adapter", Version: "1.0.0"},
+		appCapabilities: map[string]interface{}{},
+		pending:         make(map[string]pendingRequest),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Initialize returns the ui/initialize request that starts the session. When
+// the host answers, HandleHostMessage emits the initialized notification, the
+// render data built from the host context and ui-lifecycle-iframe-ready.
+func (t *Translator) Initialize() (*Output, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := &Output{}
+	err := t.sendPending(out, pendingInit, pendingInit, MethodInitialize, &InitializeParams{
+		AppInfo:         t.appInfo,
+		AppCapabilities: t.appCapabilities,
+		ProtocolVersion: ProtocolVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RenderData returns the render data assembled from host notifications
+func (t *Translator) RenderData() mcpuiserver.RenderData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.renderData
+}
+
+// HostCapabilities returns the capabilities from the host's ui/initialize
+// result, or nil before initialization
+func (t *Translator) HostCapabilities() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.hostCapabilities
+}
+
+// Pending returns the number of requests awaiting a host response
+func (t *Translator) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// HandleWidgetMessage translates a message sent by the widget. Every message
+// is acknowledged with ui-message-received; actions become JSON-RPC requests
+// or notifications for the host.
+func (t *Translator) HandleWidgetMessage(msg mcpuiserver.Message) (*Output, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	messageID := widgetMessageID(msg)
+	if messageID == "" {
+		messageID = t.generateMessageID()
+	}
+
+	out := &Output{}
+	out.Widget = append(out.Widget, mcpuiserver.NewMessageReceivedMessage(messageID, nil))
+
+	var err error
+	switch m := msg.(type) {
+	case *mcpuiserver.UIActionResultToolCallType:
+		err = t.sendPending(out, messageID, pendingTool, MethodToolsCall, &CallToolParams{
+			Name:      m.Payload.ToolName,
+			Arguments: m.Payload.Params,
+		})
+	case *mcpuiserver.MCPUISizeChangeMessage:
+		err = t.notify(out, MethodSizeChanged, &SizeChangedParams{Width: m.Payload.Width, Height: m.Payload.Height})
+	case *mcpuiserver.UIActionResultNotificationType:
+		err = t.notify(out, MethodNotificationsMessage, &LoggingMessageParams{Level: "info", Data: m.Payload.Message})
+	case *mcpuiserver.UIActionResultLinkType:
+		err = t.sendPending(out, messageID, pendingLink, MethodOpenLink, &OpenLinkParams{URL: m.Payload.URL})
+	case *mcpuiserver.UIActionResultPromptType:
+		err = t.sendPending(out, messageID, pendingPrompt, MethodMessage, userMessage(m.Payload.Prompt))
+	case *mcpuiserver.MCPUILifecycleReadyMessage:
+		if err = t.notify(out, MethodInitialized, &InitializedParams{}); err == nil {
+			t.sendRenderData(out, nil)
+		}
+	case *mcpuiserver.MCPUIRequestRenderDataMessage:
+		t.sendRenderData(out, &messageID)
+	case *mcpuiserver.MCPUIRequestDataMessage:
+		provider, ok := t.dataProviders[m.Payload.RequestType]
+		if !ok {
+			t.respond(out, messageID, nil, mcpuiserver.NewUnsupportedError("No data provider registered for request type: "+m.Payload.RequestType))
+			break
+		}
+		err = t.sendPending(out, messageID, pendingRequestData, MethodToolsCall, &CallToolParams{
+			Name:      provider.ToolName,
+			Arguments: mcpuiserver.MapParams(provider.ParamMapping, m.Payload.Params),
+		})
+	case *mcpuiserver.UIActionResultIntentType:
+		if route, ok := mcpuiserver.ResolveIntentRoute(t.intentRoutes, m.Payload.Intent); ok {
+			err = t.handleRoutedIntent(out, messageID, route, m.Payload)
+			break
+		}
+		err = t.sendPending(out, messageID, pendingIntent, MethodMessage,
+			userMessage("Intent: "+m.Payload.Intent+". Parameters: "+stringify(m.Payload.Params)))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// handleRoutedIntent performs the action of a declared intent route
+func (t *Translator) handleRoutedIntent(out *Output, messageID string, route mcpuiserver.IntentRoute, payload mcpuiserver.IntentPayload) error {
+	switch route.Action {
+	case mcpuiserver.IntentActionTool:
+		return t.sendPending(out, messageID, pendingIntent, MethodToolsCall, &CallToolParams{
+			Name:      route.ToolName,
+			Arguments: mcpuiserver.MapParams(route.ParamMapping, payload.Params),
+		})
+	case mcpuiserver.IntentActionPrompt:
+		return t.sendPending(out, messageID, pendingIntent, MethodMessage,
+			userMessage(route.RenderTemplate(payload.Intent, payload.Params)))
+	case mcpuiserver.IntentActionLink:
+		return t.sendPending(out, messageID, pendingIntent, MethodOpenLink,
+			&OpenLinkParams{URL: route.RenderTemplate(payload.Intent, payload.Params)})
+	case mcpuiserver.IntentActionDrop:
+		t.respond(out, messageID, map[string]interface{}{"ignored": true}, nil)
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown action %q", mcpuiserver.ErrInvalidIntentRoute, route.Action)
+	}
+}
+
+// HandleHostMessage translates a JSON-RPC message sent by the host. Tool and
+// host context notifications update the render data, which is sent to the
+// widget as ui-lifecycle-iframe-render-data. Partial tool input, cancellation
+// and teardown are also forwarded as their lifecycle messages. Responses
+// answer the widget's
+// pending request with ui-message-response. Responses to unknown requests
+// return an error wrapping ErrUnknownRequest.
+func (t *Translator) HandleHostMessage(env *Envelope) (*Output, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := &Output{}
+	if env.Kind() == KindResponse {
+		if err := t.handleResponse(out, env); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	switch env.Method {
+	case MethodToolInput:
+		var params ToolInputParams
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		t.renderData.ToolInput = params.Arguments
+		t.sendRenderData(out, nil)
+	case MethodToolInputPartial:
+		var params ToolInputPartialParams
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		t.renderData.ToolInput = params.Arguments
+		out.Widget = append(out.Widget, mcpuiserver.NewToolInputPartialMessage(params.Arguments, nil))
+		t.sendRenderData(out, nil)
+	case MethodToolCancelled:
+		var params ToolCancelledParams
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		out.Widget = append(out.Widget, mcpuiserver.NewToolCancelledMessage(params.Reason, nil))
+	case MethodToolResult:
+		var params interface{}
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		t.renderData.ToolOutput = params
+		t.sendRenderData(out, nil)
+	case MethodHostContextChanged:
+		var params HostContextChangedParams
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		t.applyHostContext(&params)
+		t.sendRenderData(out, nil)
+	case MethodSizeChanged:
+		var params SizeChangedParams
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		if params.Height != nil && *params.Height != 0 {
+			t.renderData.MaxHeight = *params.Height
+		}
+		t.sendRenderData(out, nil)
+	case MethodResourceTeardown:
+		var params ResourceTeardownParams
+		if err := env.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		out.Widget = append(out.Widget, mcpuiserver.NewTeardownMessage(params.Reason, nil))
+		if env.ID != nil {
+			result, err := NewResult(*env.ID, &ResourceTeardownResult{})
+			if err != nil {
+				return nil, err
+			}
+			out.Host = append(out.Host, result)
+		}
+	}
+	return out, nil
+}
+
+// handleResponse resolves the pending request answered by a host response
+func (t *Translator) handleResponse(out *Output, env *Envelope) error {
+	if env.ID == nil {
+		// The host could not read one of our requests and cannot say which
+		return fmt.Errorf("%w: null id: %w", ErrUnknownRequest, env.Error)
+	}
+	key := env.ID.String()
+	req, ok := t.pending[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRequest, key)
+	}
+	delete(t.pending, key)
+
+	if req.kind == pendingInit {
+		if env.Error != nil {
+			return fmt.Errorf("%w: %w", ErrInitializeRejected, env.Error)
+		}
+		var result InitializeResult
+		if err := env.DecodeResult(&result); err != nil {
+			return err
+		}
+		t.hostCapabilities = result.HostCapabilities
+		if err := t.notify(out, MethodInitialized, &InitializedParams{}); err != nil {
+			return err
+		}
+		if result.HostContext != nil {
+			t.applyHostContext(result.HostContext)
+		}
+		t.sendRenderData(out, nil)
+		out.Widget = append(out.Widget, mcpuiserver.NewLifecycleReadyMessage(nil))
+		return nil
+	}
+
+	var response interface{}
+	if err := env.DecodeResult(&response); err != nil {
+		return err
+	}
+	var respErr interface{}
+	if env.Error != nil {
+		respErr = env.Error.ProtocolError()
+	}
+	t.respond(out, req.messageID, response, respErr)
+	return nil
+}
+
+// Expire drops a pending request the host has not answered in time and tells
+// the widget it timed out. An expired ui/initialize proceeds without host
+// context by sending ui-lifecycle-iframe-ready. It reports whether the
+// request was pending.
+func (t *Translator) Expire(id ID) (*Output, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	req, ok := t.pending[id.String()]
+	if !ok {
+		return nil, false
+	}
+	delete(t.pending, id.String())
+
+	out := &Output{}
+	if req.kind == pendingInit {
+		out.Widget = append(out.Widget, mcpuiserver.NewLifecycleReadyMessage(nil))
+	} else {
+		t.respond(out, req.messageID, nil, mcpuiserver.NewTimeoutError("Request timed out"))
+	}
+	return out, true
+}
+
+// applyHostContext copies the set fields of a host context into the render data
+func (t *Translator) applyHostContext(ctx *HostContext) {
+	if ctx.Theme != "" {
+		t.renderData.Theme = ctx.Theme
+	}
+	if ctx.DisplayMode != "" {
+		t.renderData.DisplayMode = ctx.DisplayMode
+	}
+	if ctx.Locale != "" {
+		t.renderData.Locale = ctx.Locale
+	}
+	if ctx.Viewport != nil && ctx.Viewport.MaxHeight != nil && *ctx.Viewport.MaxHeight != 0 {
+		t.renderData.MaxHeight = *ctx.Viewport.MaxHeight
+	}
+}
+
+// sendPending sends a JSON-RPC request and remembers which widget message
+// to answer with its response
+func (t *Translator) sendPending(out *Output, messageID, kind string, method Method, params interface{}) error {
+	t.counter++
+	id := NumberID(t.counter)
+	req, err := NewRequest(id, method, params)
+	if err != nil {
+		return err
+	}
+	t.pending[id.String()] = pendingRequest{messageID: messageID, kind: kind}
+	out.Host = append(out.Host, req)
+	return nil
+}
+
+func (t *Translator) notify(out *Output, method Method, params interface{}) error {
+	n, err := NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	out.Host = append(out.Host, n)
+	return nil
+}
+
+func (t *Translator) respond(out *Output, messageID string, response, err interface{}) {
+	id := messageID
+	out.Widget = append(out.Widget, mcpuiserver.NewMessageResponseMessage(messageID, response, err, &id))
+}
+
+func (t *Translator) sendRenderData(out *Output, messageID *string) {
+	out.Widget = append(out.Widget, mcpuiserver.NewRenderDataMessage(t.renderData, messageID))
+}
+
+// generateMessageID creates an ID for widget messages sent without one
+func (t *Translator) generateMessageID() string {
+	t.counter++
+	return fmt.Sprintf("adapter-%d-%d", time.Now().UnixMilli(), t.counter)
+}
+
+// widgetMessageID returns the message ID carried by a widget message
+func widgetMessageID(msg mcpuiserver.Message) string {
+	var id *string
+	switch m := msg.(type) {
+	case *mcpuiserver.UIActionResultToolCallType:
+		id = m.MessageID
+	case *mcpuiserver.UIActionResultPromptType:
+		id = m.MessageID
+	case *mcpuiserver.UIActionResultLinkType:
+		id = m.MessageID
+	case *mcpuiserver.UIActionResultIntentType:
+		id = m.MessageID
+	case *mcpuiserver.UIActionResultNotificationType:
+		id = m.MessageID
+	case *mcpuiserver.MCPUILifecycleReadyMessage:
+		id = m.MessageID
+	case *mcpuiserver.MCPUISizeChangeMessage:
+		id = m.MessageID
+	case *mcpuiserver.MCPUIRequestDataMessage:
+		return m.MessageID
+	case *mcpuiserver.MCPUIRequestRenderDataMessage:
+		id = m.MessageID
+	}
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
+func userMessage(text string) *MessageParams {
+	return &MessageParams{Role: RoleUser, Content: []ContentBlock{TextContent(text)}}
+}
+
+// stringify encodes v like JSON.stringify
+func stringify(v interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,381 @@
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// toJSON encodes a translated message so tests can compare wire formats
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func parseWidget(t *testing.T, data string) mcpuiserver.Message {
+	t.Helper()
+	msg, err := mcpuiserver.ParseMessage([]byte(data))
+	assert.NoError(t, err)
+	return msg
+}
+
+func decodeHost(t *testing.T, data string) *Envelope {
+	t.Helper()
+	env, err := Decode([]byte(data))
+	assert.NoError(t, err)
+	return env
+}
+
+func TestTranslator_Initialize(t *testing.T) {
+	tr := NewTranslator(WithAppInfo("weather", "2.0.0"), WithAppCapabilities(map[string]interface{}{"tools": true}))
+
+	out, err := tr.Initialize()
+	assert.NoError(t, err)
+	assert.Empty(t, out.Widget)
+	assert.Len(t, out.Host, 1)
+	assert.JSONEq(t, `{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "ui/initialize",
+		"params": {
+			"appInfo": {"name": "weather", "version": "2.0.0"},
+			"appCapabilities": {"tools": true},
+			"protocolVersion": "2025-11-21"
+		}
+	}`, toJSON(t, out.Host[0]))
+
+	out, err = tr.HandleHostMessage(decodeHost(t, `{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"result": {
+			"hostCapabilities": {"openLinks": {}},
+			"hostContext": {"theme": "dark", "locale": "fr-FR", "viewport": {"maxHeight": 500}}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	assert.Len(t, out.Host, 1)
+	assert.Equal(t, MethodInitialized, out.Host[0].Method)
+
+	assert.Len(t, out.Widget, 2)
+	assert.JSONEq(t, `{
+		"type": "ui-lifecycle-iframe-render-data",
+		"payload": {"renderData": {"theme": "dark", "locale": "fr-FR", "maxHeight": 500}}
+	}`, toJSON(t, out.Widget[0]))
+	assert.Equal(t, mcpuiserver.MessageTypeLifecycleReady, out.Widget[1].MessageType())
+
+	assert.Equal(t, map[string]interface{}{"openLinks": map[string]interface{}{}}, tr.HostCapabilities())
+	assert.Equal(t, 0, tr.Pending())
+}
+
+func TestTranslator_InitializeRejected(t *testing.T) {
+	tr := NewTranslator()
+	_, err := tr.Initialize()
+	assert.NoError(t, err)
+
+	_, err = tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"no"}}`))
+	assert.ErrorIs(t, err, ErrInitializeRejected)
+
+	var rpcErr *Error
+	assert.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, CodeInternalError, rpcErr.Code)
+}
+
+func TestTranslator_WidgetMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		widget   string
+		wantHost string
+	}{
+		{
+			name:     "tool",
+			widget:   `{"type":"tool","messageId":"m1","payload":{"toolName":"getWeather","params":{"city":"Paris"}}}`,
+			wantHost: `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"getWeather","arguments":{"city":"Paris"}}}`,
+		},
+		{
+			name:     "prompt",
+			widget:   `{"type":"prompt","messageId":"m1","payload":{"prompt":"Hello"}}`,
+			wantHost: `{"jsonrpc":"2.0","id":1,"method":"ui/message","params":{"role":"user","content":[{"type":"text","text":"Hello"}]}}`,
+		},
+		{
+			name:     "link",
+			widget:   `{"type":"link","messageId":"m1","payload":{"url":"https://example.com"}}`,
+			wantHost: `{"jsonrpc":"2.0","id":1,"method":"ui/open-link","params":{"url":"https://example.com"}}`,
+		},
+		{
+			name:     "notify",
+			widget:   `{"type":"notify","messageId":"m1","payload":{"message":"Saved"}}`,
+			wantHost: `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"Saved"}}`,
+		},
+		{
+			name:     "size change",
+			widget:   `{"type":"ui-size-change","messageId":"m1","payload":{"height":320}}`,
+			wantHost: `{"jsonrpc":"2.0","method":"ui/notifications/size-changed","params":{"height":320}}`,
+		},
+		{
+			name:     "unrouted intent",
+			widget:   `{"type":"intent","messageId":"m1","payload":{"intent":"book","params":{"seat":"4A"}}}`,
+			wantHost: `{"jsonrpc":"2.0","id":1,"method":"ui/message","params":{"role":"user","content":[{"type":"text","text":"Intent: book. Parameters: {\"seat\":\"4A\"}"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTranslator()
+			out, err := tr.HandleWidgetMessage(parseWidget(t, tt.widget))
+			assert.NoError(t, err)
+
+			assert.Len(t, out.Widget, 1)
+			assert.JSONEq(t, `{"type":"ui-message-received","payload":{"messageId":"m1"}}`, toJSON(t, out.Widget[0]))
+			assert.Len(t, out.Host, 1)
+			assert.JSONEq(t, tt.wantHost, toJSON(t, out.Host[0]))
+		})
+	}
+}
+
+func TestTranslator_ResponseRoundTrip(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleWidgetMessage(parseWidget(t, `{"type":"tool","messageId":"m1","payload":{"toolName":"x"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tr.Pending())
+
+	out, err = tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","id":`+out.Host[0].ID.String()+`,"result":{"content":[]}}`))
+	assert.NoError(t, err)
+	assert.Empty(t, out.Host)
+	assert.Len(t, out.Widget, 1)
+	assert.JSONEq(t, `{
+		"type": "ui-message-response",
+		"messageId": "m1",
+		"payload": {"messageId": "m1", "response": {"content": []}}
+	}`, toJSON(t, out.Widget[0]))
+	assert.Equal(t, 0, tr.Pending())
+
+	_, err = tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","id":1,"result":{}}`))
+	assert.ErrorIs(t, err, ErrUnknownRequest)
+}
+
+func TestTranslator_ErrorResponse(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleWidgetMessage(parseWidget(t, `{"type":"link","messageId":"m1","payload":{"url":"https://example.com"}}`))
+	assert.NoError(t, err)
+
+	out, err = tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","id":`+out.Host[0].ID.String()+`,"error":{"code":-32002,"message":"denied","data":{"reason":"policy"}}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "ui-message-response",
+		"messageId": "m1",
+		"payload": {"messageId": "m1", "error": {"code": "denied", "message": "denied", "data": {"reason": "policy"}}}
+	}`, toJSON(t, out.Widget[0]))
+}
+
+func TestTranslator_NullIDErrorResponse(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request"}}`))
+	assert.ErrorIs(t, err, ErrUnknownRequest)
+	assert.Nil(t, out)
+
+	var rpcErr *Error
+	assert.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, CodeInvalidRequest, rpcErr.Code)
+}
+
+func TestTranslator_GeneratesMessageIDs(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleWidgetMessage(parseWidget(t, `{"type":"prompt","payload":{"prompt":"hi"}}`))
+	assert.NoError(t, err)
+
+	ack := out.Widget[0].(*mcpuiserver.MCPUIMessageReceivedMessage)
+	assert.True(t, strings.HasPrefix(ack.Payload.MessageID, "adapter-"))
+}
+
+func TestTranslator_Lifecycle(t *testing.T) {
+	tr := NewTranslator()
+
+	out, err := tr.HandleWidgetMessage(parseWidget(t, `{"type":"ui-lifecycle-iframe-ready"}`))
+	assert.NoError(t, err)
+	assert.Len(t, out.Host, 1)
+	assert.Equal(t, MethodInitialized, out.Host[0].Method)
+	assert.Len(t, out.Widget, 2)
+	assert.Equal(t, mcpuiserver.MessageTypeLifecycleRenderData, out.Widget[1].MessageType())
+
+	out, err = tr.HandleWidgetMessage(parseWidget(t, `{"type":"ui-request-render-data","messageId":"r1"}`))
+	assert.NoError(t, err)
+	assert.Empty(t, out.Host)
+	assert.Len(t, out.Widget, 2)
+	assert.JSONEq(t, `{"type":"ui-lifecycle-iframe-render-data","messageId":"r1","payload":{"renderData":{}}}`, toJSON(t, out.Widget[1]))
+}
+
+func TestTranslator_HostNotifications(t *testing.T) {
+	tr := NewTranslator()
+
+	steps := []struct {
+		host string
+		want mcpuiserver.RenderData
+	}{
+		{
+			host: `{"jsonrpc":"2.0","method":"ui/notifications/tool-input-partial","params":{"arguments":{"city":"Par"}}}`,
+			want: mcpuiserver.RenderData{ToolInput: map[string]interface{}{"city": "Par"}},
+		},
+		{
+			host: `{"jsonrpc":"2.0","method":"ui/notifications/tool-input","params":{"arguments":{"city":"Paris"}}}`,
+			want: mcpuiserver.RenderData{ToolInput: map[string]interface{}{"city": "Paris"}},
+		},
+		{
+			host: `{"jsonrpc":"2.0","method":"ui/notifications/tool-result","params":{"structuredContent":{"temp":21}}}`,
+			want: mcpuiserver.RenderData{
+				ToolInput:  map[string]interface{}{"city": "Paris"},
+				ToolOutput: map[string]interface{}{"structuredContent": map[string]interface{}{"temp": float64(21)}},
+			},
+		},
+		{
+			host: `{"jsonrpc":"2.0","method":"ui/notifications/host-context-changed","params":{"theme":"light","displayMode":"fullscreen"}}`,
+			want: mcpuiserver.RenderData{
+				ToolInput:   map[string]interface{}{"city": "Paris"},
+				ToolOutput:  map[string]interface{}{"structuredContent": map[string]interface{}{"temp": float64(21)}},
+				Theme:       "light",
+				DisplayMode: mcpuiserver.DisplayModeFullscreen,
+			},
+		},
+		{
+			host: `{"jsonrpc":"2.0","method":"ui/notifications/size-changed","params":{"width":100,"height":700}}`,
+			want: mcpuiserver.RenderData{
+				ToolInput:   map[string]interface{}{"city": "Paris"},
+				ToolOutput:  map[string]interface{}{"structuredContent": map[string]interface{}{"temp": float64(21)}},
+				Theme:       "light",
+				DisplayMode: mcpuiserver.DisplayModeFullscreen,
+				MaxHeight:   700,
+			},
+		},
+	}
+
+	for _, step := range steps {
+		out, err := tr.HandleHostMessage(decodeHost(t, step.host))
+		assert.NoError(t, err)
+		assert.Empty(t, out.Host)
+		assert.NotEmpty(t, out.Widget)
+		assert.Equal(t, mcpuiserver.NewRenderDataMessage(step.want, nil), out.Widget[len(out.Widget)-1])
+		assert.Equal(t, step.want, tr.RenderData())
+	}
+}
+
+func TestTranslator_ResourceTeardown(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","id":"t1","method":"ui/resource-teardown","params":{"reason":"closed"}}`))
+	assert.NoError(t, err)
+	assert.Len(t, out.Host, 1)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"t1","result":{}}`, toJSON(t, out.Host[0]))
+	assert.Equal(t, []mcpuiserver.Message{mcpuiserver.NewTeardownMessage("closed", nil)}, out.Widget)
+}
+
+func TestTranslator_ToolCancelled(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","method":"ui/notifications/tool-cancelled","params":{"reason":"user"}}`))
+	assert.NoError(t, err)
+	assert.Empty(t, out.Host)
+	assert.Equal(t, []mcpuiserver.Message{mcpuiserver.NewToolCancelledMessage("user", nil)}, out.Widget)
+}
+
+func TestTranslator_ToolInputPartial(t *testing.T) {
+	tr := NewTranslator()
+	out, err := tr.HandleHostMessage(decodeHost(t, `{"jsonrpc":"2.0","method":"ui/notifications/tool-input-partial","params":{"arguments":{"city":"Pa"}}}`))
+	assert.NoError(t, err)
+
+	args := map[string]interface{}{"city": "Pa"}
+	assert.Equal(t, []mcpuiserver.Message{
+		mcpuiserver.NewToolInputPartialMessage(args, nil),
+		mcpuiserver.NewRenderDataMessage(mcpuiserver.RenderData{ToolInput: args}, nil),
+	}, out.Widget)
+}
+
+func TestTranslator_Expire(t *testing.T) {
+	tr := NewTranslator()
+	init, err := tr.Initialize()
+	assert.NoError(t, err)
+	call, err := tr.HandleWidgetMessage(parseWidget(t, `{"type":"tool","messageId":"m1","payload":{"toolName":"x"}}`))
+	assert.NoError(t, err)
+
+	out, ok := tr.Expire(*call.Host[0].ID)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{
+		"type": "ui-message-response",
+		"messageId": "m1",
+		"payload": {"messageId": "m1", "error": {"code": "timeout", "message": "Request timed out"}}
+	}`, toJSON(t, out.Widget[0]))
+
+	out, ok = tr.Expire(*init.Host[0].ID)
+	assert.True(t, ok)
+	assert.Equal(t, mcpuiserver.MessageTypeLifecycleReady, out.Widget[0].MessageType())
+
+	_, ok = tr.Expire(NumberID(99))
+	assert.False(t, ok)
+	assert.Equal(t, 0, tr.Pending())
+}
+
+func TestTranslator_DataProviders(t *testing.T) {
+	tr := NewTranslator(WithDataProviders(map[string]mcpuiserver.DataProvider{
+		"userStats": {ToolName: "getUserStats", ParamMapping: map[string]string{"id": "userId"}},
+	}))
+
+	out, err := tr.HandleWidgetMessage(parseWidget(t, `{"type":"ui-request-data","messageId":"d1","payload":{"requestType":"userStats","params":{"userId":"u1"}}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"getUserStats","arguments":{"id":"u1"}}}`, toJSON(t, out.Host[0]))
+
+	out, err = tr.HandleWidgetMessage(parseWidget(t, `{"type":"ui-request-data","messageId":"d2","payload":{"requestType":"other"}}`))
+	assert.NoError(t, err)
+	assert.Empty(t, out.Host)
+	assert.JSONEq(t, `{
+		"type": "ui-message-response",
+		"messageId": "d2",
+		"payload": {"messageId": "d2", "error": {"code": "unsupported", "message": "No data provider registered for request type: other"}}
+	}`, toJSON(t, out.Widget[1]))
+}
+
+func TestTranslator_IntentRoutes(t *testing.T) {
+	tr := NewTranslator(WithIntentRoutes(map[string]mcpuiserver.IntentRoute{
+		"search":                        {Action: mcpuiserver.IntentActionLink, Template: "https://example.com/?q={{q}}"},
+		"ask":                           {Action: mcpuiserver.IntentActionPrompt, Template: "Tell me about {{topic}}"},
+		"settings":                      {Action: mcpuiserver.IntentActionTool, ToolName: "openSettings"},
+		mcpuiserver.IntentRouteWildcard: {Action: mcpuiserver.IntentActionDrop},
+	}))
+
+	tests := []struct {
+		widget     string
+		wantHost   string
+		wantWidget string
+	}{
+		{
+			widget:   `{"type":"intent","messageId":"i1","payload":{"intent":"search","params":{"q":"a b"}}}`,
+			wantHost: `{"jsonrpc":"2.0","id":1,"method":"ui/open-link","params":{"url":"https://example.com/?q=a%20b"}}`,
+		},
+		{
+			widget:   `{"type":"intent","messageId":"i2","payload":{"intent":"ask","params":{"topic":"tides"}}}`,
+			wantHost: `{"jsonrpc":"2.0","id":2,"method":"ui/message","params":{"role":"user","content":[{"type":"text","text":"Tell me about tides"}]}}`,
+		},
+		{
+			widget:   `{"type":"intent","messageId":"i3","payload":{"intent":"settings","params":{"tab":"a"}}}`,
+			wantHost: `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"openSettings","arguments":{"tab":"a"}}}`,
+		},
+		{
+			widget:     `{"type":"intent","messageId":"i4","payload":{"intent":"unknown"}}`,
+			wantWidget: `{"type":"ui-message-response","messageId":"i4","payload":{"messageId":"i4","response":{"ignored":true}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		out, err := tr.HandleWidgetMessage(parseWidget(t, tt.widget))
+		assert.NoError(t, err)
+		if tt.wantHost != "" {
+			assert.Len(t, out.Host, 1)
+			assert.JSONEq(t, tt.wantHost, toJSON(t, out.Host[0]))
+		}
+		if tt.wantWidget != "" {
+			assert.Empty(t, out.Host)
+			assert.JSONEq(t, tt.wantWidget, toJSON(t, out.Widget[1]))
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mcpuiserver "github.com/MCP-UI-Org/mcp-ui/sdks/go/server"
+	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/internal/pixels"
+)
+
+// Role is the author of a ui/message
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// ContentBlock is an MCP content block as carried by tool results and
+// ui/message. Non-text blocks keep their payload in the raw fields.
+type ContentBlock struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	Data     string          `json:"data,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// TextContent creates a text content block
+func TextContent(text string) ContentBlock {
+	return ContentBlock{Type: "text", Text: text}
+}
+
+// Viewport describes the space the host gives the app
+type Viewport struct {
+	Width     *int `json:"width,omitempty"`
+	Height    *int `json:"height,omitempty"`
+	MaxWidth  *int `json:"maxWidth,omitempty"`
+	MaxHeight *int `json:"maxHeight,omitempty"`
+}
+
+// UnmarshalJSON decodes the viewport, rounding fractional CSS pixel values to
+// the nearest pixel
+func (v *Viewport) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Width     *float64 `json:"width,omitempty"`
+		Height    *float64 `json:"height,omitempty"`
+		MaxWidth  *float64 `json:"maxWidth,omitempty"`
+		MaxHeight *float64 `json:"maxHeight,omitempty"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return roundPixelFields([]pixelField{
+		{"width", aux.Width, &v.Width},
+		{"height", aux.Height, &v.Height},
+		{"maxWidth", aux.MaxWidth, &v.MaxWidth},
+		{"maxHeight", aux.MaxHeight, &v.MaxHeight},
+	})
+}
+
+// HostContext describes the environment the app is rendered in
+type HostContext struct {
+	Theme       string                  `json:"theme,omitempty"`
+	DisplayMode mcpuiserver.DisplayMode `json:"displayMode,omitempty"`
+	Locale      string                  `json:"locale,omitempty"`
+	Viewport    *Viewport               `json:"viewport,omitempty"`
+}
+
+// InitializeParams are the params of the ui/initialize request
+type InitializeParams struct {
+	AppInfo         mcpuiserver.AppInfo    `json:"appInfo"`
+	AppCapabilities map[string]interface{} `json:"appCapabilities"`
+	ProtocolVersion string                 `json:"protocolVersion"`
+}
+
+// InitializeResult is the host's answer to ui/initialize
+type InitializeResult struct {
+	ProtocolVersion  string                 `json:"protocolVersion,omitempty"`
+	HostInfo         *mcpuiserver.AppInfo   `json:"hostInfo,omitempty"`
+	HostCapabilities map[string]interface{} `json:"hostCapabilities,omitempty"`
+	HostContext      *HostContext           `json:"hostContext,omitempty"`
+}
+
+// InitializedParams are the params of the ui/notifications/initialized notification
+type InitializedParams struct{}
+
+// SizeChangedParams are the params of the ui/notifications/size-changed
+// notification. The app sends it when its content size changes; the host may
+// send it when the available size changes.
+type SizeChangedParams struct {
+	Width  *int `json:"width,omitempty"`
+	Height *int `json:"height,omitempty"`
+}
+
+// UnmarshalJSON decodes the size, rounding fractional CSS pixel values to the
+// nearest pixel
+func (p *SizeChangedParams) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Width  *float64 `json:"width,omitempty"`
+		Height *float64 `json:"height,omitempty"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return roundPixelFields([]pixelField{
+		{"width", aux.Width, &p.Width},
+		{"height", aux.Height, &p.Height},
+	})
+}
+
+// pixelField pairs a decoded CSS pixel value with the field it is stored in
+type pixelField struct {
+	name  string
+	value *float64
+	dst   **int
+}
+
+// roundPixelFields rounds each present value to the nearest integer and stores
+// it, rejecting values that do not fit a 32-bit int
+func roundPixelFields(fields []pixelField) error {
+	for _, field := range fields {
+		rounded, err := pixels.RoundPtr(field.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+		if rounded != nil {
+			*field.dst = rounded
+		}
+	}
+	return nil
+}
+
+// OpenLinkParams are the params of the ui/open-link request
+type OpenLinkParams struct {
+	URL string `json:"url"`
+}
+
+// OpenLinkResult is the host's answer to ui/open-link
+type OpenLinkResult struct {
+	IsError bool `json:"isError,omitempty"`
+}
+
+// MessageParams are the params of the ui/message request, which adds a
+// message to the conversation
+type MessageParams struct {
+	Role    Role           `json:"role"`
+	Content []ContentBlock `json:"content"`
+}
+
+// MessageResult is the host's answer to ui/message
+type MessageResult struct {
+	IsError bool `json:"isError,omitempty"`
+}
+
+// CallToolParams are the params of the tools/call request
+type CallToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// CallToolResult is the result of a tool call, returned by tools/call and
+// delivered by ui/notifications/tool-result
+type CallToolResult struct {
+	Content           []ContentBlock         `json:"content,omitempty"`
+	StructuredContent interface{}            `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+	Meta              map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// LoggingMessageParams are the params of the notifications/message notification
+type LoggingMessageParams struct {
+	Level  string      `json:"level"`
+	Logger string      `json:"logger,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
+// ToolInputParams are the params of the ui/notifications/tool-input
+// notification, which delivers the complete tool arguments
+type ToolInputParams struct {
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// ToolInputPartialParams are the params of the
+// ui/notifications/tool-input-partial notification, which delivers the
+// arguments streamed so far
+type ToolInputPartialParams struct {
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// ToolResultParams are the params of the ui/notifications/tool-result notification
+type ToolResultParams = CallToolResult
+
+// ToolCancelledParams are the params of the ui/notifications/tool-cancelled notification
+type ToolCancelledParams struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// HostContextChangedParams are the params of the
+// ui/notifications/host-context-changed notification. Only changed fields
+// are set.
+type HostContextChangedParams = HostContext
+
+// ResourceTeardownParams are the params of the ui/resource-teardown request
+type ResourceTeardownParams struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ResourceTeardownResult is the app's answer to ui/resource-teardown
+type ResourceTeardownResult struct{}
@@ -0,0 +1,180 @@
+package mcpuiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ProtocolErrorCode classifies a failed request so widgets can branch on the
+// kind of failure rather than on message text
+type ProtocolErrorCode string
+
+const (
+	// ErrorCodeUnsupported means the host cannot perform the requested action
+	ErrorCodeUnsupported ProtocolErrorCode = "unsupported"
+	// ErrorCodeTimeout means the request was not answered in time
+	ErrorCodeTimeout ProtocolErrorCode = "timeout"
+	// ErrorCodeDenied means the host or user refused the request
+	ErrorCodeDenied ProtocolErrorCode = "denied"
+	// ErrorCodeInvalidParams means the request parameters were rejected
+	ErrorCodeInvalidParams ProtocolErrorCode = "invalid-params"
+	// ErrorCodeHostError means the host failed while handling the request
+	ErrorCodeHostError ProtocolErrorCode = "host-error"
+)
+
+// JSON-RPC error codes used by MCP Apps hosts for each ProtocolErrorCode,
+// along with the standard parse and invalid request codes. Timeout and
+// denied use the implementation-defined server error range.
+const (
+	JSONRPCCodeParseError     = -32700
+	JSONRPCCodeInvalidRequest = -32600
+	JSONRPCCodeMethodNotFound = -32601
+	JSONRPCCodeInvalidParams  = -32602
+	JSONRPCCodeInternalError  = -32603
+	JSONRPCCodeTimeout        = -32001
+	JSONRPCCodeDenied         = -32002
+)
+
+// IsValid reports whether c is one of the standard error codes
+func (c ProtocolErrorCode) IsValid() bool {
+	switch c {
+	case ErrorCodeUnsupported, ErrorCodeTimeout, ErrorCodeDenied, ErrorCodeInvalidParams, ErrorCodeHostError:
+		return true
+	}
+	return false
+}
+
+// JSONRPCCode returns the JSON-RPC error code for c. Unknown codes map to
+// the internal error code.
+func (c ProtocolErrorCode) JSONRPCCode() int {
+	switch c {
+	case ErrorCodeUnsupported:
+		return JSONRPCCodeMethodNotFound
+	case ErrorCodeTimeout:
+		return JSONRPCCodeTimeout
+	case ErrorCodeDenied:
+		return JSONRPCCodeDenied
+	case ErrorCodeInvalidParams:
+		return JSONRPCCodeInvalidParams
+	default:
+		return JSONRPCCodeInternalError
+	}
+}
+
+// ProtocolErrorCodeFromJSONRPC returns the error code for a JSON-RPC error
+// code. Codes without a counterpart map to ErrorCodeHostError.
+func ProtocolErrorCodeFromJSONRPC(code int) ProtocolErrorCode {
+	switch code {
+	case JSONRPCCodeMethodNotFound:
+		return ErrorCodeUnsupported
+	case JSONRPCCodeTimeout:
+		return ErrorCodeTimeout
+	case JSONRPCCodeDenied:
+		return ErrorCodeDenied
+	case JSONRPCCodeInvalidParams:
+		return ErrorCodeInvalidParams
+	default:
+		return ErrorCodeHostError
+	}
+}
+
+// ProtocolError is the error shape carried by MessageResponsePayload.Error.
+// Both adapter runtimes and the Go host helpers report failures this way.
+type ProtocolError struct {
+	Code    ProtocolErrorCode `json:"code"`
+	Message string            `json:"message"`
+	Data    interface{}       `json:"data,omitempty"`
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports whether target is a *ProtocolError with the same code, so
+// errors.Is(err, &ProtocolError{Code: ErrorCodeTimeout}) matches any timeout.
+func (e *ProtocolError) Is(target error) bool {
+	t, ok := target.(*ProtocolError)
+	return ok && t.Code == e.Code
+}
+
+// NewProtocolError creates a protocol error with optional data
+func NewProtocolError(code ProtocolErrorCode, message string, data interface{}) *ProtocolError {
+	return &ProtocolError{Code: code, Message: message, Data: data}
+}
+
+// NewUnsupportedError creates an ErrorCodeUnsupported error
+func NewUnsupportedError(message string) *ProtocolError {
+	return NewProtocolError(ErrorCodeUnsupported, message, nil)
+}
+
+// NewTimeoutError creates an ErrorCodeTimeout error
+func NewTimeoutError(message string) *ProtocolError {
+	return NewProtocolError(ErrorCodeTimeout, message, nil)
+}
+
+// NewDeniedError creates an ErrorCodeDenied error
+func NewDeniedError(message string) *ProtocolError {
+	return NewProtocolError(ErrorCodeDenied, message, nil)
+}
+
+// NewInvalidParamsError creates an ErrorCodeInvalidParams error
+func NewInvalidParamsError(message string) *ProtocolError {
+	return NewProtocolError(ErrorCodeInvalidParams, message, nil)
+}
+
+// NewHostError creates an ErrorCodeHostError error
+func NewHostError(message string) *ProtocolError {
+	return NewProtocolError(ErrorCodeHostError, message, nil)
+}
+
+// AsProtocolError converts err into the error sent to widgets. A
+// *ProtocolError in the chain is returned as-is, timeouts become
+// ErrorCodeTimeout and anything else becomes ErrorCodeHostError. It returns
+// nil for a nil error.
+func AsProtocolError(err error) *ProtocolError {
+	if err == nil {
+		return nil
+	}
+	var protoErr *ProtocolError
+	if errors.As(err, &protoErr) {
+		return protoErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRequestTimeout) {
+		return NewTimeoutError(err.Error())
+	}
+	return NewHostError(err.Error())
+}
+
+// ProtocolError returns the response error as a *ProtocolError, or nil when
+// the response succeeded. Error shapes sent by older runtimes, such as plain
+// strings or {message} objects, become ErrorCodeHostError, except the
+// "Timeout" string, which becomes ErrorCodeTimeout.
+func (p MessageResponsePayload) ProtocolError() *ProtocolError {
+	switch e := p.Error.(type) {
+	case nil:
+		return nil
+	case *ProtocolError:
+		return e
+	case ProtocolError:
+		return &e
+	case error:
+		return AsProtocolError(e)
+	case string:
+		if e == "Timeout" {
+			return NewTimeoutError(e)
+		}
+		return NewHostError(e)
+	case map[string]interface{}:
+		message, _ := e["message"].(string)
+		code := ErrorCodeHostError
+		if c, ok := e["code"].(string); ok && ProtocolErrorCode(c).IsValid() {
+			code = ProtocolErrorCode(c)
+		} else if c, ok := e["code"].(float64); ok {
+			code = ProtocolErrorCodeFromJSONRPC(int(c))
+		}
+		return NewProtocolError(code, message, e["data"])
+	default:
+		return NewHostError(fmt.Sprint(e))
+	}
+}
@@ -0,0 +1,151 @@
+package mcpuiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocolError_Constructors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ProtocolError
+		want ProtocolErrorCode
+	}{
+		{name: "unsupported", err: NewUnsupportedError("no tools"), want: ErrorCodeUnsupported},
+		{name: "timeout", err: NewTimeoutError("too slow"), want: ErrorCodeTimeout},
+		{name: "denied", err: NewDeniedError("blocked"), want: ErrorCodeDenied},
+		{name: "invalid params", err: NewInvalidParamsError("missing url"), want: ErrorCodeInvalidParams},
+		{name: "host error", err: NewHostError("crashed"), want: ErrorCodeHostError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Code)
+			assert.True(t, tt.err.Code.IsValid())
+			assert.Nil(t, tt.err.Data)
+			assert.ErrorIs(t, fmt.Errorf("wrapped: %w", tt.err), &ProtocolError{Code: tt.want})
+		})
+	}
+}
+
+func TestProtocolError_JSON(t *testing.T) {
+	data, err := json.Marshal(NewProtocolError(ErrorCodeDenied, "blocked", map[string]interface{}{"reason": "policy"}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"code":"denied","message":"blocked","data":{"reason":"policy"}}`, string(data))
+
+	data, err = json.Marshal(NewTimeoutError("too slow"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"code":"timeout","message":"too slow"}`, string(data))
+
+	assert.Equal(t, "timeout: too slow", NewTimeoutError("too slow").Error())
+}
+
+func TestProtocolErrorCode_JSONRPC(t *testing.T) {
+	tests := []struct {
+		code    ProtocolErrorCode
+		jsonRPC int
+	}{
+		{code: ErrorCodeUnsupported, jsonRPC: -32601},
+		{code: ErrorCodeInvalidParams, jsonRPC: -32602},
+		{code: ErrorCodeHostError, jsonRPC: -32603},
+		{code: ErrorCodeTimeout, jsonRPC: -32001},
+		{code: ErrorCodeDenied, jsonRPC: -32002},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			assert.Equal(t, tt.jsonRPC, tt.code.JSONRPCCode())
+			assert.Equal(t, tt.code, ProtocolErrorCodeFromJSONRPC(tt.jsonRPC))
+		})
+	}
+
+	assert.False(t, ProtocolErrorCode("boom").IsValid())
+	assert.Equal(t, -32603, ProtocolErrorCode("boom").JSONRPCCode())
+	assert.Equal(t, ErrorCodeHostError, ProtocolErrorCodeFromJSONRPC(-32700))
+	assert.Equal(t, ErrorCodeHostError, ProtocolErrorCodeFromJSONRPC(1))
+}
+
+func TestAsProtocolError(t *testing.T) {
+	denied := NewDeniedError("blocked")
+
+	tests := []struct {
+		name string
+		err  error
+		want *ProtocolError
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "protocol error", err: denied, want: denied},
+		{name: "wrapped protocol error", err: fmt.Errorf("open link: %w", denied), want: denied},
+		{name: "deadline", err: context.DeadlineExceeded, want: NewTimeoutError("context deadline exceeded")},
+		{name: "request timeout", err: &RequestTimeoutError{MessageID: "m"}, want: NewTimeoutError((&RequestTimeoutError{MessageID: "m"}).Error())},
+		{name: "other", err: errors.New("boom"), want: NewHostError("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AsProtocolError(tt.err))
+		})
+	}
+}
+
+func TestMessageResponsePayload_ProtocolError(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *ProtocolError
+	}{
+		{
+			name:  "success",
+			input: `{"messageId":"m","response":{}}`,
+			want:  nil,
+		},
+		{
+			name:  "protocol error",
+			input: `{"messageId":"m","error":{"code":"denied","message":"blocked","data":{"reason":"policy"}}}`,
+			want:  NewProtocolError(ErrorCodeDenied, "blocked", map[string]interface{}{"reason": "policy"}),
+		},
+		{
+			name:  "JSON-RPC code",
+			input: `{"messageId":"m","error":{"code":-32602,"message":"bad url"}}`,
+			want:  NewInvalidParamsError("bad url"),
+		},
+		{
+			name:  "unknown code",
+			input: `{"messageId":"m","error":{"code":"weird","message":"odd"}}`,
+			want:  NewHostError("odd"),
+		},
+		{
+			name:  "legacy message object",
+			input: `{"messageId":"m","error":{"message":"boom","name":"Error"}}`,
+			want:  NewHostError("boom"),
+		},
+		{
+			name:  "legacy timeout string",
+			input: `{"messageId":"m","error":"Timeout"}`,
+			want:  NewTimeoutError("Timeout"),
+		},
+		{
+			name:  "legacy string",
+			input: `{"messageId":"m","error":"boom"}`,
+			want:  NewHostError("boom"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload MessageResponsePayload
+			assert.NoError(t, json.Unmarshal([]byte(tt.input), &payload))
+			assert.Equal(t, tt.want, payload.ProtocolError())
+		})
+	}
+
+	denied := NewDeniedError("blocked")
+	assert.Same(t, denied, MessageResponsePayload{Error: denied}.ProtocolError())
+	assert.Equal(t, denied, MessageResponsePayload{Error: *denied}.ProtocolError())
+	assert.Equal(t, NewHostError("boom"), MessageResponsePayload{Error: errors.New("boom")}.ProtocolError())
+}
@@ -1,6 +1,12 @@
 // Package mcpuiserver provides protocol message types and constructors for MCP-UI communication.
 package mcpuiserver
 
+import (
+	"fmt"
+
+	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/internal/pixels"
+)
+
 // MCPUILifecycleReadyMessage indicates the widget is ready
 type MCPUILifecycleReadyMessage struct {
 	Type      ProtocolMessageType    `json:"type"`
@@ -21,6 +27,33 @@ type SizeChangePayload struct {
 	Height *int `json:"height,omitempty"`
 }
 
+// UnmarshalJSON decodes the dimensions, rounding fractional CSS pixel values
+// to the nearest pixel. Unknown fields are rejected.
+func (p *SizeChangePayload) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Width  *float64 `json:"width,omitempty"`
+		Height *float64 `json:"height,omitempty"`
+	}
+	if err := decodeStrict(data, &aux); err != nil {
+		return err
+	}
+	width, err := pixels.RoundPtr(aux.Width)
+	if err != nil {
+		return fmt.Errorf("width: %w", err)
+	}
+	height, err := pixels.RoundPtr(aux.Height)
+	if err != nil {
+		return fmt.Errorf("height: %w", err)
+	}
+	if width != nil {
+		p.Width = width
+	}
+	if height != nil {
+		p.Height = height
+	}
+	return nil
+}
+
 // MCPUIRequestDataMessage requests data from the host
 type MCPUIRequestDataMessage struct {
 	Type      ProtocolMessageType `json:"type"`
@@ -79,6 +112,44 @@ type MessageResponsePayload struct {
 	Error     interface{} `json:"error,omitempty"`
 }
 
+// MCPUIToolCancelledMessage tells the widget that the tool call it renders was cancelled
+type MCPUIToolCancelledMessage struct {
+	Type      ProtocolMessageType  `json:"type"`
+	MessageID *string              `json:"messageId,omitempty"`
+	Payload   ToolCancelledPayload `json:"payload"`
+}
+
+// ToolCancelledPayload contains the cancellation reason
+type ToolCancelledPayload struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// MCPUITeardownMessage tells the widget it is about to be removed so it can
+// release resources and persist state
+type MCPUITeardownMessage struct {
+	Type      ProtocolMessageType `json:"type"`
+	MessageID *string             `json:"messageId,omitempty"`
+	Payload   TeardownPayload     `json:"payload"`
+}
+
+// TeardownPayload contains the teardown reason
+type TeardownPayload struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// MCPUIToolInputPartialMessage delivers tool arguments while the model is
+// still streaming them
+type MCPUIToolInputPartialMessage struct {
+	Type      ProtocolMessageType     `json:"type"`
+	MessageID *string                 `json:"messageId,omitempty"`
+	Payload   ToolInputPartialPayload `json:"payload"`
+}
+
+// ToolInputPartialPayload contains the arguments received so far
+type ToolInputPartialPayload struct {
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
 // NewLifecycleReadyMessage creates a lifecycle ready message
 func NewLifecycleReadyMessage(messageID *string) *MCPUILifecycleReadyMessage {
 	return &MCPUILifecycleReadyMessage{
@@ -155,3 +226,36 @@ func NewMessageResponseMessage(requestMessageID string, response interface{}, er
 		},
 	}
 }
+
+// NewToolCancelledMessage creates a tool cancelled message
+func NewToolCancelledMessage(reason string, messageID *string) *MCPUIToolCancelledMessage {
+	return &MCPUIToolCancelledMessage{
+		Type:      MessageTypeToolCancelled,
+		MessageID: messageID,
+		Payload: ToolCancelledPayload{
+			Reason: reason,
+		},
+	}
+}
+
+// NewTeardownMessage creates a teardown message
+func NewTeardownMessage(reason string, messageID *string) *MCPUITeardownMessage {
+	return &MCPUITeardownMessage{
+		Type:      MessageTypeTeardown,
+		MessageID: messageID,
+		Payload: TeardownPayload{
+			Reason: reason,
+		},
+	}
+}
+
+// NewToolInputPartialMessage creates a partial tool input message
+func NewToolInputPartialMessage(arguments map[string]interface{}, messageID *string) *MCPUIToolInputPartialMessage {
+	return &MCPUIToolInputPartialMessage{
+		Type:      MessageTypeToolInputPartial,
+		MessageID: messageID,
+		Payload: ToolInputPartialPayload{
+			Arguments: arguments,
+		},
+	}
+}
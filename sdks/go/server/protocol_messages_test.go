@@ -31,6 +31,9 @@ func TestProtocolMessageTypeConstants(t *testing.T) {
 	assert.Equal(t, ProtocolMessageType("ui-lifecycle-iframe-render-data"), MessageTypeLifecycleRenderData)
 	assert.Equal(t, ProtocolMessageType("ui-message-received"), MessageTypeMessageReceived)
 	assert.Equal(t, ProtocolMessageType("ui-message-response"), MessageTypeMessageResponse)
+	assert.Equal(t, ProtocolMessageType("ui-lifecycle-tool-cancelled"), MessageTypeToolCancelled)
+	assert.Equal(t, ProtocolMessageType("ui-lifecycle-teardown"), MessageTypeTeardown)
+	assert.Equal(t, ProtocolMessageType("ui-lifecycle-tool-input-partial"), MessageTypeToolInputPartial)
 }
 
 func TestDisplayModeConstants(t *testing.T) {
@@ -186,6 +189,34 @@ func TestNewMessageResponseMessageWithError(t *testing.T) {
 	assert.Equal(t, err, msg.Payload.Error)
 }
 
+func TestNewToolCancelledMessage(t *testing.T) {
+	msg := NewToolCancelledMessage("user", nil)
+
+	assert.Equal(t, MessageTypeToolCancelled, msg.Type)
+	assert.Nil(t, msg.MessageID)
+	assert.Equal(t, "user", msg.Payload.Reason)
+}
+
+func TestNewTeardownMessage(t *testing.T) {
+	msgID := "msg-teardown"
+	msg := NewTeardownMessage("", &msgID)
+
+	assert.Equal(t, MessageTypeTeardown, msg.Type)
+	assert.Equal(t, &msgID, msg.MessageID)
+
+	jsonBytes, err := json.Marshal(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"ui-lifecycle-teardown","messageId":"msg-teardown","payload":{}}`, string(jsonBytes))
+}
+
+func TestNewToolInputPartialMessage(t *testing.T) {
+	args := map[string]interface{}{"city": "Par"}
+	msg := NewToolInputPartialMessage(args, nil)
+
+	assert.Equal(t, MessageTypeToolInputPartial, msg.Type)
+	assert.Equal(t, args, msg.Payload.Arguments)
+}
+
 func TestProtocolMessageSerialization(t *testing.T) {
 	// Test that all message types can be serialized to JSON
 	msgID := "test-123"
@@ -199,6 +230,9 @@ func TestProtocolMessageSerialization(t *testing.T) {
 		NewRenderDataMessage(RenderData{}, &msgID),
 		NewMessageReceivedMessage("orig", &msgID),
 		NewMessageResponseMessage("req", nil, nil, &msgID),
+		NewToolCancelledMessage("user", &msgID),
+		NewTeardownMessage("closed", &msgID),
+		NewToolInputPartialMessage(map[string]interface{}{}, &msgID),
 	}
 
 	for _, msg := range messages {
@@ -0,0 +1,318 @@
+package mcpuiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Message parsing errors
+var (
+	ErrInvalidMessage     = errors.New("invalid MCP-UI message")
+	ErrMissingMessageType = errors.New("message must have a string 'type' field")
+	ErrUnknownMessageType = errors.New("unknown message type")
+)
+
+// UnknownMessageTypeError reports a well-formed message whose type is not part
+// of the MCP-UI protocol. Proxies can use Type to forward the message untouched.
+type UnknownMessageTypeError struct {
+	Type string
+}
+
+func (e *UnknownMessageTypeError) Error() string {
+	return fmt.Sprintf("unknown message type: %q", e.Type)
+}
+
+func (e *UnknownMessageTypeError) Is(target error) bool {
+	return target == ErrUnknownMessageType
+}
+
+// Message is implemented by every MCP-UI protocol message: the widget-to-host
+// UI action results and the lifecycle and response messages. The interface is
+// sealed so that a type switch over the values returned by ParseMessage can be
+// exhaustive.
+type Message interface {
+	// MessageType returns the protocol discriminator of the message
+	MessageType() ProtocolMessageType
+	isMessage()
+}
+
+func (r UIActionResultToolCallType) MessageType() ProtocolMessageType {
+	return ProtocolMessageType(r.Type)
+}
+func (UIActionResultToolCallType) isMessage() {}
+
+func (r UIActionResultPromptType) MessageType() ProtocolMessageType {
+	return ProtocolMessageType(r.Type)
+}
+func (UIActionResultPromptType) isMessage() {}
+
+func (r UIActionResultLinkType) MessageType() ProtocolMessageType {
+	return ProtocolMessageType(r.Type)
+}
+func (UIActionResultLinkType) isMessage() {}
+
+func (r UIActionResultIntentType) MessageType() ProtocolMessageType {
+	return ProtocolMessageType(r.Type)
+}
+func (UIActionResultIntentType) isMessage() {}
+
+func (r UIActionResultNotificationType) MessageType() ProtocolMessageType {
+	return ProtocolMessageType(r.Type)
+}
+func (UIActionResultNotificationType) isMessage() {}
+
+func (m MCPUILifecycleReadyMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUILifecycleReadyMessage) isMessage()                         {}
+
+func (m MCPUISizeChangeMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUISizeChangeMessage) isMessage()                         {}
+
+func (m MCPUIRequestDataMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIRequestDataMessage) isMessage()                         {}
+
+func (m MCPUIRequestRenderDataMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIRequestRenderDataMessage) isMessage()                         {}
+
+func (m MCPUIRenderDataMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIRenderDataMessage) isMessage()                         {}
+
+func (m MCPUIMessageReceivedMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIMessageReceivedMessage) isMessage()                         {}
+
+func (m MCPUIMessageResponseMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIMessageResponseMessage) isMessage()                         {}
+
+func (m MCPUIToolCancelledMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIToolCancelledMessage) isMessage()                         {}
+
+func (m MCPUITeardownMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUITeardownMessage) isMessage()                         {}
+
+func (m MCPUIToolInputPartialMessage) MessageType() ProtocolMessageType { return m.Type }
+func (MCPUIToolInputPartialMessage) isMessage()                         {}
+
+// ParseMessage decodes a raw postMessage payload into its concrete message type
+// based on the "type" discriminator. Every result is a pointer, for example
+// *UIActionResultToolCallType for "tool" or *MCPUISizeChangeMessage for
+// "ui-size-change".
+//
+// Decoding is strict: unknown fields, trailing data, mistyped values and
+// missing required fields are reported as ErrInvalidMessage. A message with a
+// type outside the protocol returns an *UnknownMessageTypeError.
+//
+// Example:
+//
+//	msg, err := mcpuiserver.ParseMessage(data)
+//	if err != nil {
+//	    return err
+//	}
+//	switch m := msg.(type) {
+//	case *mcpuiserver.UIActionResultToolCallType:
+//	    callTool(m.Payload.ToolName, m.Payload.Params)
+//	case *mcpuiserver.MCPUISizeChangeMessage:
+//	    resize(m.Payload.Width, m.Payload.Height)
+//	}
+func ParseMessage(data []byte) (Message, error) {
+	var envelope struct {
+		Type *string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if envelope.Type == nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMessage, ErrMissingMessageType)
+	}
+
+	msg, ok := newMessage(ProtocolMessageType(*envelope.Type))
+	if !ok {
+		return nil, &UnknownMessageTypeError{Type: *envelope.Type}
+	}
+
+	if err := decodeStrict(data, msg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMessage, *envelope.Type, err)
+	}
+	if err := msg.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMessage, *envelope.Type, err)
+	}
+	return msg, nil
+}
+
+// messageTypes lists every message type understood by ParseMessage, in
+// protocol order
+var messageTypes = []ProtocolMessageType{
+	MessageTypeToolCall,
+	MessageTypePrompt,
+	MessageTypeLink,
+	MessageTypeIntent,
+	MessageTypeNotify,
+	MessageTypeLifecycleReady,
+	MessageTypeSizeChange,
+	MessageTypeRequestData,
+	MessageTypeRequestRenderData,
+	MessageTypeLifecycleRenderData,
+	MessageTypeMessageReceived,
+	MessageTypeMessageResponse,
+	MessageTypeToolCancelled,
+	MessageTypeTeardown,
+	MessageTypeToolInputPartial,
+}
+
+// parsedMessage is a message that can check its own required fields
+type parsedMessage interface {
+	Message
+	validate() error
+}
+
+// newMessage returns an empty message of the concrete type for t
+func newMessage(t ProtocolMessageType) (parsedMessage, bool) {
+	switch t {
+	case MessageTypeToolCall:
+		return &UIActionResultToolCallType{}, true
+	case MessageTypePrompt:
+		return &UIActionResultPromptType{}, true
+	case MessageTypeLink:
+		return &UIActionResultLinkType{}, true
+	case MessageTypeIntent:
+		return &UIActionResultIntentType{}, true
+	case MessageTypeNotify:
+		return &UIActionResultNotificationType{}, true
+	case MessageTypeLifecycleReady:
+		return &MCPUILifecycleReadyMessage{}, true
+	case MessageTypeSizeChange:
+		return &MCPUISizeChangeMessage{}, true
+	case MessageTypeRequestData:
+		return &MCPUIRequestDataMessage{}, true
+	case MessageTypeRequestRenderData:
+		return &MCPUIRequestRenderDataMessage{}, true
+	case MessageTypeLifecycleRenderData:
+		return &MCPUIRenderDataMessage{}, true
+	case MessageTypeMessageReceived:
+		return &MCPUIMessageReceivedMessage{}, true
+	case MessageTypeMessageResponse:
+		return &MCPUIMessageResponseMessage{}, true
+	case MessageTypeToolCancelled:
+		return &MCPUIToolCancelledMessage{}, true
+	case MessageTypeTeardown:
+		return &MCPUITeardownMessage{}, true
+	case MessageTypeToolInputPartial:
+		return &MCPUIToolInputPartialMessage{}, true
+	}
+	return nil, false
+}
+
+// decodeStrict decodes a single JSON value into dst, rejecting unknown fields
+// and trailing data.
+func decodeStrict(data []byte, dst interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after message")
+	}
+	return nil
+}
+
+// Required field checks for parsed messages
+
+func (r *UIActionResultToolCallType) validate() error {
+	if r.Payload.ToolName == "" {
+		return errors.New("payload.toolName is required")
+	}
+	return nil
+}
+
+func (r *UIActionResultPromptType) validate() error {
+	if r.Payload.Prompt == "" {
+		return errors.New("payload.prompt is required")
+	}
+	return nil
+}
+
+func (r *UIActionResultLinkType) validate() error {
+	if r.Payload.URL == "" {
+		return errors.New("payload.url is required")
+	}
+	return nil
+}
+
+func (r *UIActionResultIntentType) validate() error {
+	if r.Payload.Intent == "" {
+		return errors.New("payload.intent is required")
+	}
+	return nil
+}
+
+func (r *UIActionResultNotificationType) validate() error {
+	if r.Payload.Message == "" {
+		return errors.New("payload.message is required")
+	}
+	return nil
+}
+
+func (m *MCPUILifecycleReadyMessage) validate() error {
+	return nil
+}
+
+func (m *MCPUISizeChangeMessage) validate() error {
+	if m.Payload.Width == nil && m.Payload.Height == nil {
+		return errors.New("payload.width or payload.height is required")
+	}
+	if m.Payload.Width != nil && *m.Payload.Width < 0 {
+		return errors.New("payload.width must not be negative")
+	}
+	if m.Payload.Height != nil && *m.Payload.Height < 0 {
+		return errors.New("payload.height must not be negative")
+	}
+	return nil
+}
+
+func (m *MCPUIRequestDataMessage) validate() error {
+	if m.MessageID == "" {
+		return errors.New("messageId is required")
+	}
+	if m.Payload.RequestType == "" {
+		return errors.New("payload.requestType is required")
+	}
+	return nil
+}
+
+func (m *MCPUIRequestRenderDataMessage) validate() error {
+	return nil
+}
+
+func (m *MCPUIRenderDataMessage) validate() error {
+	return nil
+}
+
+func (m *MCPUIMessageReceivedMessage) validate() error {
+	if m.Payload.MessageID == "" {
+		return errors.New("payload.messageId is required")
+	}
+	return nil
+}
+
+func (m *MCPUIMessageResponseMessage) validate() error {
+	if m.Payload.MessageID == "" {
+		return errors.New("payload.messageId is required")
+	}
+	return nil
+}
+
+func (m *MCPUIToolCancelledMessage) validate() error {
+	return nil
+}
+
+func (m *MCPUITeardownMessage) validate() error {
+	return nil
+}
+
+func (m *MCPUIToolInputPartialMessage) validate() error {
+	if m.Payload.Arguments == nil {
+		return errors.New("payload.arguments is required")
+	}
+	return nil
+}
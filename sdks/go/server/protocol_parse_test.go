@@ -0,0 +1,223 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMessage(t *testing.T) {
+	width := 400
+	msgID := "msg-1"
+
+	tests := []struct {
+		name string
+		data string
+		want Message
+	}{
+		{
+			name: "tool call",
+			data: `{"type":"tool","messageId":"msg-1","payload":{"toolName":"fetchData","params":{"query":"stats"}}}`,
+			want: &UIActionResultToolCallType{
+				Type:      "tool",
+				MessageID: &msgID,
+				Payload: ToolCallPayload{
+					ToolName: "fetchData",
+					Params:   map[string]interface{}{"query": "stats"},
+				},
+			},
+		},
+		{
+			name: "prompt",
+			data: `{"type":"prompt","payload":{"prompt":"Hello"}}`,
+			want: &UIActionResultPromptType{Type: "prompt", Payload: PromptPayload{Prompt: "Hello"}},
+		},
+		{
+			name: "link",
+			data: `{"type":"link","payload":{"url":"https://example.com"}}`,
+			want: &UIActionResultLinkType{Type: "link", Payload: LinkPayload{URL: "https://example.com"}},
+		},
+		{
+			name: "intent",
+			data: `{"type":"intent","payload":{"intent":"showSettings","params":{"tab":"account"}}}`,
+			want: &UIActionResultIntentType{
+				Type: "intent",
+				Payload: IntentPayload{
+					Intent: "showSettings",
+					Params: map[string]interface{}{"tab": "account"},
+				},
+			},
+		},
+		{
+			name: "notify",
+			data: `{"type":"notify","payload":{"message":"Saved"}}`,
+			want: &UIActionResultNotificationType{Type: "notify", Payload: NotificationPayload{Message: "Saved"}},
+		},
+		{
+			name: "lifecycle ready",
+			data: `{"type":"ui-lifecycle-iframe-ready"}`,
+			want: &MCPUILifecycleReadyMessage{Type: MessageTypeLifecycleReady},
+		},
+		{
+			name: "size change",
+			data: `{"type":"ui-size-change","payload":{"width":400}}`,
+			want: &MCPUISizeChangeMessage{Type: MessageTypeSizeChange, Payload: SizeChangePayload{Width: &width}},
+		},
+		{
+			name: "request data",
+			data: `{"type":"ui-request-data","messageId":"msg-1","payload":{"requestType":"userStats"}}`,
+			want: NewRequestDataMessage("userStats", nil, "msg-1"),
+		},
+		{
+			name: "request render data",
+			data: `{"type":"ui-request-render-data","messageId":"msg-1"}`,
+			want: &MCPUIRequestRenderDataMessage{Type: MessageTypeRequestRenderData, MessageID: &msgID},
+		},
+		{
+			name: "render data",
+			data: `{"type":"ui-lifecycle-iframe-render-data","payload":{"renderData":{"theme":"dark"}}}`,
+			want: NewRenderDataMessage(RenderData{Theme: "dark"}, nil),
+		},
+		{
+			name: "message received",
+			data: `{"type":"ui-message-received","payload":{"messageId":"msg-1"}}`,
+			want: NewMessageReceivedMessage("msg-1", nil),
+		},
+		{
+			name: "message response",
+			data: `{"type":"ui-message-response","payload":{"messageId":"msg-1","response":{"ok":true}}}`,
+			want: NewMessageResponseMessage("msg-1", map[string]interface{}{"ok": true}, nil, nil),
+		},
+		{
+			name: "tool cancelled",
+			data: `{"type":"ui-lifecycle-tool-cancelled","payload":{"reason":"user"}}`,
+			want: NewToolCancelledMessage("user", nil),
+		},
+		{
+			name: "teardown without payload",
+			data: `{"type":"ui-lifecycle-teardown"}`,
+			want: NewTeardownMessage("", nil),
+		},
+		{
+			name: "tool input partial",
+			data: `{"type":"ui-lifecycle-tool-input-partial","payload":{"arguments":{"city":"Par"}}}`,
+			want: NewToolInputPartialMessage(map[string]interface{}{"city": "Par"}, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseMessage([]byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, msg)
+			assert.Equal(t, tt.want.MessageType(), msg.MessageType())
+		})
+	}
+}
+
+func TestParseMessage_RoundTrip(t *testing.T) {
+	height := 300
+	messages := []Message{
+		UIActionResultToolCall("fetchData", map[string]interface{}{"query": "stats"}),
+		UIActionResultPrompt("Enter your API key"),
+		UIActionResultLink("https://docs.example.com"),
+		UIActionResultIntent("showSettings", map[string]interface{}{"tab": "account"}),
+		UIActionResultNotification("Data saved successfully!"),
+		NewSizeChangeMessage(nil, &height, nil),
+		NewRequestDataMessage("userStats", map[string]interface{}{"id": "1"}, "msg-2"),
+	}
+
+	for _, original := range messages {
+		t.Run(string(original.MessageType()), func(t *testing.T) {
+			data, err := json.Marshal(original)
+			assert.NoError(t, err)
+
+			parsed, err := ParseMessage(data)
+			assert.NoError(t, err)
+			assert.Equal(t, original.MessageType(), parsed.MessageType())
+
+			reencoded, err := json.Marshal(parsed)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(data), string(reencoded))
+		})
+	}
+}
+
+func TestParseMessage_FractionalPixels(t *testing.T) {
+	width, height, maxHeight := 413, 300, 600
+
+	tests := []struct {
+		name string
+		data string
+		want Message
+	}{
+		{
+			name: "size change",
+			data: `{"type":"ui-size-change","payload":{"width":412.5,"height":299.6}}`,
+			want: &MCPUISizeChangeMessage{Type: MessageTypeSizeChange, Payload: SizeChangePayload{Width: &width, Height: &height}},
+		},
+		{
+			name: "render data",
+			data: `{"type":"ui-lifecycle-iframe-render-data","payload":{"renderData":{"theme":"dark","maxHeight":599.5}}}`,
+			want: NewRenderDataMessage(RenderData{Theme: "dark", MaxHeight: maxHeight}, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseMessage([]byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, msg)
+		})
+	}
+}
+
+func TestParseMessage_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "not JSON", data: `tool`, wantErr: ErrInvalidMessage},
+		{name: "not an object", data: `["tool"]`, wantErr: ErrInvalidMessage},
+		{name: "missing type", data: `{"payload":{}}`, wantErr: ErrMissingMessageType},
+		{name: "non-string type", data: `{"type":1}`, wantErr: ErrInvalidMessage},
+		{name: "unknown type", data: `{"type":"ui-custom"}`, wantErr: ErrUnknownMessageType},
+		{name: "unknown field", data: `{"type":"prompt","payload":{"prompt":"hi","extra":1}}`, wantErr: ErrInvalidMessage},
+		{name: "trailing data", data: `{"type":"prompt","payload":{"prompt":"hi"}} {}`, wantErr: ErrInvalidMessage},
+		{name: "mistyped field", data: `{"type":"tool","payload":{"toolName":42}}`, wantErr: ErrInvalidMessage},
+		{name: "missing tool name", data: `{"type":"tool","payload":{}}`, wantErr: ErrInvalidMessage},
+		{name: "missing prompt", data: `{"type":"prompt"}`, wantErr: ErrInvalidMessage},
+		{name: "missing url", data: `{"type":"link","payload":{"url":""}}`, wantErr: ErrInvalidMessage},
+		{name: "missing intent", data: `{"type":"intent","payload":{"params":{}}}`, wantErr: ErrInvalidMessage},
+		{name: "missing notification", data: `{"type":"notify","payload":{}}`, wantErr: ErrInvalidMessage},
+		{name: "empty size change", data: `{"type":"ui-size-change","payload":{}}`, wantErr: ErrInvalidMessage},
+		{name: "negative size", data: `{"type":"ui-size-change","payload":{"height":-1}}`, wantErr: ErrInvalidMessage},
+		{name: "size out of range", data: `{"type":"ui-size-change","payload":{"height":1e12}}`, wantErr: ErrInvalidMessage},
+		{name: "unknown size field", data: `{"type":"ui-size-change","payload":{"width":10,"depth":1}}`, wantErr: ErrInvalidMessage},
+		{name: "unknown render data field", data: `{"type":"ui-lifecycle-iframe-render-data","payload":{"renderData":{"maxHeight":1,"bogus":2}}}`, wantErr: ErrInvalidMessage},
+		{name: "request data without id", data: `{"type":"ui-request-data","payload":{"requestType":"x"}}`, wantErr: ErrInvalidMessage},
+		{name: "request data without type", data: `{"type":"ui-request-data","messageId":"1","payload":{}}`, wantErr: ErrInvalidMessage},
+		{name: "ack without id", data: `{"type":"ui-message-received","payload":{}}`, wantErr: ErrInvalidMessage},
+		{name: "response without id", data: `{"type":"ui-message-response","payload":{"response":1}}`, wantErr: ErrInvalidMessage},
+		{name: "partial input without arguments", data: `{"type":"ui-lifecycle-tool-input-partial","payload":{}}`, wantErr: ErrInvalidMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseMessage([]byte(tt.data))
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, msg)
+		})
+	}
+}
+
+func TestUnknownMessageTypeError(t *testing.T) {
+	_, err := ParseMessage([]byte(`{"type":"ui-custom","payload":{"anything":true}}`))
+
+	var unknownErr *UnknownMessageTypeError
+	assert.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, "ui-custom", unknownErr.Type)
+	assert.NotErrorIs(t, err, ErrInvalidMessage)
+}
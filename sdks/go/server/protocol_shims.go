@@ -1,8 +1,13 @@
 package mcpuiserver
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"net/url"
+	"strings"
 )
 
 const (
@@ -12,6 +17,35 @@ const (
 	DefaultAdapterVersion = "v1"
 )
 
+// Protocol configuration errors
+var (
+	ErrInvalidProtocolConfig = errors.New("invalid protocol config")
+	ErrInvalidTimeout        = errors.New("timeout must be positive")
+	ErrInvalidIntentHandling = errors.New("intentHandling must be 'prompt' or 'ignore'")
+	ErrInvalidHostOrigin     = errors.New("hostOrigin must be an absolute origin such as 'https://example.com'")
+	ErrInvalidLogLevel       = errors.New("logLevel must be 'debug', 'info', 'warn', 'error' or 'silent'")
+	ErrInvalidAppInfo        = errors.New("appInfo must have a non-empty name and version")
+)
+
+// ProtocolConfigError reports a protocol configuration that could not be decoded
+// or failed validation for the given protocol.
+type ProtocolConfigError struct {
+	Protocol ProtocolType
+	Err      error
+}
+
+func (e *ProtocolConfigError) Error() string {
+	return fmt.Sprintf("invalid %s protocol config: %v", e.Protocol, e.Err)
+}
+
+func (e *ProtocolConfigError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ProtocolConfigError) Is(target error) bool {
+	return target == ErrInvalidProtocolConfig
+}
+
 // ProtocolShimGenerator generates script tags for external protocol adapters.
 // Each protocol type has its own generator that creates the appropriate
 // script reference for loading the external adapter.
@@ -22,6 +56,125 @@ type ProtocolShimGenerator interface {
 	GetMIMEType() string
 }
 
+// ProtocolShimValidator is implemented by shim generators that can check
+// their protocol-specific configuration before a script tag is generated.
+// Generators that do not implement it are used without validation.
+type ProtocolShimValidator interface {
+	// Validate decodes and validates the protocol-specific configuration
+	Validate() error
+}
+
+// validateShim validates the generator configuration if it supports validation
+func validateShim(shim ProtocolShimGenerator) error {
+	if v, ok := shim.(ProtocolShimValidator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// AppsSdkShimConfig is the typed schema of the data-mcp-config attribute
+// read by the external Apps SDK adapter script.
+type AppsSdkShimConfig struct {
+	// Timeout in milliseconds for async operations
+	Timeout int `json:"timeout,omitempty"`
+	// IntentHandling is either "prompt" or "ignore"
+	IntentHandling string `json:"intentHandling,omitempty"`
+	// HostOrigin for MessageEvents dispatched to the widget
+	HostOrigin string `json:"hostOrigin,omitempty"`
+	// LogLevel is the minimum level the adapter writes to the console
+	LogLevel LogLevel `json:"logLevel,omitempty"`
+	// IntentRoutes maps intent names to declarative routes
+	IntentRoutes map[string]IntentRoute `json:"intentRoutes,omitempty"`
+	// DataProviders maps ui-request-data request types to tool calls
+	DataProviders map[string]DataProvider `json:"dataProviders,omitempty"`
+}
+
+// Validate validates the Apps SDK shim configuration. Zero values are
+// left to the adapter defaults.
+func (c *AppsSdkShimConfig) Validate() error {
+	if c.Timeout < 0 {
+		return ErrInvalidTimeout
+	}
+	if c.IntentHandling != "" && c.IntentHandling != "prompt" && c.IntentHandling != "ignore" {
+		return ErrInvalidIntentHandling
+	}
+	if c.HostOrigin != "" {
+		if err := ValidateHostOrigin(c.HostOrigin); err != nil {
+			return err
+		}
+	}
+	if c.LogLevel != "" && !c.LogLevel.IsValid() {
+		return ErrInvalidLogLevel
+	}
+	if err := ValidateIntentRoutes(c.IntentRoutes); err != nil {
+		return err
+	}
+	return ValidateDataProviders(c.DataProviders)
+}
+
+// McpAppsShimConfig is the typed schema of the data-mcp-config attribute
+// read by the external MCP Apps adapter script.
+type McpAppsShimConfig struct {
+	// Timeout in milliseconds for async operations
+	Timeout int `json:"timeout,omitempty"`
+	// AppInfo identifies the widget in the ui/initialize request
+	AppInfo *AppInfo `json:"appInfo,omitempty"`
+	// AppCapabilities are announced to the host in the ui/initialize request
+	AppCapabilities map[string]interface{} `json:"appCapabilities,omitempty"`
+	// LogLevel is the minimum level the adapter writes to the console
+	LogLevel LogLevel `json:"logLevel,omitempty"`
+	// IntentRoutes maps intent names to declarative routes
+	IntentRoutes map[string]IntentRoute `json:"intentRoutes,omitempty"`
+	// DataProviders maps ui-request-data request types to tool calls
+	DataProviders map[string]DataProvider `json:"dataProviders,omitempty"`
+}
+
+// Validate validates the MCP Apps shim configuration. Zero values are
+// left to the adapter defaults.
+func (c *McpAppsShimConfig) Validate() error {
+	if c.Timeout < 0 {
+		return ErrInvalidTimeout
+	}
+	if c.AppInfo != nil && (c.AppInfo.Name == "" || c.AppInfo.Version == "") {
+		return ErrInvalidAppInfo
+	}
+	if c.LogLevel != "" && !c.LogLevel.IsValid() {
+		return ErrInvalidLogLevel
+	}
+	if err := ValidateIntentRoutes(c.IntentRoutes); err != nil {
+		return err
+	}
+	return ValidateDataProviders(c.DataProviders)
+}
+
+// DecodeAppsSdkShimConfig decodes a loosely typed config map, such as the
+// client's "mcp-ui-protocol-config", into the Apps SDK schema and validates it.
+// Numbers may be any Go numeric type, float64 or json.Number as long as they
+// are integral. Unknown keys are rejected.
+func DecodeAppsSdkShimConfig(raw map[string]interface{}) (*AppsSdkShimConfig, error) {
+	config := &AppsSdkShimConfig{}
+	if err := decodeShimConfig(raw, config); err != nil {
+		return nil, &ProtocolConfigError{Protocol: ProtocolTypeAppsSDK, Err: err}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, &ProtocolConfigError{Protocol: ProtocolTypeAppsSDK, Err: err}
+	}
+	return config, nil
+}
+
+// DecodeMcpAppsShimConfig decodes a loosely typed config map into the MCP Apps
+// schema and validates it, with the same rules as DecodeAppsSdkShimConfig.
+func DecodeMcpAppsShimConfig(raw map[string]interface{}) (*McpAppsShimConfig, error) {
+	config := &McpAppsShimConfig{}
+	if err := decodeShimConfig(raw, config); err != nil {
+		return nil, &ProtocolConfigError{Protocol: ProtocolTypeMCPApps, Err: err}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, &ProtocolConfigError{Protocol: ProtocolTypeMCPApps, Err: err}
+	}
+	return config, nil
+}
+
 // GenericProtocolShim generates script tags for the generic MCP-UI protocol.
 // No external adapter is needed for the generic protocol.
 type GenericProtocolShim struct{}
@@ -36,6 +189,11 @@ func (g *GenericProtocolShim) GetMIMEType() string {
 	return MimeTypeHTML
 }
 
+// Validate always succeeds as the generic protocol takes no configuration
+func (g *GenericProtocolShim) Validate() error {
+	return nil
+}
+
 // AppsSdkProtocolShim generates script tags for the ChatGPT/Apps SDK adapter.
 // This adapter enables widgets to run in ChatGPT and other Apps SDK environments.
 type AppsSdkProtocolShim struct {
@@ -44,20 +202,27 @@ type AppsSdkProtocolShim struct {
 	Config  map[string]interface{}
 }
 
-// GenerateScriptTag returns a script tag that loads the Apps SDK adapter from an external URL
+// ParseConfig decodes Config into the typed Apps SDK schema and validates it.
+func (a *AppsSdkProtocolShim) ParseConfig() (*AppsSdkShimConfig, error) {
+	return DecodeAppsSdkShimConfig(a.Config)
+}
+
+// Validate reports whether Config matches the Apps SDK schema
+func (a *AppsSdkProtocolShim) Validate() error {
+	_, err := a.ParseConfig()
+	return err
+}
+
+// GenerateScriptTag returns a script tag that loads the Apps SDK adapter from an external URL.
+// An invalid Config is serialized as an empty object; call Validate to surface the error.
 func (a *AppsSdkProtocolShim) GenerateScriptTag() string {
 	scriptURL := fmt.Sprintf("%s/appssdk-%s.js", a.BaseURL, a.Version)
 
-	// Serialize config to JSON
-	configJSON := "{}"
-	if a.Config != nil && len(a.Config) > 0 {
-		if jsonBytes, err := json.Marshal(a.Config); err == nil {
-			configJSON = string(jsonBytes)
-		}
+	var config interface{}
+	if parsed, err := a.ParseConfig(); err == nil {
+		config = parsed
 	}
-
-	// Generate script tag with configuration in data attribute
-	return fmt.Sprintf(`<script src="%s" data-mcp-config='%s'></script>`, scriptURL, configJSON)
+	return generateAdapterScriptTag(scriptURL, config)
 }
 
 // GetMIMEType returns the Apps SDK specific MIME type
@@ -74,20 +239,27 @@ type McpAppsProtocolShim struct {
 	Config  map[string]interface{}
 }
 
-// GenerateScriptTag returns a script tag that loads the MCP Apps adapter from an external URL
+// ParseConfig decodes Config into the typed MCP Apps schema and validates it.
+func (m *McpAppsProtocolShim) ParseConfig() (*McpAppsShimConfig, error) {
+	return DecodeMcpAppsShimConfig(m.Config)
+}
+
+// Validate reports whether Config matches the MCP Apps schema
+func (m *McpAppsProtocolShim) Validate() error {
+	_, err := m.ParseConfig()
+	return err
+}
+
+// GenerateScriptTag returns a script tag that loads the MCP Apps adapter from an external URL.
+// An invalid Config is serialized as an empty object; call Validate to surface the error.
 func (m *McpAppsProtocolShim) GenerateScriptTag() string {
 	scriptURL := fmt.Sprintf("%s/mcpapps-%s.js", m.BaseURL, m.Version)
 
-	// Serialize config to JSON
-	configJSON := "{}"
-	if m.Config != nil && len(m.Config) > 0 {
-		if jsonBytes, err := json.Marshal(m.Config); err == nil {
-			configJSON = string(jsonBytes)
-		}
+	var config interface{}
+	if parsed, err := m.ParseConfig(); err == nil {
+		config = parsed
 	}
-
-	// Generate script tag with configuration in data attribute
-	return fmt.Sprintf(`<script src="%s" data-mcp-config='%s'></script>`, scriptURL, configJSON)
+	return generateAdapterScriptTag(scriptURL, config)
 }
 
 // GetMIMEType returns the standard HTML MIME type for MCP Apps
@@ -98,15 +270,7 @@ func (m *McpAppsProtocolShim) GetMIMEType() string {
 // getProtocolShimGenerator creates the appropriate shim generator based on protocol configuration.
 // It handles default values for BaseURL and Version if not specified in the config.
 func getProtocolShimGenerator(config *ProtocolConfig) ProtocolShimGenerator {
-	baseURL := config.BaseURL
-	if baseURL == "" {
-		baseURL = DefaultAdapterBaseURL
-	}
-
-	version := config.Version
-	if version == "" {
-		version = DefaultAdapterVersion
-	}
+	baseURL, version := adapterLocation(config)
 
 	switch config.Type {
 	case ProtocolTypeAppsSDK:
@@ -125,3 +289,74 @@ func getProtocolShimGenerator(config *ProtocolConfig) ProtocolShimGenerator {
 		return &GenericProtocolShim{}
 	}
 }
+
+// adapterLocation returns the base URL and version of the adapter scripts
+// for config, applying the defaults
+func adapterLocation(config *ProtocolConfig) (baseURL, version string) {
+	baseURL = config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultAdapterBaseURL
+	}
+	version = config.Version
+	if version == "" {
+		version = DefaultAdapterVersion
+	}
+	return baseURL, version
+}
+
+// generateAdapterScriptTag renders the external adapter script tag with the
+// configuration JSON encoded into the data-mcp-config attribute.
+func generateAdapterScriptTag(scriptURL string, config interface{}) string {
+	configJSON := "{}"
+	if config != nil {
+		if jsonBytes, err := json.Marshal(config); err == nil {
+			configJSON = string(jsonBytes)
+		}
+	}
+
+	return fmt.Sprintf(`<script src="%s" data-mcp-config='%s'></script>`,
+		html.EscapeString(scriptURL), escapeSingleQuotedAttribute(configJSON))
+}
+
+// singleQuotedAttributeEscaper escapes text for a single-quoted HTML attribute value
+var singleQuotedAttributeEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"'", "&#39;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// escapeSingleQuotedAttribute escapes s so it cannot terminate a single-quoted
+// attribute. Double quotes are left intact so the config JSON stays readable;
+// json.Marshal already escapes <, > and & as \u sequences, which leaves the
+// single quote as the only character actually rewritten in practice.
+func escapeSingleQuotedAttribute(s string) string {
+	return singleQuotedAttributeEscaper.Replace(s)
+}
+
+// decodeShimConfig decodes a loosely typed config map (typically parsed from
+// client JSON) into dst, rejecting unknown keys and mistyped values.
+func decodeShimConfig(raw map[string]interface{}, dst interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	jsonBytes, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(dst)
+}
+
+// ValidateHostOrigin checks that origin is a scheme and host with no path,
+// as the adapters expect for the origin of host MessageEvents.
+func ValidateHostOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("%w: got %q", ErrInvalidHostOrigin, origin)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package mcpuiserver
 
 import (
 	"encoding/json"
+	"html"
 	"strings"
 	"testing"
 
@@ -271,4 +272,163 @@ func TestProtocolShimGenerator_Interface(t *testing.T) {
 	var _ ProtocolShimGenerator = &GenericProtocolShim{}
 	var _ ProtocolShimGenerator = &AppsSdkProtocolShim{}
 	var _ ProtocolShimGenerator = &McpAppsProtocolShim{}
+
+	// The built-in shims also validate their configuration
+	var _ ProtocolShimValidator = &GenericProtocolShim{}
+	var _ ProtocolShimValidator = &AppsSdkProtocolShim{}
+	var _ ProtocolShimValidator = &McpAppsProtocolShim{}
+}
+
+// scriptOnlyShim implements ProtocolShimGenerator without Validate, as
+// generators written before ProtocolShimValidator do
+type scriptOnlyShim struct{}
+
+func (scriptOnlyShim) GenerateScriptTag() string { return "<script></script>" }
+func (scriptOnlyShim) GetMIMEType() string       { return MimeTypeHTML }
+
+func TestValidateShim(t *testing.T) {
+	assert.NoError(t, validateShim(scriptOnlyShim{}))
+	assert.NoError(t, validateShim(&AppsSdkProtocolShim{}))
+	assert.ErrorIs(t, validateShim(&AppsSdkProtocolShim{Config: map[string]interface{}{"timeout": -1}}), ErrInvalidTimeout)
+}
+
+func TestAppsSdkProtocolShim_ParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		want    *AppsSdkShimConfig
+		wantErr error
+	}{
+		{
+			name:   "nil config",
+			config: nil,
+			want:   &AppsSdkShimConfig{},
+		},
+		{
+			name: "JSON numbers decode into int",
+			config: map[string]interface{}{
+				"timeout":        float64(10000),
+				"intentHandling": "ignore",
+				"hostOrigin":     "https://chatgpt.com",
+			},
+			want: &AppsSdkShimConfig{
+				Timeout:        10000,
+				IntentHandling: "ignore",
+				HostOrigin:     "https://chatgpt.com",
+			},
+		},
+		{
+			name:    "fractional timeout",
+			config:  map[string]interface{}{"timeout": 1.5},
+			wantErr: ErrInvalidProtocolConfig,
+		},
+		{
+			name:    "negative timeout",
+			config:  map[string]interface{}{"timeout": -1},
+			wantErr: ErrInvalidTimeout,
+		},
+		{
+			name:    "invalid intent handling",
+			config:  map[string]interface{}{"intentHandling": "execute"},
+			wantErr: ErrInvalidIntentHandling,
+		},
+		{
+			name:    "host origin with path",
+			config:  map[string]interface{}{"hostOrigin": "https://example.com/app"},
+			wantErr: ErrInvalidHostOrigin,
+		},
+		{
+			name:    "wrong value type",
+			config:  map[string]interface{}{"intentHandling": true},
+			wantErr: ErrInvalidProtocolConfig,
+		},
+		{
+			name:    "unknown key",
+			config:  map[string]interface{}{"timout": 5000},
+			wantErr: ErrInvalidProtocolConfig,
+		},
+		{
+			name:    "invalid log level",
+			config:  map[string]interface{}{"logLevel": "trace"},
+			wantErr: ErrInvalidLogLevel,
+		},
+		{
+			name:    "unmarshalable value",
+			config:  map[string]interface{}{"timeout": make(chan int)},
+			wantErr: ErrInvalidProtocolConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shim := &AppsSdkProtocolShim{Config: tt.config}
+			got, err := shim.ParseConfig()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrInvalidProtocolConfig)
+				assert.ErrorIs(t, shim.Validate(), tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.NoError(t, shim.Validate())
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMcpAppsProtocolShim_ParseConfig(t *testing.T) {
+	shim := &McpAppsProtocolShim{Config: map[string]interface{}{"timeout": float64(5000)}}
+	config, err := shim.ParseConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 5000, config.Timeout)
+
+	shim = &McpAppsProtocolShim{Config: map[string]interface{}{
+		"appInfo": map[string]interface{}{"name": "weather-widget"},
+	}}
+	assert.ErrorIs(t, shim.Validate(), ErrInvalidAppInfo)
+
+	shim = &McpAppsProtocolShim{Config: map[string]interface{}{"intentHandling": "prompt"}}
+	err = shim.Validate()
+	assert.ErrorIs(t, err, ErrInvalidProtocolConfig)
+	var configErr *ProtocolConfigError
+	assert.ErrorAs(t, err, &configErr)
+	assert.Equal(t, ProtocolTypeMCPApps, configErr.Protocol)
+}
+
+func TestProtocolShim_AttributeEscaping(t *testing.T) {
+	shim := &AppsSdkProtocolShim{
+		BaseURL: "https://cdn.example.com",
+		Version: "v1",
+		Config: map[string]interface{}{
+			"hostOrigin": "https://it's.example.com",
+		},
+	}
+
+	script := shim.GenerateScriptTag()
+
+	// The single quote must not terminate the attribute early
+	assert.NotContains(t, script, "it's")
+	assert.Contains(t, script, "it&#39;s")
+
+	parts := strings.Split(script, "data-mcp-config='")
+	assert.Len(t, parts, 2)
+	configPart := strings.Split(parts[1], "'")[0]
+
+	var parsedConfig map[string]interface{}
+	err := json.Unmarshal([]byte(html.UnescapeString(configPart)), &parsedConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://it's.example.com", parsedConfig["hostOrigin"])
+}
+
+func TestProtocolShim_InvalidConfigRendersEmptyObject(t *testing.T) {
+	shim := &McpAppsProtocolShim{
+		BaseURL: "https://cdn.example.com",
+		Version: "v1",
+		Config:  map[string]interface{}{"timeout": "soon"},
+	}
+
+	assert.Error(t, shim.Validate())
+	assert.Contains(t, shim.GenerateScriptTag(), "data-mcp-config='{}'")
 }
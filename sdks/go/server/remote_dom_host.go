@@ -0,0 +1,327 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RemoteDOMModuleBaseURL is the CDN the Remote DOM host page imports
+// @remote-dom/core, @remote-dom/react, @quilted/threads and React from. Hosts
+// that restrict network access must allow it, for example in the MCP Apps
+// resource CSP.
+const RemoteDOMModuleBaseURL = "https://esm.sh"
+
+// Module versions imported by the host page, matching the dependencies of
+// the client SDK's Remote DOM renderer
+const (
+	remoteDOMCoreVersion  = "1.8.0"
+	remoteDOMReactVersion = "1.2.2"
+	threadsVersion        = "3.1.3"
+	reactVersion          = "18.3.1"
+)
+
+// remoteDOMHostDataID is the id of the script element carrying the Remote
+// DOM script and framework as JSON
+const remoteDOMHostDataID = "mcpui-remote-dom"
+
+// remoteDOMHostOptions is the JSON read by the host page
+type remoteDOMHostOptions struct {
+	Framework RemoteDOMFramework `json:"framework"`
+	Code      string             `json:"code"`
+}
+
+// remoteDOMModule returns the CDN URL of an npm package entry point
+func remoteDOMModule(pkg, version, path string) string {
+	return RemoteDOMModuleBaseURL + "/" + pkg + "@" + version + path
+}
+
+// remoteDOMSandboxSrcDoc is the document of the sandboxed iframe that runs the
+// Remote DOM script. Like the client SDK's iframe, it defines the remote
+// elements, observes #root with a RemoteMutationObserver and runs the script
+// as a function of root and console.
+func remoteDOMSandboxSrcDoc() string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+</head>
+<body>
+<div id="root"></div>
+<script type="module">
+import { RemoteElement, RemoteMutationObserver } from "%s";
+import { ThreadNestedIframe } from "%s";
+
+new ThreadNestedIframe({
+  exports: {
+    async render(options, receiver) {
+      (options.remoteElements || []).forEach(function (def) {
+        if (customElements.get(def.tagName)) return;
+        customElements.define(def.tagName, class extends RemoteElement {
+          static get remoteAttributes() { return def.remoteAttributes || []; }
+          static get remoteEvents() { return def.remoteEvents || []; }
+        });
+      });
+
+      const root = document.querySelector("#root");
+      new RemoteMutationObserver(receiver).observe(root);
+
+      try {
+        new Function("root", "console", options.code)(root, console);
+      } catch (e) {
+        console.error("Error executing remote script:", e);
+      }
+    },
+  },
+});
+</script>
+</body>
+</html>`,
+		remoteDOMModule("@remote-dom/core", remoteDOMCoreVersion, "/elements"),
+		remoteDOMModule("@quilted/threads", threadsVersion, ""),
+	)
+}
+
+// remoteDOMElementsScript defines the remote elements of the basic component
+// library, as exported by the client SDK's remote-elements module
+const remoteDOMElementsScript = `const remoteElements = [
+  { tagName: "ui-card" },
+  { tagName: "ui-button", remoteAttributes: ["label"], remoteEvents: ["click", "press"] },
+  { tagName: "ui-text", remoteAttributes: ["content"] },
+  { tagName: "ui-stack", remoteAttributes: ["direction", "spacing", "align", "justify"] },
+  { tagName: "ui-image", remoteAttributes: ["src", "alt", "width", "height"] },
+];`
+
+// remoteDOMRunScript loads the sandbox iframe and renders the script into
+// receiver, once the framework script has created it. UI actions posted by
+// the script are relayed to the adapter, and messages from the adapter are
+// relayed back, as the external URL wrapper page does.
+const remoteDOMRunScript = `const options = JSON.parse(document.getElementById("%s").textContent);
+const sandbox = document.createElement("iframe");
+sandbox.setAttribute("sandbox", "allow-scripts");
+sandbox.setAttribute("title", "Remote DOM Sandbox");
+sandbox.style.display = "none";
+sandbox.srcdoc = %s;
+sandbox.addEventListener("load", function () {
+  const thread = new ThreadIframe(sandbox);
+  thread.imports
+    .render({ code: options.code, remoteElements, useReactRenderer: options.framework === "react" }, receiver.connection)
+    .catch(function (error) { console.error("Error calling remote render:", error); });
+}, { once: true });
+
+window.addEventListener("message", function (event) {
+  const data = event.data;
+  if (!data || typeof data !== "object" || Array.isArray(data) || typeof data.type !== "string") {
+    return;
+  }
+  if (event.source === sandbox.contentWindow) {
+    window.parent.postMessage(data, "*");
+  } else if (event.source === window || event.source === null) {
+    sandbox.contentWindow.postMessage(data, "*");
+  }
+});
+document.body.appendChild(sandbox);`
+
+// remoteDOMWebComponentsScript mirrors the remote tree into #root with
+// DOMRemoteReceiver and defines the basic component library as custom
+// elements, with the styles of the client SDK's basic library
+const remoteDOMWebComponentsScript = `import { DOMRemoteReceiver } from "%s";
+import { ThreadIframe } from "%s";
+
+function define(tagName, attributes, render) {
+  if (customElements.get(tagName)) return;
+  customElements.define(tagName, class extends HTMLElement {
+    static get observedAttributes() { return attributes; }
+    connectedCallback() { this.update(); }
+    attributeChangedCallback() { this.update(); }
+    update() {
+      if (!this.isConnected) return;
+      if (!this.shadowRoot) this.attachShadow({ mode: "open" });
+      render(this, this.shadowRoot);
+    }
+  });
+}
+function attr(el, name, fallback) {
+  const value = el.getAttribute(name);
+  return value === null || value === "" ? fallback : value;
+}
+function text(value) {
+  const span = document.createElement("span");
+  span.textContent = value;
+  return span;
+}
+function style(css) {
+  const element = document.createElement("style");
+  element.textContent = css;
+  return element;
+}
+
+define("ui-text", ["content"], function (el, shadow) {
+  const content = attr(el, "content", null);
+  shadow.replaceChildren(content === null ? document.createElement("slot") : text(content));
+});
+define("ui-button", ["label"], function (el, shadow) {
+  const label = attr(el, "label", null);
+  const button = document.createElement("button");
+  button.setAttribute("part", "button");
+  button.appendChild(label === null ? document.createElement("slot") : text(label));
+  button.addEventListener("click", function () {
+    el.dispatchEvent(new CustomEvent("press"));
+  });
+  shadow.replaceChildren(
+    style("button{padding:8px 16px;background-color:#007bff;color:white;border:none;border-radius:4px;cursor:pointer}"),
+    button
+  );
+});
+define("ui-stack", ["direction", "spacing", "align", "justify"], function (el, shadow) {
+  shadow.replaceChildren(
+    style(":host{display:flex;flex-direction:" + (attr(el, "direction", "vertical") === "horizontal" ? "row" : "column") +
+      ";gap:" + attr(el, "spacing", "8") + "px;align-items:" + attr(el, "align", "stretch") +
+      ";justify-content:" + attr(el, "justify", "flex-start") + "}"),
+    document.createElement("slot")
+  );
+});
+define("ui-image", ["src", "alt", "width", "height"], function (el, shadow) {
+  const image = document.createElement("img");
+  ["src", "alt", "width", "height"].forEach(function (name) {
+    if (el.hasAttribute(name)) image.setAttribute(name, el.getAttribute(name));
+  });
+  shadow.replaceChildren(style("img{max-width:100%%;height:auto;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,0.1)}"), image);
+});
+define("ui-card", [], function (el, shadow) {
+  shadow.replaceChildren(style(":host{display:block;padding:16px;border:1px solid #e0e0e0;border-radius:8px}"), document.createElement("slot"));
+});
+
+const receiver = new DOMRemoteReceiver();
+receiver.connect(document.getElementById("root"));`
+
+// remoteDOMReactScript renders the remote tree into #root with
+// RemoteRootRenderer and the client SDK's basic React component library
+const remoteDOMReactScript = `import { createElement, forwardRef } from "%s";
+import { createRoot } from "%s";
+import { RemoteReceiver, RemoteRootRenderer, createRemoteComponentRenderer } from "%s";
+import { ThreadIframe } from "%s";
+
+const UIText = forwardRef(function ({ content, children, ...props }, ref) {
+  return createElement("span", { ref, ...props }, content || children);
+});
+const UIButton = forwardRef(function ({ label, onPress, onClick, children, ...props }, ref) {
+  return createElement("button", {
+    ref,
+    onClick: function (event) {
+      if (onPress) onPress();
+      if (onClick) onClick(event);
+    },
+    style: { padding: "8px 16px", backgroundColor: "#007bff", color: "white", border: "none", borderRadius: "4px", cursor: "pointer" },
+    ...props,
+  }, label || children);
+});
+const UIStack = forwardRef(function ({ direction = "vertical", spacing = "8", align = "stretch", justify = "flex-start", children, ...props }, ref) {
+  return createElement("div", {
+    ref,
+    style: { display: "flex", flexDirection: direction === "horizontal" ? "row" : "column", gap: spacing + "px", alignItems: align, justifyContent: justify },
+    ...props,
+  }, children);
+});
+const UIImage = forwardRef(function ({ src, alt, width, height, children, ...props }, ref) {
+  return createElement("img", {
+    ref, src, alt, width, height,
+    style: { maxWidth: "100%%", height: "auto", borderRadius: "8px", boxShadow: "0 2px 8px rgba(0, 0, 0, 0.1)" },
+    ...props,
+  });
+});
+const UICard = forwardRef(function ({ children, ...props }, ref) {
+  return createElement("div", {
+    ref,
+    style: { display: "block", padding: "16px", border: "1px solid #e0e0e0", borderRadius: "8px" },
+    ...props,
+  }, children);
+});
+
+const components = new Map([
+  ["ui-text", UIText],
+  ["ui-button", UIButton],
+  ["ui-stack", UIStack],
+  ["ui-image", UIImage],
+  ["ui-card", UICard],
+].map(function ([tagName, component]) {
+  return [tagName, createRemoteComponentRenderer(component)];
+}));
+
+const receiver = new RemoteReceiver();
+createRoot(document.getElementById("root")).render(createElement(RemoteRootRenderer, { receiver, components }));`
+
+// remoteDOMFrameworkScript returns the module script that creates the
+// receiver and mounts the component library for framework
+func remoteDOMFrameworkScript(framework RemoteDOMFramework) (string, error) {
+	threads := remoteDOMModule("@quilted/threads", threadsVersion, "")
+	switch framework {
+	case FrameworkWebComponents:
+		return fmt.Sprintf(remoteDOMWebComponentsScript,
+			remoteDOMModule("@remote-dom/core", remoteDOMCoreVersion, "/receivers"),
+			threads,
+		), nil
+	case FrameworkReact:
+		// Pinning React for @remote-dom/react makes it share the page's
+		// React instance
+		deps := "?deps=react@" + reactVersion + ",react-dom@" + reactVersion
+		return fmt.Sprintf(remoteDOMReactScript,
+			remoteDOMModule("react", reactVersion, ""),
+			remoteDOMModule("react-dom", reactVersion, "/client"),
+			remoteDOMModule("@remote-dom/react", remoteDOMReactVersion, "/host"+deps),
+			threads,
+		), nil
+	}
+	return "", fmt.Errorf("%w: got %q", ErrInvalidFramework, framework)
+}
+
+// requiresHTMLHost reports whether hosts speaking protocol render only HTML,
+// so Remote DOM content must be wrapped in a host page
+func requiresHTMLHost(protocol ProtocolType) bool {
+	return protocol == ProtocolTypeAppsSDK || protocol == ProtocolTypeMCPApps
+}
+
+// generateRemoteDOMHostPage wraps a Remote DOM script in an HTML page for
+// hosts that only render HTML. The page loads the protocol adapter, mounts
+// the component library for the payload's framework on a remote-dom
+// receiver and runs the script in a sandboxed iframe, as the client SDK does.
+func generateRemoteDOMHostPage(payload *RemoteDOMPayload, adapterScriptTag string) (string, error) {
+	frameworkScript, err := remoteDOMFrameworkScript(payload.Framework)
+	if err != nil {
+		return "", err
+	}
+	// json.Marshal escapes <, > and &, so neither the script nor the sandbox
+	// document can close the surrounding <script> element
+	data, err := json.Marshal(remoteDOMHostOptions{Framework: payload.Framework, Code: payload.Script})
+	if err != nil {
+		return "", err
+	}
+	srcDoc, err := json.Marshal(remoteDOMSandboxSrcDoc())
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+%s
+</head>
+<body>
+<div id="root"></div>
+<script type="application/json" id="%s">%s</script>
+<script type="module">
+%s
+
+%s
+
+%s
+</script>
+</body>
+</html>`,
+		adapterScriptTag,
+		remoteDOMHostDataID, data,
+		frameworkScript,
+		remoteDOMElementsScript,
+		fmt.Sprintf(remoteDOMRunScript, remoteDOMHostDataID, srcDoc),
+	), nil
+}
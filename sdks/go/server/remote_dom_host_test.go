@@ -0,0 +1,197 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUIResource_RemoteDOMWithProtocol(t *testing.T) {
+	script := `const b = document.createElement('ui-button'); b.setAttribute('label', '</script><b>'); root.appendChild(b);`
+
+	tests := []struct {
+		name          string
+		framework     RemoteDOMFramework
+		config        *ProtocolConfig
+		wantMimeType  string
+		wantAdapter   string
+		wantUnwrapped bool
+	}{
+		{
+			name:         "Apps SDK with React",
+			framework:    FrameworkReact,
+			config:       &ProtocolConfig{Type: ProtocolTypeAppsSDK},
+			wantMimeType: MimeTypeAppsSdkAdapter,
+			wantAdapter:  DefaultAdapterBaseURL + "/appssdk-" + DefaultAdapterVersion + ".js",
+		},
+		{
+			name:         "MCP Apps with WebComponents and custom location",
+			framework:    FrameworkWebComponents,
+			config:       &ProtocolConfig{Type: ProtocolTypeMCPApps, BaseURL: "https://cdn.example.com", Version: "v2"},
+			wantMimeType: MimeTypeMCPAppsAdapter,
+			wantAdapter:  "https://cdn.example.com/mcpapps-v2.js",
+		},
+		{
+			name:          "generic protocol leaves Remote DOM unchanged",
+			framework:     FrameworkReact,
+			config:        &ProtocolConfig{Type: ProtocolTypeGeneric},
+			wantMimeType:  MimeTypeRemoteDomReact,
+			wantUnwrapped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: script, Framework: tt.framework}
+			resource, err := CreateUIResource("ui://test/remote", content, EncodingText, WithProtocolConfig(tt.config))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMimeType, resource.Resource.MimeType)
+			assert.NoError(t, ValidateUIResource(resource))
+
+			page := resource.Resource.Text
+			if tt.wantUnwrapped {
+				assert.Equal(t, script, page)
+				return
+			}
+
+			assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+			assert.Equal(t, []string{tt.wantAdapter}, externalScripts(page),
+				"the adapter must be the only external script")
+			assert.Contains(t, page, `<div id="root"></div>`)
+			assert.NotContains(t, page, "</script><b>", "script must not close the data element")
+			assert.Equal(t, 3, strings.Count(page, "<script"), "adapter, data and module scripts only")
+
+			frameworkScript, err := remoteDOMFrameworkScript(tt.framework)
+			assert.NoError(t, err)
+			assert.Contains(t, page, frameworkScript)
+			assert.Less(t, strings.Index(page, tt.wantAdapter), strings.Index(page, frameworkScript),
+				"adapter must load before the receiver")
+
+			var options remoteDOMHostOptions
+			assert.NoError(t, json.Unmarshal([]byte(remoteDOMHostData(t, page)), &options))
+			assert.Equal(t, remoteDOMHostOptions{Framework: tt.framework, Code: script}, options)
+		})
+	}
+}
+
+func TestRemoteDOMFrameworkScript(t *testing.T) {
+	tests := []struct {
+		framework   RemoteDOMFramework
+		wantImports []string
+		wantLibrary string
+	}{
+		{
+			framework: FrameworkWebComponents,
+			wantImports: []string{
+				`import { DOMRemoteReceiver } from "https://esm.sh/@remote-dom/core@` + remoteDOMCoreVersion + `/receivers"`,
+				`import { ThreadIframe } from "https://esm.sh/@quilted/threads@` + threadsVersion + `"`,
+			},
+			wantLibrary: `define("%s"`,
+		},
+		{
+			framework: FrameworkReact,
+			wantImports: []string{
+				`from "https://esm.sh/react@` + reactVersion + `"`,
+				`from "https://esm.sh/react-dom@` + reactVersion + `/client"`,
+				`import { RemoteReceiver, RemoteRootRenderer, createRemoteComponentRenderer } from "https://esm.sh/@remote-dom/react@` + remoteDOMReactVersion + `/host?deps=react@` + reactVersion + `,react-dom@` + reactVersion + `"`,
+				`import { ThreadIframe } from "https://esm.sh/@quilted/threads@` + threadsVersion + `"`,
+			},
+			wantLibrary: `["%s", `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.framework), func(t *testing.T) {
+			script, err := remoteDOMFrameworkScript(tt.framework)
+			assert.NoError(t, err)
+			for _, want := range tt.wantImports {
+				assert.Contains(t, script, want)
+			}
+			for _, tag := range []string{"ui-text", "ui-button", "ui-stack", "ui-image", "ui-card"} {
+				assert.Contains(t, script, fmt.Sprintf(tt.wantLibrary, tag), "library must provide %s", tag)
+				assert.Contains(t, remoteDOMElementsScript, `tagName: "`+tag+`"`, "sandbox must define %s", tag)
+			}
+			assert.NotContains(t, script, "%!", "script must be fully formatted")
+			assert.NotContains(t, script, "</script")
+		})
+	}
+
+	script, err := remoteDOMFrameworkScript("vue")
+	assert.ErrorIs(t, err, ErrInvalidFramework)
+	assert.Empty(t, script)
+}
+
+func TestRemoteDOMSandboxSrcDoc(t *testing.T) {
+	srcDoc := remoteDOMSandboxSrcDoc()
+
+	assert.Contains(t, srcDoc, `import { RemoteElement, RemoteMutationObserver } from "https://esm.sh/@remote-dom/core@`+remoteDOMCoreVersion+`/elements"`)
+	assert.Contains(t, srcDoc, `import { ThreadNestedIframe } from "https://esm.sh/@quilted/threads@`+threadsVersion+`"`)
+	assert.Contains(t, srcDoc, `new Function("root", "console", options.code)(root, console)`)
+	assert.Contains(t, remoteDOMRunScript, `sandbox.setAttribute("sandbox", "allow-scripts")`)
+}
+
+// TestRemoteDOMModuleVersions checks that the host page imports the versions
+// the client SDK's Remote DOM renderer is built with
+func TestRemoteDOMModuleVersions(t *testing.T) {
+	data, err := os.ReadFile("../../typescript/client/package.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var manifest struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &manifest))
+
+	assert.Equal(t, "^"+remoteDOMCoreVersion, manifest.Dependencies["@remote-dom/core"])
+	assert.Equal(t, "^"+remoteDOMReactVersion, manifest.Dependencies["@remote-dom/react"])
+	assert.Equal(t, "^"+threadsVersion, manifest.Dependencies["@quilted/threads"])
+	assert.Equal(t, "^"+reactVersion, manifest.DevDependencies["react"])
+}
+
+func TestCreateUIResource_RemoteDOMWithProtocolBlob(t *testing.T) {
+	content := &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkReact}
+	resource, err := CreateUIResource("ui://test/remote", content, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+
+	assert.NoError(t, err)
+	assert.Equal(t, MimeTypeMCPAppsAdapter, resource.Resource.MimeType)
+	assert.Empty(t, resource.Resource.Text)
+	assert.NoError(t, ValidateUIResource(resource))
+}
+
+func TestCreateUIResource_RemoteDOMWithInvalidProtocolConfig(t *testing.T) {
+	content := &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkReact}
+	_, err := CreateUIResource("ui://test/remote", content, EncodingText, WithProtocolConfig(&ProtocolConfig{
+		Type:   ProtocolTypeAppsSDK,
+		Config: map[string]interface{}{"timeout": -1},
+	}))
+
+	var configErr *ProtocolConfigError
+	assert.ErrorAs(t, err, &configErr)
+}
+
+// externalScripts returns the src of every external script in a page
+func externalScripts(page string) []string {
+	var srcs []string
+	for _, part := range strings.Split(page, `<script src="`)[1:] {
+		srcs = append(srcs, part[:strings.Index(part, `"`)])
+	}
+	return srcs
+}
+
+// remoteDOMHostData returns the body of the JSON data element in a host page
+func remoteDOMHostData(t *testing.T, page string) string {
+	t.Helper()
+	open := `<script type="application/json" id="` + remoteDOMHostDataID + `">`
+	start := strings.Index(page, open)
+	if !assert.GreaterOrEqual(t, start, 0) {
+		return "{}"
+	}
+	rest := page[start+len(open):]
+	return rest[:strings.Index(rest, "</script>")]
+}
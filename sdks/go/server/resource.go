@@ -2,12 +2,14 @@ package mcpuiserver
 
 import (
 	"fmt"
+	"strings"
 )
 
 // CreateUIResource creates a UIResource for inclusion in MCP tool results.
 //
 // The function validates the URI (must start with "ui://"), processes the content
-// based on its type, and applies the specified encoding.
+// based on its type, runs the resource transformers (see WithTransformers), and
+// applies the specified encoding.
 //
 // Parameters:
 //   - uri: Resource identifier starting with "ui://"
@@ -30,6 +32,50 @@ import (
 //	    EncodingText,
 //	)
 func CreateUIResource(uri string, content ResourceContentPayload, encoding Encoding, opts ...Option) (*UIResource, error) {
+	built, err := buildResource(uri, content, encoding, opts)
+	if err != nil {
+		return nil, err
+	}
+	return built.resource(), nil
+}
+
+// builtResource is a resource with every option applied whose content has not
+// been encoded yet. CreateUIResource encodes it into a UIResource and
+// UIResourceStream writes it straight to its output.
+type builtResource struct {
+	uri         string
+	mimeType    string
+	encoding    Encoding
+	content     contentParts
+	meta        map[string]interface{}
+	annotations map[string]interface{}
+	outerMeta   map[string]interface{}
+}
+
+// buildResource validates the arguments, applies the options and processes
+// the content for CreateUIResource and NewUIResourceStream
+func buildResource(uri string, content ResourceContentPayload, encoding Encoding, opts []Option) (*builtResource, error) {
+	return buildResourceOptions(newResourceOptions(uri, content, encoding, opts))
+}
+
+// newResourceOptions applies opts to the options for the arguments
+func newResourceOptions(uri string, content ResourceContentPayload, encoding Encoding, opts []Option) *CreateUIResourceOptions {
+	options := &CreateUIResourceOptions{
+		URI:      uri,
+		Content:  content,
+		Encoding: encoding,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// buildResourceOptions validates the arguments held in options and processes
+// the content
+func buildResourceOptions(options *CreateUIResourceOptions) (*builtResource, error) {
+	uri, content, encoding := options.URI, options.Content, options.Encoding
+
 	// Validate URI
 	if err := validateURI(uri); err != nil {
 		return nil, err
@@ -48,17 +94,111 @@ func CreateUIResource(uri string, content ResourceContentPayload, encoding Encod
 		return nil, ErrInvalidEncoding
 	}
 
-	// Apply options
-	options := &CreateUIResourceOptions{
-		URI:      uri,
-		Content:  content,
-		Encoding: encoding,
+	if options.Strict {
+		if err := checkStrict(options); err != nil {
+			return nil, err
+		}
 	}
-	for _, opt := range opts {
-		opt(options)
+	if err := options.URIVersion.validate(); err != nil {
+		return nil, err
+	}
+
+	parts, mimeType, err := renderContentParts(content, options)
+	if err != nil {
+		return nil, err
+	}
+
+	built := &builtResource{
+		uri:      uri,
+		mimeType: mimeType,
+		encoding: encoding,
+		content:  parts,
+		meta:     buildMetadata(options),
+	}
+
+	// Run the transformer chain between content processing and encoding. The
+	// content is only joined into one string when there are transformers.
+	if chain := transformerChain(options); len(chain) > 0 {
+		draft := &ResourceDraft{
+			URI:         uri,
+			ContentType: content.contentType(),
+			Content:     parts.String(),
+			MimeType:    mimeType,
+			Meta:        built.meta,
+		}
+		if options.Protocol != nil {
+			draft.Protocol = options.Protocol.Type
+		}
+		if err := applyTransformers(draft, chain); err != nil {
+			return nil, err
+		}
+		built.content = contentParts{draft.Content}
+		built.mimeType = draft.MimeType
+		built.meta = draft.Meta
+	}
+	if options.ContentHash {
+		if built.meta == nil {
+			built.meta = make(map[string]interface{})
+		}
+		built.uri = applyContentHash(built.meta, uri, built.content, options.URIVersion)
+	}
+	if len(built.meta) == 0 {
+		built.meta = nil
+	}
+
+	// Add embedded resource props
+	if built.annotations, err = embeddedAnnotations(options); err != nil {
+		return nil, err
+	}
+	if options.EmbeddedResourceProps != nil {
+		if meta, ok := options.EmbeddedResourceProps["_meta"]; ok {
+			if metaMap, ok := meta.(map[string]interface{}); ok {
+				built.outerMeta = cloneMap(metaMap)
+			}
+		}
+	}
+
+	return built, nil
+}
+
+// resource encodes the content and returns the UIResource
+func (b *builtResource) resource() *UIResource {
+	// Build resource content
+	resourceContent := ResourceContent{
+		URI:      b.uri,
+		MimeType: b.mimeType,
+		Meta:     b.meta,
+	}
+
+	// Apply encoding
+	switch b.encoding {
+	case EncodingText:
+		resourceContent.Text = b.content.String()
+	case EncodingBlob:
+		resourceContent.Blob = b.content.base64()
+	}
+
+	return &UIResource{
+		Type:        "resource",
+		Resource:    resourceContent,
+		Annotations: b.annotations,
+		Meta:        b.outerMeta,
 	}
+}
 
-	// Determine content string and MIME type
+// renderContent returns the content string and MIME type for a payload,
+// applying the configured protocol
+func renderContent(content ResourceContentPayload, options *CreateUIResourceOptions) (string, string, error) {
+	parts, mimeType, err := renderContentParts(content, options)
+	if err != nil {
+		return "", "", err
+	}
+	return parts.String(), mimeType, nil
+}
+
+// renderContentParts is renderContent without joining the content, so large
+// documents with an injected adapter tag are never copied
+func renderContentParts(content ResourceContentPayload, options *CreateUIResourceOptions) (contentParts, string, error) {
 	var contentString string
 	var mimeType string
 
@@ -77,124 +217,113 @@ func CreateUIResource(uri string, content ResourceContentPayload, encoding Encod
 			mimeType = MimeTypeRemoteDomWC
 		}
 	default:
-		return nil, fmt.Errorf("unsupported content type: %T", content)
+		return nil, "", fmt.Errorf("unsupported content type: %T", content)
 	}
 
-	// Apply protocol-specific script injection if protocol is configured (only for RawHTML content)
-	if options.Protocol != nil {
-		if _, isRawHTML := content.(*RawHTMLPayload); isRawHTML {
+	// Apply protocol-specific script injection if protocol is configured. Raw
+	// HTML gets the adapter script tag; Remote DOM, and external URLs when
+	// wrapping is enabled, are wrapped in a page for protocols whose hosts
+	// only render HTML.
+	if options.Protocol == nil {
+		return contentParts{contentString}, mimeType, nil
+	}
+	switch c := content.(type) {
+	case *RawHTMLPayload:
+		shimGen := getProtocolShimGenerator(options.Protocol)
+		if err := validateShim(shimGen); err != nil {
+			return nil, "", err
+		}
+		mimeType = shimGen.GetMIMEType()
+		if scriptTag := shimGen.GenerateScriptTag(); scriptTag != "" {
+			return injectScriptTag(contentString, scriptTag), mimeType, nil
+		}
+	case *RemoteDOMPayload:
+		if requiresHTMLHost(options.Protocol.Type) {
 			shimGen := getProtocolShimGenerator(options.Protocol)
-			scriptTag := shimGen.GenerateScriptTag()
-			if scriptTag != "" {
-				contentString = injectScriptTag(contentString, scriptTag)
+			if err := validateShim(shimGen); err != nil {
+				return nil, "", err
 			}
+			page, err := generateRemoteDOMHostPage(c, shimGen.GenerateScriptTag())
+			if err != nil {
+				return nil, "", err
+			}
+			contentString = page
 			mimeType = shimGen.GetMIMEType()
 		}
-	}
-
-	// Build resource content
-	resourceContent := ResourceContent{
-		URI:      uri,
-		MimeType: mimeType,
-	}
-
-	// Apply encoding
-	switch encoding {
-	case EncodingText:
-		resourceContent.Text = contentString
-	case EncodingBlob:
-		resourceContent.Blob = encodeBase64(contentString)
-	}
-
-	// Add metadata
-	resourceContent.Meta = buildMetadata(options)
-
-	// Build UI resource
-	resource := &UIResource{
-		Type:     "resource",
-		Resource: resourceContent,
-	}
-
-	// Add embedded resource props
-	if options.EmbeddedResourceProps != nil {
-		if annotations, ok := options.EmbeddedResourceProps["annotations"]; ok {
-			if annotationsMap, ok := annotations.(map[string]interface{}); ok {
-				resource.Annotations = annotationsMap
+	case *ExternalURLPayload:
+		if options.WrapExternalURL && requiresHTMLHost(options.Protocol.Type) {
+			shimGen := getProtocolShimGenerator(options.Protocol)
+			if err := validateShim(shimGen); err != nil {
+				return nil, "", err
 			}
-		}
-		if meta, ok := options.EmbeddedResourceProps["_meta"]; ok {
-			if metaMap, ok := meta.(map[string]interface{}); ok {
-				resource.Meta = metaMap
+			page, err := generateExternalURLWrapperPage(c, shimGen.GenerateScriptTag())
+			if err != nil {
+				return nil, "", err
 			}
+			contentString = page
+			mimeType = shimGen.GetMIMEType()
 		}
 	}
-
-	return resource, nil
+	return contentParts{contentString}, mimeType, nil
 }
 
 // injectScriptTag injects a script tag into HTML <head>.
 // This function enables protocol-based external adapter loading by injecting
 // a script reference, keeping the HTML content clean and the AI context window minimal.
-func injectScriptTag(htmlContent string, scriptTag string) string {
+// The tags are found case-insensitively at their byte offsets in htmlContent
+// and the result references slices of it, so the document is never copied.
+func injectScriptTag(htmlContent string, scriptTag string) contentParts {
 	// Check if there's a <head> tag
-	headStart := findInsensitive(htmlContent, "<head>")
-	headEnd := findInsensitive(htmlContent, "</head>")
+	headStart := indexTagFold(htmlContent, "<head>")
+	headEnd := indexTagFold(htmlContent, "</head>")
 
 	if headStart >= 0 && headEnd >= 0 && headEnd > headStart {
 		// Inject script tag after <head>
 		headTagEnd := headStart + len("<head>")
-		return htmlContent[:headTagEnd] + "\n" + scriptTag + htmlContent[headTagEnd:]
+		return contentParts{htmlContent[:headTagEnd], "\n", scriptTag, htmlContent[headTagEnd:]}
 	}
 
 	// Check if there's an <html> tag
-	htmlStart := findInsensitive(htmlContent, "<html>")
+	htmlStart := indexTagFold(htmlContent, "<html>")
 
 	if htmlStart >= 0 {
 		// Insert <head> with script tag after <html>
 		htmlTagEnd := htmlStart + len("<html>")
-		headSection := fmt.Sprintf("\n<head>\n%s\n</head>\n", scriptTag)
-		return htmlContent[:htmlTagEnd] + headSection + htmlContent[htmlTagEnd:]
+		return contentParts{htmlContent[:htmlTagEnd], "\n<head>\n", scriptTag, "\n</head>\n", htmlContent[htmlTagEnd:]}
 	}
 
 	// No <html> or <head> tag, wrap everything
-	return fmt.Sprintf("<html>\n<head>\n%s\n</head>\n<body>\n%s\n</body>\n</html>",
-		scriptTag, htmlContent)
-}
-
-// findInsensitive finds a substring case-insensitively and returns the index.
-// Returns -1 if not found.
-func findInsensitive(s, substr string) int {
-	sLower := toLower(s)
-	substrLower := toLower(substr)
-	return indexOf(sLower, substrLower)
+	return contentParts{"<html>\n<head>\n", scriptTag, "\n</head>\n<body>\n", htmlContent, "\n</body>\n</html>"}
 }
 
-// toLower converts a string to lowercase.
-func toLower(s string) string {
-	result := make([]rune, len(s))
-	for i, r := range s {
-		if r >= 'A' && r <= 'Z' {
-			result[i] = r + 32
-		} else {
-			result[i] = r
+// indexTagFold returns the byte offset of the first ASCII case-insensitive
+// match of tag, which must be lowercase, in s, or -1. Only ASCII letters are
+// folded, so offsets stay valid for multibyte text.
+func indexTagFold(s, tag string) int {
+	for i := 0; i+len(tag) <= len(s); i++ {
+		next := strings.IndexByte(s[i:], tag[0])
+		if next < 0 {
+			return -1
+		}
+		i += next
+		if i+len(tag) <= len(s) && equalFoldASCII(s[i:i+len(tag)], tag) {
+			return i
 		}
 	}
-	return string(result)
+	return -1
 }
 
-// indexOf finds the first occurrence of a substring.
-func indexOf(s, substr string) int {
-	if len(substr) == 0 {
-		return 0
-	}
-	if len(substr) > len(s) {
-		return -1
-	}
-
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
+// equalFoldASCII reports whether s equals the lowercase ASCII string lower,
+// ignoring the case of ASCII letters in s
+func equalFoldASCII(s, lower string) bool {
+	for i := 0; i < len(lower); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != lower[i] {
+			return false
 		}
 	}
-	return -1
+	return true
 }
@@ -0,0 +1,352 @@
+package mcpuiserver
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"unsafe"
+)
+
+// DefaultResourceCacheCapacity is the capacity of a ResourceCache created
+// with a non-positive capacity
+const DefaultResourceCacheCapacity = 256
+
+// ResourceCacheOption is a functional option for configuring a ResourceCache
+type ResourceCacheOption func(*ResourceCache)
+
+// WithSharedResources makes the cache return the cached *UIResource itself
+// instead of a clone. Callers must then treat returned resources, including
+// their metadata and annotation maps, as immutable.
+func WithSharedResources() ResourceCacheOption {
+	return func(c *ResourceCache) {
+		c.shared = true
+	}
+}
+
+// ResourceCacheStats is a snapshot of a ResourceCache's counters
+type ResourceCacheStats struct {
+	Hits      uint64 // Resources returned from the cache
+	Misses    uint64 // Resources created and stored
+	Bypassed  uint64 // Resources created without caching (see ResourceCache)
+	Evictions uint64 // Least recently used entries removed to stay in capacity
+	Len       int    // Entries currently cached
+	Capacity  int
+}
+
+// HitRate returns Hits / (Hits + Misses), or 0 before the first lookup
+func (s ResourceCacheStats) HitRate() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// ResourceCache memoizes CreateUIResource for tools that return the same
+// widget many times, skipping adapter injection, encoding and metadata
+// merging on repeated calls. It holds at most its capacity of resources and
+// evicts the least recently used one when full. It is safe for concurrent
+// use.
+//
+// Entries are keyed by the URI, a SHA-256 hash of the content payload, the
+// encoding, the protocol config and every other option that shapes the
+// result, along with the current default transformer chain. Resources built
+// with WithTransformers are not cached, because transformers cannot be
+// compared; they are created on every call and counted as Bypassed.
+//
+// While an entry is cached, the hash of its content is remembered by the
+// address and length of the content string. Go strings are immutable, so a
+// payload built from the same string, such as a package-level template, is
+// found without rehashing; any other content is hashed on every call.
+//
+// By default every call returns a Clone, which copies the maps and shares the
+// content strings, so callers may modify it. See WithSharedResources.
+type ResourceCache struct {
+	capacity int
+	shared   bool
+
+	mu      sync.Mutex
+	order   *list.List // Front is most recently used
+	entries map[resourceCacheKey]*list.Element
+	digests map[contentID]*contentDigest
+	stats   ResourceCacheStats
+}
+
+// contentID identifies the content string of a payload by its data pointer
+// and length, which determine its bytes while the string is alive
+type contentID struct {
+	contentType ContentType
+	framework   RemoteDOMFramework
+	data        *byte
+	length      int
+}
+
+// contentDigest is the hash of the content a contentID refers to. It holds
+// the string, so the data cannot be freed and reused while the digest is
+// remembered, and counts the cache entries using it.
+type contentDigest struct {
+	content string
+	sum     [sha256.Size]byte
+	refs    int
+}
+
+// resourceCacheEntry is the value of an order element
+type resourceCacheEntry struct {
+	key      resourceCacheKey
+	content  contentID
+	resource *UIResource
+}
+
+// resourceCacheKey identifies the resource CreateUIResource would build
+type resourceCacheKey struct {
+	uri         string
+	content     [sha256.Size]byte
+	encoding    Encoding
+	protocol    ProtocolType // Empty without a ProtocolConfig
+	version     string
+	baseURL     string
+	settings    resourceCacheSettings
+	values      string // Canonical JSON of the map and annotation options, empty when none is set
+	transformer uint64 // Default transformer chain generation
+}
+
+// resourceCacheSettings holds the scalar options of a cache key
+type resourceCacheSettings struct {
+	hasProtocol             bool
+	strict                  bool
+	wrapExternalURL         bool
+	contentHash             bool
+	skipDefaultTransformers bool
+	uriVersion              URIVersionStyle
+}
+
+// NewResourceCache creates a cache holding up to capacity resources.
+// Default configuration:
+//   - Capacity: DefaultResourceCacheCapacity when capacity is not positive
+//   - Returns clones rather than shared resources
+func NewResourceCache(capacity int, opts ...ResourceCacheOption) *ResourceCache {
+	if capacity <= 0 {
+		capacity = DefaultResourceCacheCapacity
+	}
+	c := &ResourceCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[resourceCacheKey]*list.Element),
+		digests:  make(map[contentID]*contentDigest),
+	}
+	c.stats.Capacity = capacity
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CreateUIResource returns the cached resource for these arguments, creating
+// and caching it with CreateUIResource on a miss. Errors are not cached.
+func (c *ResourceCache) CreateUIResource(uri string, content ResourceContentPayload, encoding Encoding, opts ...Option) (*UIResource, error) {
+	options := newResourceOptions(uri, content, encoding, opts)
+	key, id, data, ok := c.newKey(options)
+	if !ok {
+		built, err := buildResourceOptions(options)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.stats.Bypassed++
+		c.mu.Unlock()
+		return built.resource(), nil
+	}
+
+	if resource, ok := c.get(key); ok {
+		return c.result(resource), nil
+	}
+
+	built, err := buildResourceOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	resource := built.resource()
+	c.put(key, id, data, resource)
+	return c.result(resource), nil
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *ResourceCache) Stats() ResourceCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Len = c.order.Len()
+	return stats
+}
+
+// Purge removes every entry, keeping the counters
+func (c *ResourceCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[resourceCacheKey]*list.Element)
+	c.digests = make(map[contentID]*contentDigest)
+}
+
+func (c *ResourceCache) get(key resourceCacheKey) (*UIResource, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*resourceCacheEntry).resource, true
+}
+
+func (c *ResourceCache) put(key resourceCacheKey, id contentID, data string, resource *UIResource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Misses++
+	if elem, ok := c.entries[key]; ok {
+		// Another caller created the same resource concurrently
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&resourceCacheEntry{key: key, content: id, resource: resource})
+	digest, ok := c.digests[id]
+	if !ok {
+		digest = &contentDigest{content: data, sum: key.content}
+		c.digests[id] = digest
+	}
+	digest.refs++
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		entry := oldest.Value.(*resourceCacheEntry)
+		delete(c.entries, entry.key)
+		if digest := c.digests[entry.content]; digest != nil {
+			if digest.refs--; digest.refs == 0 {
+				delete(c.digests, entry.content)
+			}
+		}
+		c.stats.Evictions++
+	}
+}
+
+// result returns the cached resource or a clone of it
+func (c *ResourceCache) result(resource *UIResource) *UIResource {
+	if c.shared {
+		return resource
+	}
+	return resource.Clone()
+}
+
+// newKey resolves the options into a cache key, along with the identity and
+// data of the content string. It reports false when the resource cannot be
+// cached: invalid arguments, transformers, or options that cannot be encoded
+// as JSON.
+func (c *ResourceCache) newKey(options *CreateUIResourceOptions) (resourceCacheKey, contentID, string, bool) {
+	id, data, ok := newContentID(options.Content)
+	if !ok || len(options.Transformers) > 0 {
+		return resourceCacheKey{}, contentID{}, "", false
+	}
+
+	key := resourceCacheKey{
+		uri:      options.URI,
+		content:  c.digest(id, options.Content),
+		encoding: options.Encoding,
+		settings: resourceCacheSettings{
+			hasProtocol:             options.Protocol != nil,
+			strict:                  options.Strict,
+			wrapExternalURL:         options.WrapExternalURL,
+			contentHash:             options.ContentHash,
+			skipDefaultTransformers: options.SkipDefaultTransformers,
+			uriVersion:              options.URIVersion,
+		},
+	}
+
+	// Only options holding maps are encoded, so the common calls with a
+	// protocol and no metadata skip JSON entirely
+	var protocolConfig map[string]interface{}
+	if options.Protocol != nil {
+		key.protocol = options.Protocol.Type
+		key.version = options.Protocol.Version
+		key.baseURL = options.Protocol.BaseURL
+		protocolConfig = options.Protocol.Config
+	}
+	if protocolConfig != nil || options.UIMetadata != nil || options.Metadata != nil || options.ResourceProps != nil ||
+		options.EmbeddedResourceProps != nil || options.Annotations != nil {
+		values, err := json.Marshal([]interface{}{
+			protocolConfig, options.UIMetadata, options.Metadata, options.ResourceProps,
+			options.EmbeddedResourceProps, options.Annotations,
+		})
+		if err != nil {
+			return resourceCacheKey{}, contentID{}, "", false
+		}
+		key.values = string(values)
+	}
+	if !options.SkipDefaultTransformers {
+		key.transformer = defaultTransformersGeneration()
+	}
+	return key, id, data, true
+}
+
+// newContentID returns the identity and data of the content string of a
+// payload, or false for a nil payload
+func newContentID(content ResourceContentPayload) (contentID, string, bool) {
+	var id contentID
+	var data string
+	switch c := content.(type) {
+	case *RawHTMLPayload:
+		data = c.HTMLString
+	case *ExternalURLPayload:
+		data = c.IframeURL
+	case *RemoteDOMPayload:
+		id.framework, data = c.Framework, c.Script
+	default:
+		return contentID{}, "", false
+	}
+	id.contentType = content.contentType()
+	id.data, id.length = unsafe.StringData(data), len(data)
+	return id, data, true
+}
+
+// digest returns the hash of a payload, looking it up by id when a cached
+// entry holds the same content string
+func (c *ResourceCache) digest(id contentID, content ResourceContentPayload) [sha256.Size]byte {
+	c.mu.Lock()
+	digest, ok := c.digests[id]
+	c.mu.Unlock()
+	if ok {
+		return digest.sum
+	}
+	return hashPayload(content.contentType(), payloadFields(content))
+}
+
+// payloadFields returns the content fields of a payload
+func payloadFields(content ResourceContentPayload) contentParts {
+	switch c := content.(type) {
+	case *RawHTMLPayload:
+		return contentParts{c.HTMLString}
+	case *ExternalURLPayload:
+		return contentParts{c.IframeURL}
+	case *RemoteDOMPayload:
+		return contentParts{string(c.Framework), c.Script}
+	}
+	return nil
+}
+
+// hashPayload hashes the content type and fields of a payload, each field
+// prefixed with its length so different splits of the same bytes differ
+func hashPayload(contentType ContentType, fields contentParts) [sha256.Size]byte {
+	h := sha256.New()
+	var length [8]byte
+	for _, field := range append(contentParts{string(contentType)}, fields...) {
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		h.Write(length[:])
+		_ = contentParts{field}.writeTo(h) // hash.Hash never fails
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
@@ -0,0 +1,47 @@
+package mcpuiserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func BenchmarkCreateUIResource_Uncached(b *testing.B) {
+	content := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: largeHTML}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CreateUIResource("ui://bench", content, EncodingText, WithProtocol(ProtocolTypeMCPApps)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResourceCache_Hit(b *testing.B) {
+	cache := NewResourceCache(8)
+	content := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: largeHTML}
+	if _, err := cache.CreateUIResource("ui://bench", content, EncodingText, WithProtocol(ProtocolTypeMCPApps)); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.CreateUIResource("ui://bench", content, EncodingText, WithProtocol(ProtocolTypeMCPApps)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResourceCache_HitCopiedContent(b *testing.B) {
+	cache := NewResourceCache(8)
+	content := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: largeHTML}
+	if _, err := cache.CreateUIResource("ui://bench", content, EncodingText, WithProtocol(ProtocolTypeMCPApps)); err != nil {
+		b.Fatal(err)
+	}
+	copied := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: strings.Clone(largeHTML)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.CreateUIResource("ui://bench", copied, EncodingText, WithProtocol(ProtocolTypeMCPApps)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
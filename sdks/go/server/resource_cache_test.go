@@ -0,0 +1,282 @@
+package mcpuiserver
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceCache_HitAndMiss(t *testing.T) {
+	cache := NewResourceCache(8)
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	first, err := cache.CreateUIResource("ui://widget", html, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+	assert.NoError(t, err)
+	second, err := cache.CreateUIResource("ui://widget",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+	assert.NoError(t, err)
+
+	want, err := CreateUIResource("ui://widget", html, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+	assert.NoError(t, err)
+	assert.Equal(t, want, first)
+	assert.Equal(t, want, second)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Len)
+	assert.Equal(t, 8, stats.Capacity)
+	assert.Equal(t, 0.5, stats.HitRate())
+}
+
+func TestResourceCache_KeyedByArguments(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	tests := []struct {
+		name     string
+		uri      string
+		content  ResourceContentPayload
+		encoding Encoding
+		opts     []Option
+	}{
+		{name: "uri", uri: "ui://other", content: html, encoding: EncodingText},
+		{name: "content", uri: "ui://widget", content: &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Bye</p>"}, encoding: EncodingText},
+		{name: "content type", uri: "ui://widget", content: &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "<p>Hi</p>", Framework: FrameworkReact}, encoding: EncodingText},
+		{name: "encoding", uri: "ui://widget", content: html, encoding: EncodingBlob},
+		{name: "protocol", uri: "ui://widget", content: html, encoding: EncodingText, opts: []Option{WithProtocol(ProtocolTypeAppsSDK)}},
+		{name: "protocol config", uri: "ui://widget", content: html, encoding: EncodingText, opts: []Option{
+			WithProtocolConfig(&ProtocolConfig{Type: ProtocolTypeAppsSDK, BaseURL: "https://cdn.example.com"})}},
+		{name: "metadata", uri: "ui://widget", content: html, encoding: EncodingText, opts: []Option{
+			WithMetadata(map[string]interface{}{"build": "2"})}},
+		{name: "content hash", uri: "ui://widget", content: html, encoding: EncodingText, opts: []Option{WithContentHash()}},
+		{name: "annotations", uri: "ui://widget", content: html, encoding: EncodingText, opts: []Option{WithAudience(RoleUser)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewResourceCache(8)
+			_, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+			assert.NoError(t, err)
+
+			got, err := cache.CreateUIResource(tt.uri, tt.content, tt.encoding, tt.opts...)
+			assert.NoError(t, err)
+			want, err := CreateUIResource(tt.uri, tt.content, tt.encoding, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			stats := cache.Stats()
+			assert.Equal(t, uint64(0), stats.Hits)
+			assert.Equal(t, 2, stats.Len)
+		})
+	}
+}
+
+func TestResourceCache_ClonesDoNotAlias(t *testing.T) {
+	cache := NewResourceCache(8)
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	opts := []Option{
+		WithMetadata(map[string]interface{}{"build": "1"}),
+		WithEmbeddedResourceProps(map[string]interface{}{
+			"annotations": map[string]interface{}{"priority": 0.5},
+			"_meta":       map[string]interface{}{"tool": "chart"},
+		}),
+	}
+
+	first, err := cache.CreateUIResource("ui://widget", html, EncodingText, opts...)
+	assert.NoError(t, err)
+	first.Resource.Text = "changed"
+	first.Resource.Meta["build"] = "changed"
+	first.Annotations["priority"] = 1.0
+	first.Meta["tool"] = "changed"
+
+	second, err := cache.CreateUIResource("ui://widget", html, EncodingText, opts...)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, "<p>Hi</p>", second.Resource.Text)
+	assert.Equal(t, "1", second.Resource.Meta["build"])
+	assert.Equal(t, 0.5, second.Annotations["priority"])
+	assert.Equal(t, "chart", second.Meta["tool"])
+}
+
+func TestResourceCache_SharedResources(t *testing.T) {
+	cache := NewResourceCache(8, WithSharedResources())
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	first, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+	second, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+}
+
+func TestResourceCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewResourceCache(2, WithSharedResources())
+	create := func(uri string) *UIResource {
+		resource, err := cache.CreateUIResource(uri, &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText)
+		assert.NoError(t, err)
+		return resource
+	}
+
+	a := create("ui://a")
+	create("ui://b")
+	assert.Same(t, a, create("ui://a"), "a is now most recently used")
+	create("ui://c") // evicts b
+
+	assert.Same(t, a, create("ui://a"))
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Len)
+
+	create("ui://b")
+	stats = cache.Stats()
+	assert.Equal(t, uint64(4), stats.Misses, "b was created again")
+	assert.Equal(t, uint64(2), stats.Evictions)
+}
+
+func TestResourceCache_DefaultCapacity(t *testing.T) {
+	assert.Equal(t, DefaultResourceCacheCapacity, NewResourceCache(0).Stats().Capacity)
+	assert.Equal(t, DefaultResourceCacheCapacity, NewResourceCache(-1).Stats().Capacity)
+}
+
+func TestResourceCache_Bypassed(t *testing.T) {
+	cache := NewResourceCache(8, WithSharedResources())
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	transformer := WithTransformers(AddMetadata(map[string]interface{}{"build": "1"}))
+
+	first, err := cache.CreateUIResource("ui://widget", html, EncodingText, transformer)
+	assert.NoError(t, err)
+	second, err := cache.CreateUIResource("ui://widget", html, EncodingText, transformer)
+	assert.NoError(t, err)
+
+	assert.NotSame(t, first, second)
+	assert.Equal(t, first, second)
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Bypassed)
+	assert.Equal(t, uint64(0), stats.Hits+stats.Misses)
+	assert.Equal(t, 0, stats.Len)
+}
+
+func TestResourceCache_DefaultTransformersChange(t *testing.T) {
+	defer SetDefaultTransformers()
+	cache := NewResourceCache(8)
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	SetDefaultTransformers(AddMetadata(map[string]interface{}{"build": "1"}))
+	first, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", first.Resource.Meta["build"])
+
+	SetDefaultTransformers(AddMetadata(map[string]interface{}{"build": "2"}))
+	second, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", second.Resource.Meta["build"], "entries from the old chain are not reused")
+
+	_, err = cache.CreateUIResource("ui://widget", html, EncodingText, WithoutDefaultTransformers())
+	assert.NoError(t, err)
+	SetDefaultTransformers()
+	_, err = cache.CreateUIResource("ui://widget", html, EncodingText, WithoutDefaultTransformers())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), cache.Stats().Hits, "skipping the chain ignores its changes")
+}
+
+func TestResourceCache_ErrorsNotCached(t *testing.T) {
+	cache := NewResourceCache(8)
+
+	_, err := cache.CreateUIResource("http://widget", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText)
+	assert.ErrorIs(t, err, ErrInvalidURI)
+	_, err = cache.CreateUIResource("ui://widget", nil, EncodingText)
+	assert.Error(t, err)
+
+	stats := cache.Stats()
+	assert.Equal(t, 0, stats.Len)
+	assert.Equal(t, uint64(0), stats.Bypassed)
+}
+
+func TestResourceCache_Purge(t *testing.T) {
+	cache := NewResourceCache(8)
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	_, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+	cache.Purge()
+	_, err = cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, 1, stats.Len)
+}
+
+func TestResourceCache_PayloadChangedInPlace(t *testing.T) {
+	cache := NewResourceCache(8)
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+
+	_, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+
+	html.HTMLString = "<p>Yo</p>"
+	got, err := cache.CreateUIResource("ui://widget", html, EncodingText)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Yo</p>", got.Resource.Text, "content of the same length is not mistaken for the old one")
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(0), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+}
+
+func TestResourceCache_ContentDigests(t *testing.T) {
+	cache := NewResourceCache(2)
+	template := "<p>Shared template</p>"
+
+	for _, uri := range []string{"ui://a", "ui://b"} {
+		_, err := cache.CreateUIResource(uri, &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: template}, EncodingText)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, cache.digests, 1, "entries built from the same string share a digest")
+
+	_, err := cache.CreateUIResource("ui://a", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: template}, EncodingText)
+	assert.NoError(t, err)
+	copied := strings.Clone(template)
+	_, err = cache.CreateUIResource("ui://a", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: copied}, EncodingText)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), cache.Stats().Hits, "a copy of the string hits by content hash")
+
+	for _, uri := range []string{"ui://c", "ui://d"} {
+		_, err := cache.CreateUIResource(uri, &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>" + uri + "</p>"}, EncodingText)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, cache.digests, 2, "evicted entries release their digest")
+
+	cache.Purge()
+	assert.Empty(t, cache.digests)
+}
+
+func TestResourceCache_Concurrent(t *testing.T) {
+	cache := NewResourceCache(4)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: fmt.Sprintf("<p>%d</p>", j%6)}
+				resource, err := cache.CreateUIResource("ui://widget", html, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+				assert.NoError(t, err)
+				assert.NotEmpty(t, resource.Resource.Blob)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(16*50), stats.Hits+stats.Misses)
+	assert.LessOrEqual(t, stats.Len, 4)
+}
+
+func TestResourceCacheStats_HitRate(t *testing.T) {
+	assert.Equal(t, 0.0, ResourceCacheStats{}.HitRate())
+	assert.Equal(t, 0.75, ResourceCacheStats{Hits: 3, Misses: 1, Bypassed: 10}.HitRate())
+}
@@ -0,0 +1,328 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema draft used by the generated schemas
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema document. It marshals to JSON directly.
+type JSONSchema map[string]interface{}
+
+// Names of the non-message schemas returned by JSONSchemas
+const (
+	SchemaNameUIActionResult = "UIActionResult"
+	SchemaNameRenderData     = "RenderData"
+	SchemaNameUIResource     = "UIResource"
+	SchemaNameProtocolError  = "ProtocolError"
+)
+
+// schemaEnums lists the allowed values of the named string types that appear
+// in generated schemas
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(DisplayMode("")):        {string(DisplayModeInline), string(DisplayModePIP), string(DisplayModeFullscreen)},
+	reflect.TypeOf(ProtocolErrorCode("")):  {string(ErrorCodeUnsupported), string(ErrorCodeTimeout), string(ErrorCodeDenied), string(ErrorCodeInvalidParams), string(ErrorCodeHostError)},
+	reflect.TypeOf(LogLevel("")):           {string(LogLevelDebug), string(LogLevelInfo), string(LogLevelWarn), string(LogLevelError), string(LogLevelSilent)},
+	reflect.TypeOf(ContentType("")):        {string(ContentTypeRawHTML), string(ContentTypeExternalURL), string(ContentTypeRemoteDOM)},
+	reflect.TypeOf(Encoding("")):           {string(EncodingText), string(EncodingBlob)},
+	reflect.TypeOf(RemoteDOMFramework("")): {string(FrameworkReact), string(FrameworkWebComponents)},
+	reflect.TypeOf(Role("")):               {string(RoleUser), string(RoleAssistant)},
+}
+
+// schemaExtender is implemented by types whose JSON Schema carries
+// constraints that cannot be derived from struct tags, mirroring the checks
+// made when messages are parsed
+type schemaExtender interface {
+	extendJSONSchema(s JSONSchema)
+}
+
+var (
+	schemaExtenderType = reflect.TypeOf((*schemaExtender)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+	rawMessageType     = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// GenerateJSONSchema derives a draft 2020-12 JSON Schema from the Go type of
+// v, following the encoding/json rules for field names, omitempty, omitzero
+// and embedded structs. time.Time values are date-time strings.
+//
+// Fields without omitempty or omitzero are required unless they can be nil,
+// in which case they also accept null. Structs reject unknown properties,
+// matching the strict decoding of ParseMessage.
+func GenerateJSONSchema(v interface{}) JSONSchema {
+	t := reflect.TypeOf(v)
+	schema := schemaFor(t)
+	schema["$schema"] = JSONSchemaDialect
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && t.Name() != "" {
+		schema["title"] = t.Name()
+	}
+	return schema
+}
+
+// MessageJSONSchema returns the JSON Schema of a protocol message, with the
+// "type" property fixed to t. It returns an *UnknownMessageTypeError for
+// types outside the protocol.
+func MessageJSONSchema(t ProtocolMessageType) (JSONSchema, error) {
+	msg, ok := newMessage(t)
+	if !ok {
+		return nil, &UnknownMessageTypeError{Type: string(t)}
+	}
+	schema := GenerateJSONSchema(msg)
+	schema["title"] = string(t)
+	schema["properties"].(JSONSchema)["type"] = JSONSchema{"const": string(t)}
+	return schema, nil
+}
+
+// JSONSchemas returns the JSON Schema of every protocol message keyed by its
+// ProtocolMessageType, together with the SchemaNameUIActionResult,
+// SchemaNameRenderData, SchemaNameUIResource and SchemaNameProtocolError
+// schemas. The UIActionResult schema accepts any of the widget action
+// messages: tool, prompt, link, intent and notify.
+//
+// Example:
+//
+//	for name, schema := range mcpuiserver.JSONSchemas() {
+//	    data, _ := json.MarshalIndent(schema, "", "  ")
+//	    os.WriteFile(name+".schema.json", data, 0o644)
+//	}
+func JSONSchemas() map[string]JSONSchema {
+	schemas := make(map[string]JSONSchema, len(messageTypes)+4)
+	for _, t := range messageTypes {
+		schema, _ := MessageJSONSchema(t)
+		schemas[string(t)] = schema
+	}
+
+	actions := make([]interface{}, 0, 5)
+	for _, t := range []ProtocolMessageType{MessageTypeToolCall, MessageTypePrompt, MessageTypeLink, MessageTypeIntent, MessageTypeNotify} {
+		schema, _ := MessageJSONSchema(t)
+		delete(schema, "$schema")
+		actions = append(actions, schema)
+	}
+	schemas[SchemaNameUIActionResult] = JSONSchema{
+		"$schema": JSONSchemaDialect,
+		"title":   SchemaNameUIActionResult,
+		"oneOf":   actions,
+	}
+
+	schemas[SchemaNameRenderData] = GenerateJSONSchema(RenderData{})
+	schemas[SchemaNameUIResource] = GenerateJSONSchema(UIResource{})
+	schemas[SchemaNameProtocolError] = GenerateJSONSchema(ProtocolError{})
+	return schemas
+}
+
+// schemaFor builds the schema of a single Go type
+func schemaFor(t reflect.Type) JSONSchema {
+	if t == nil {
+		return JSONSchema{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var schema JSONSchema
+	switch {
+	case t == timeType:
+		schema = JSONSchema{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		schema = JSONSchema{}
+	default:
+		schema = kindSchema(t)
+	}
+
+	if reflect.PointerTo(t).Implements(schemaExtenderType) {
+		reflect.New(t).Interface().(schemaExtender).extendJSONSchema(schema)
+	}
+	return schema
+}
+
+func kindSchema(t reflect.Type) JSONSchema {
+	switch t.Kind() {
+	case reflect.String:
+		schema := JSONSchema{"type": "string"}
+		if values, ok := schemaEnums[t]; ok {
+			schema["enum"] = values
+		}
+		return schema
+	case reflect.Bool:
+		return JSONSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return JSONSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return JSONSchema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes byte slices as base64 strings
+			return JSONSchema{"type": "string", "contentEncoding": "base64"}
+		}
+		return JSONSchema{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		schema := JSONSchema{"type": "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			schema["additionalProperties"] = schemaFor(t.Elem())
+		}
+		return schema
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		// interface{} and anything else encoding/json accepts as-is
+		return JSONSchema{}
+	}
+}
+
+func structSchema(t reflect.Type) JSONSchema {
+	properties := JSONSchema{}
+	required := []string{}
+	addStructFields(t, properties, &required)
+
+	schema := JSONSchema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addStructFields adds the JSON properties of t, flattening embedded structs
+// the way encoding/json does
+func addStructFields(t reflect.Type, properties JSONSchema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				addStructFields(fieldType, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := schemaFor(fieldType)
+		optional := strings.Contains(","+opts+",", ",omitempty,") || strings.Contains(","+opts+",", ",omitzero,")
+		switch {
+		case optional:
+		case nilable(fieldType):
+			allowNull(schema)
+		case fieldType.Kind() == reflect.Struct && fieldType != timeType && !hasRequirements(schema):
+			// A missing struct decodes to its zero value, which is only
+			// rejected when one of its own fields is required
+		default:
+			if _, isEnum := schema["enum"]; fieldType.Kind() == reflect.String && !isEnum {
+				schema["minLength"] = 1
+			}
+			*required = append(*required, name)
+		}
+		properties[name] = schema
+	}
+}
+
+func hasRequirements(schema JSONSchema) bool {
+	_, required := schema["required"]
+	_, anyOf := schema["anyOf"]
+	return required || anyOf
+}
+
+func nilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// allowNull widens a schema to also accept null
+func allowNull(schema JSONSchema) {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+	}
+}
+
+// Constraints checked by ParseMessage and CreateUIResource
+
+// pixelSchema describes a CSS pixel length. Decoders accept fractional
+// values and round them to the nearest int32 (see pixels.Round).
+func pixelSchema() JSONSchema {
+	return JSONSchema{"type": "number", "minimum": math.MinInt32, "maximum": math.MaxInt32}
+}
+
+func (*SizeChangePayload) extendJSONSchema(s JSONSchema) {
+	properties := s["properties"].(JSONSchema)
+	for _, name := range []string{"width", "height"} {
+		// Values above -0.5 round to 0, the smallest size accepted
+		schema := pixelSchema()
+		delete(schema, "minimum")
+		schema["exclusiveMinimum"] = -0.5
+		properties[name] = schema
+	}
+	s["anyOf"] = []interface{}{
+		JSONSchema{"required": []string{"width"}},
+		JSONSchema{"required": []string{"height"}},
+	}
+}
+
+func (*RenderData) extendJSONSchema(s JSONSchema) {
+	s["properties"].(JSONSchema)["maxHeight"] = pixelSchema()
+}
+
+func (*ToolInputPartialPayload) extendJSONSchema(s JSONSchema) {
+	s["properties"].(JSONSchema)["arguments"] = JSONSchema{"type": "object"}
+	s["required"] = []string{"arguments"}
+}
+
+func (*MessageResponsePayload) extendJSONSchema(s JSONSchema) {
+	// Runtimes older than ProtocolError sent plain strings or {message}
+	// objects, which MessageResponsePayload.ProtocolError still accepts
+	s["properties"].(JSONSchema)["error"] = JSONSchema{
+		"anyOf": []interface{}{
+			schemaFor(reflect.TypeOf(ProtocolError{})),
+			JSONSchema{"type": "string"},
+			JSONSchema{"type": "object", "properties": JSONSchema{"message": JSONSchema{"type": "string"}}},
+		},
+	}
+}
+
+func (*ProtocolError) extendJSONSchema(s JSONSchema) {
+	// Runtimes forward host error messages verbatim, which may be empty
+	delete(s["properties"].(JSONSchema)["message"].(JSONSchema), "minLength")
+}
+
+func (*ResourceContent) extendJSONSchema(s JSONSchema) {
+	s["properties"].(JSONSchema)["uri"].(JSONSchema)["pattern"] = "^" + URIScheme
+}
+
+func (*UIResource) extendJSONSchema(s JSONSchema) {
+	properties := s["properties"].(JSONSchema)
+	properties["type"] = JSONSchema{"const": "resource"}
+	properties["annotations"] = schemaFor(reflect.TypeOf(Annotations{}))
+}
+
+func (*Annotations) extendJSONSchema(s JSONSchema) {
+	priority := s["properties"].(JSONSchema)["priority"].(JSONSchema)
+	priority["minimum"] = 0
+	priority["maximum"] = 1
+}
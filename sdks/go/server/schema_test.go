@@ -0,0 +1,353 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchemas_Coverage(t *testing.T) {
+	schemas := JSONSchemas()
+
+	names := []string{SchemaNameUIActionResult, SchemaNameRenderData, SchemaNameUIResource, SchemaNameProtocolError}
+	for _, messageType := range messageTypes {
+		names = append(names, string(messageType))
+	}
+	assert.Len(t, schemas, len(names))
+
+	for _, name := range names {
+		schema, ok := schemas[name]
+		if assert.True(t, ok, "missing schema %q", name) {
+			assert.Equal(t, JSONSchemaDialect, schema["$schema"])
+			assert.Equal(t, name, schema["title"])
+			_, err := json.Marshal(schema)
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestMessageJSONSchema_UnknownType(t *testing.T) {
+	schema, err := MessageJSONSchema("ui-custom")
+	assert.ErrorIs(t, err, ErrUnknownMessageType)
+	assert.Nil(t, schema)
+}
+
+// The schemas must accept the messages ParseMessage accepts and reject the
+// ones it rejects for missing or mistyped fields
+func TestMessageJSONSchema_MatchesParseMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		valid bool
+	}{
+		{name: "tool call", data: `{"type":"tool","messageId":"msg-1","payload":{"toolName":"fetchData","params":{"query":"stats"}}}`, valid: true},
+		{name: "tool call with null params", data: `{"type":"tool","payload":{"toolName":"fetchData","params":null}}`, valid: true},
+		{name: "prompt", data: `{"type":"prompt","payload":{"prompt":"Hello"}}`, valid: true},
+		{name: "link", data: `{"type":"link","payload":{"url":"https://example.com"}}`, valid: true},
+		{name: "intent", data: `{"type":"intent","payload":{"intent":"showSettings","params":{"tab":"account"}}}`, valid: true},
+		{name: "notify", data: `{"type":"notify","payload":{"message":"Saved"}}`, valid: true},
+		{name: "lifecycle ready", data: `{"type":"ui-lifecycle-iframe-ready"}`, valid: true},
+		{name: "size change", data: `{"type":"ui-size-change","payload":{"width":400}}`, valid: true},
+		{name: "request data", data: `{"type":"ui-request-data","messageId":"msg-1","payload":{"requestType":"userStats"}}`, valid: true},
+		{name: "request render data", data: `{"type":"ui-request-render-data","messageId":"msg-1"}`, valid: true},
+		{name: "render data", data: `{"type":"ui-lifecycle-iframe-render-data","payload":{"renderData":{"theme":"dark","displayMode":"pip"}}}`, valid: true},
+		{name: "message received", data: `{"type":"ui-message-received","payload":{"messageId":"msg-1"}}`, valid: true},
+		{name: "message response", data: `{"type":"ui-message-response","payload":{"messageId":"msg-1","response":{"ok":true}}}`, valid: true},
+		{name: "protocol error response", data: `{"type":"ui-message-response","payload":{"messageId":"msg-1","error":{"code":"denied","message":"no"}}}`, valid: true},
+		{name: "legacy error response", data: `{"type":"ui-message-response","payload":{"messageId":"msg-1","error":"Timeout"}}`, valid: true},
+		{name: "tool cancelled", data: `{"type":"ui-lifecycle-tool-cancelled","payload":{"reason":"user"}}`, valid: true},
+		{name: "teardown without payload", data: `{"type":"ui-lifecycle-teardown"}`, valid: true},
+		{name: "tool input partial", data: `{"type":"ui-lifecycle-tool-input-partial","payload":{"arguments":{"city":"Par"}}}`, valid: true},
+
+		{name: "unknown field", data: `{"type":"prompt","payload":{"prompt":"hi","extra":1}}`},
+		{name: "mistyped field", data: `{"type":"tool","payload":{"toolName":42}}`},
+		{name: "missing tool name", data: `{"type":"tool","payload":{}}`},
+		{name: "missing prompt", data: `{"type":"prompt"}`},
+		{name: "missing url", data: `{"type":"link","payload":{"url":""}}`},
+		{name: "missing intent", data: `{"type":"intent","payload":{"params":{}}}`},
+		{name: "missing notification", data: `{"type":"notify","payload":{}}`},
+		{name: "empty size change", data: `{"type":"ui-size-change","payload":{}}`},
+		{name: "negative size", data: `{"type":"ui-size-change","payload":{"height":-1}}`},
+		{name: "request data without id", data: `{"type":"ui-request-data","payload":{"requestType":"x"}}`},
+		{name: "request data without type", data: `{"type":"ui-request-data","messageId":"1","payload":{}}`},
+		{name: "ack without id", data: `{"type":"ui-message-received","payload":{}}`},
+		{name: "response without id", data: `{"type":"ui-message-response","payload":{"response":1}}`},
+		{name: "partial input without arguments", data: `{"type":"ui-lifecycle-tool-input-partial","payload":{}}`},
+		{name: "partial input with null arguments", data: `{"type":"ui-lifecycle-tool-input-partial","payload":{"arguments":null}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.data), &value))
+
+			schema, err := MessageJSONSchema(ProtocolMessageType(value["type"].(string)))
+			assert.NoError(t, err)
+			schemaErr := validateJSONSchema(t, schema, value)
+			_, parseErr := ParseMessage([]byte(tt.data))
+
+			if tt.valid {
+				assert.NoError(t, schemaErr)
+				assert.NoError(t, parseErr)
+			} else {
+				assert.Error(t, schemaErr)
+				assert.Error(t, parseErr)
+			}
+		})
+	}
+}
+
+func TestMessageJSONSchema_PixelFieldsMatchDecoder(t *testing.T) {
+	messages := map[string]string{
+		"width":     `{"type":"ui-size-change","payload":{"width":%s}}`,
+		"height":    `{"type":"ui-size-change","payload":{"height":%s}}`,
+		"maxHeight": `{"type":"ui-lifecycle-iframe-render-data","payload":{"renderData":{"maxHeight":%s}}}`,
+	}
+	values := []string{"0", "300", "412.5", "-0.4", "-0.5", "-1", "2147483647", "2147483647.6", "1e12", `"300"`}
+
+	for _, field := range sortedKeys(messages) {
+		for _, v := range values {
+			t.Run(field+"="+v, func(t *testing.T) {
+				data := fmt.Sprintf(messages[field], v)
+				var value map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(data), &value))
+
+				schema, err := MessageJSONSchema(ProtocolMessageType(value["type"].(string)))
+				assert.NoError(t, err)
+				schemaErr := validateJSONSchema(t, schema, value)
+				_, parseErr := ParseMessage([]byte(data))
+
+				assert.Equal(t, parseErr == nil, schemaErr == nil, "parse: %v, schema: %v", parseErr, schemaErr)
+			})
+		}
+	}
+}
+
+func TestJSONSchemas_AcceptSDKOutput(t *testing.T) {
+	height := 300
+	resource, err := CreateUIResource("ui://test/widget", &RawHTMLPayload{
+		Type:       ContentTypeRawHTML,
+		HTMLString: "<p>Hi</p>",
+	}, EncodingBlob, WithUIMetadata(map[string]interface{}{UIMetadataKeyPreferredFrameSize: []string{"800px", "600px"}}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		schema string
+		value  interface{}
+	}{
+		{schema: SchemaNameUIResource, value: resource},
+		{schema: SchemaNameRenderData, value: RenderData{Theme: "dark", DisplayMode: DisplayModeFullscreen, MaxHeight: 600}},
+		{schema: SchemaNameProtocolError, value: NewDeniedError("blocked")},
+		{schema: SchemaNameUIActionResult, value: UIActionResultToolCall("fetchData", nil)},
+		{schema: SchemaNameUIActionResult, value: UIActionResultNotification("Saved")},
+		{schema: string(MessageTypeSizeChange), value: NewSizeChangeMessage(nil, &height, nil)},
+		{schema: string(MessageTypeMessageResponse), value: NewMessageResponseMessage("m", nil, NewTimeoutError("late"), nil)},
+		{schema: string(MessageTypeLifecycleRenderData), value: NewRenderDataMessage(RenderData{Locale: "en-US"}, nil)},
+	}
+
+	schemas := JSONSchemas()
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema := schemas[tt.schema]
+			assert.NoError(t, validateJSONSchema(t, schema, tt.value))
+		})
+	}
+
+	assert.Error(t, validateJSONSchema(t, schemas[SchemaNameUIResource], map[string]interface{}{
+		"type":     "resource",
+		"resource": map[string]interface{}{"uri": "http://example.com", "mimeType": "text/html"},
+	}), "URIs must use the ui:// scheme")
+	assert.Error(t, validateJSONSchema(t, schemas[SchemaNameRenderData], map[string]interface{}{
+		"displayMode": "floating",
+	}), "display modes are limited to the DisplayMode constants")
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	type Embedded struct {
+		Shared string `json:"shared,omitempty"`
+	}
+	type Example struct {
+		Embedded
+		Name      string                 `json:"name"`
+		Mode      DisplayMode            `json:"mode"`
+		Count     int                    `json:"count,omitempty"`
+		Ratio     float64                `json:"ratio,omitempty"`
+		Tags      []string               `json:"tags"`
+		Labels    map[string]string      `json:"labels,omitempty"`
+		Extra     map[string]interface{} `json:"extra,omitempty"`
+		Payload   json.RawMessage        `json:"payload,omitempty"`
+		Data      []byte                 `json:"data,omitempty"`
+		Updated   time.Time              `json:"updated"`
+		Ignored   string                 `json:"-"`
+		Untagged  bool
+		unexposed string
+	}
+
+	schema := GenerateJSONSchema(&Example{})
+	assert.Equal(t, JSONSchema{
+		"$schema":              JSONSchemaDialect,
+		"title":                "Example",
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": JSONSchema{
+			"shared":   JSONSchema{"type": "string"},
+			"name":     JSONSchema{"type": "string", "minLength": 1},
+			"mode":     JSONSchema{"type": "string", "enum": []string{"inline", "pip", "fullscreen"}},
+			"count":    JSONSchema{"type": "integer"},
+			"ratio":    JSONSchema{"type": "number"},
+			"tags":     JSONSchema{"type": []string{"array", "null"}, "items": JSONSchema{"type": "string"}},
+			"labels":   JSONSchema{"type": "object", "additionalProperties": JSONSchema{"type": "string"}},
+			"extra":    JSONSchema{"type": "object"},
+			"payload":  JSONSchema{},
+			"data":     JSONSchema{"type": "string", "contentEncoding": "base64"},
+			"updated":  JSONSchema{"type": "string", "format": "date-time"},
+			"Untagged": JSONSchema{"type": "boolean"},
+		},
+		"required": []string{"name", "mode", "updated", "Untagged"},
+	}, schema)
+}
+
+// roundTrip converts v to its generic JSON form, as a validator would see it
+func roundTrip(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	var out interface{}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	return out
+}
+
+// validateJSONSchema checks value against the subset of JSON Schema used by
+// the generated schemas. Both are compared in their encoded JSON form, so the
+// check also covers serialization.
+func validateJSONSchema(t *testing.T, schema JSONSchema, value interface{}) error {
+	t.Helper()
+	return validateNode(roundTrip(t, schema).(map[string]interface{}), roundTrip(t, value), "$")
+}
+
+func validateNode(schema map[string]interface{}, value interface{}, path string) error {
+	if c, ok := schema["const"]; ok && c != value {
+		return fmt.Errorf("%s: want %v, got %v", path, c, value)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == value
+		}
+		if !found {
+			return fmt.Errorf("%s: %v not in %v", path, value, enum)
+		}
+	}
+	if typ, ok := schema["type"]; ok && !matchesType(typ, value) {
+		return fmt.Errorf("%s: want type %v, got %T", path, typ, value)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		alternatives, ok := schema[key].([]interface{})
+		if !ok {
+			continue
+		}
+		matches := 0
+		for _, alt := range alternatives {
+			if validateNode(alt.(map[string]interface{}), value, path) == nil {
+				matches++
+			}
+		}
+		if matches == 0 || (key == "oneOf" && matches > 1) {
+			return fmt.Errorf("%s: %d alternatives of %s match", path, matches, key)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("%s: shorter than %v", path, min)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return fmt.Errorf("%s: does not match %s", path, pattern)
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			return fmt.Errorf("%s: below %v", path, min)
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && v <= min {
+			return fmt.Errorf("%s: not above %v", path, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			return fmt.Errorf("%s: above %v", path, max)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateNode(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[name.(string)]; !ok {
+					return fmt.Errorf("%s: missing %s", path, name)
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, item := range v {
+			if property, ok := properties[name]; ok {
+				if err := validateNode(property.(map[string]interface{}), item, path+"."+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s: unexpected property %s", path, name)
+				}
+			case map[string]interface{}:
+				if err := validateNode(additional, item, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func matchesType(typ interface{}, value interface{}) bool {
+	if types, ok := typ.([]interface{}); ok {
+		for _, t := range types {
+			if matchesType(t, value) {
+				return true
+			}
+		}
+		return false
+	}
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "null":
+		return value == nil
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+package mcpuiserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// contentParts is resource content kept as the pieces it was assembled from,
+// such as a document split around an injected adapter tag, so it can be
+// encoded or written without first joining it into one string
+type contentParts []string
+
+// Len returns the length of the content in bytes
+func (p contentParts) Len() int {
+	n := 0
+	for _, part := range p {
+		n += len(part)
+	}
+	return n
+}
+
+// String joins the parts, without copying when there is only one
+func (p contentParts) String() string {
+	if len(p) == 1 {
+		return p[0]
+	}
+	var b strings.Builder
+	b.Grow(p.Len())
+	for _, part := range p {
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// base64 returns the standard base64 encoding of the content, encoded
+// straight into the result
+func (p contentParts) base64() string {
+	var b strings.Builder
+	b.Grow(base64.StdEncoding.EncodedLen(p.Len()))
+	_ = p.writeBase64(&b) // strings.Builder never fails
+	return b.String()
+}
+
+// writeBase64 streams the standard base64 encoding of the content to w
+func (p contentParts) writeBase64(w io.Writer) error {
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if err := p.writeTo(enc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeTo writes the content to w through a small chunk buffer, for writers
+// such as encoders and hashes that only accept byte slices
+func (p contentParts) writeTo(w io.Writer) error {
+	var chunk [3 * 1024]byte
+	for _, part := range p {
+		for len(part) > 0 {
+			n := copy(chunk[:], part)
+			if _, err := w.Write(chunk[:n]); err != nil {
+				return err
+			}
+			part = part[n:]
+		}
+	}
+	return nil
+}
+
+// jsonChunkSize is how much content is escaped into the scratch buffer
+// before it is written out
+const jsonChunkSize = 16 * 1024
+
+// writeJSONString writes the content as a JSON string, escaped exactly as
+// encoding/json escapes strings. The content is escaped chunk by chunk into
+// one scratch buffer; chunks end on character boundaries and parts are split
+// on ASCII boundaries, so multibyte characters are never split.
+func (p contentParts) writeJSONString(out jsonOutput) error {
+	buf := make([]byte, 0, jsonChunkSize+jsonChunkSize/2)
+	buf = append(buf, '"')
+	for _, part := range p {
+		for len(part) > 0 {
+			n := min(len(part), jsonChunkSize)
+			for k := 0; k < utf8.UTFMax-1 && n < len(part) && !utf8.RuneStart(part[n]); k++ {
+				n--
+			}
+			buf = appendJSONEscaped(buf, part[:n])
+			part = part[n:]
+			if _, err := out.Write(buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+		}
+	}
+	buf = append(buf, '"')
+	_, err := out.Write(buf)
+	return err
+}
+
+// jsonOutput is the buffered output UIResourceStream writes to
+type jsonOutput interface {
+	io.Writer
+	io.StringWriter
+}
+
+const hexDigits = "0123456789abcdef"
+
+// jsonSafe reports which ASCII bytes encoding/json writes unescaped
+var jsonSafe = func() (safe [utf8.RuneSelf]bool) {
+	for b := 0x20; b < utf8.RuneSelf; b++ {
+		safe[b] = b != '"' && b != '\\' && b != '<' && b != '>' && b != '&'
+	}
+	return safe
+}()
+
+// appendJSONEscaped appends s with the escaping encoding/json applies by
+// default: quotes, backslashes and control characters, the HTML characters
+// <, > and &, U+2028 and U+2029, with invalid UTF-8 replaced by U+FFFD
+func appendJSONEscaped(dst []byte, s string) []byte {
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if jsonSafe[b] {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	return append(dst, s[start:]...)
+}
+
+// UIResourceStream is a UI resource whose content is encoded while it is
+// written. It produces the same JSON as marshaling the UIResource returned by
+// CreateUIResource, but escapes or base64-encodes the content straight into
+// the output instead of first building the text or blob string, which keeps
+// memory flat for multi-megabyte widgets.
+//
+// When transformers run (see WithTransformers), the content is joined into
+// one string for them and streamed from there.
+type UIResourceStream struct {
+	built *builtResource
+}
+
+// NewUIResourceStream validates the arguments and applies the options like
+// CreateUIResource, and returns a stream that encodes the content on write.
+//
+// Example:
+//
+//	stream, err := NewUIResourceStream("ui://dashboard", payload, EncodingBlob,
+//	    WithProtocol(ProtocolTypeMCPApps))
+//	if err != nil {
+//	    return err
+//	}
+//	_, err = stream.WriteTo(w)
+func NewUIResourceStream(uri string, content ResourceContentPayload, encoding Encoding, opts ...Option) (*UIResourceStream, error) {
+	built, err := buildResource(uri, content, encoding, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &UIResourceStream{built: built}, nil
+}
+
+// UIResource encodes the content and returns the equivalent UIResource. Each
+// call returns a new resource that shares no maps with earlier ones.
+func (s *UIResourceStream) UIResource() *UIResource {
+	return s.built.resource().Clone()
+}
+
+// MimeType returns the MIME type of the resource
+func (s *UIResourceStream) MimeType() string {
+	return s.built.mimeType
+}
+
+// WriteTo writes the resource as JSON to w through a buffer. It implements
+// io.WriterTo.
+func (s *UIResourceStream) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriterSize(cw, 32*1024)
+	if err := s.writeJSON(bw); err != nil {
+		return cw.n, err
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// MarshalJSON implements json.Marshaler, encoding the content into a buffer
+// sized for the whole resource
+func (s *UIResourceStream) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(s.estimatedSize())
+	if err := s.writeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// estimatedSize is the JSON size without escapes and metadata
+func (s *UIResourceStream) estimatedSize() int {
+	n := s.built.content.Len()
+	if s.built.encoding == EncodingBlob {
+		n = base64.StdEncoding.EncodedLen(n)
+	}
+	return n + len(s.built.uri) + len(s.built.mimeType) + 128
+}
+
+// writeJSON writes the fields in the order and with the omitempty rules of
+// UIResource and ResourceContent
+func (s *UIResourceStream) writeJSON(out jsonOutput) error {
+	b := s.built
+	var err error
+	write := func(raw string) {
+		if err == nil {
+			_, err = out.WriteString(raw)
+		}
+	}
+	writeValue := func(v interface{}) {
+		if err == nil {
+			var data []byte
+			if data, err = json.Marshal(v); err == nil {
+				_, err = out.Write(data)
+			}
+		}
+	}
+
+	write(`{"type":"resource","resource":{"uri":`)
+	writeValue(b.uri)
+	write(`,"mimeType":`)
+	writeValue(b.mimeType)
+	if b.content.Len() > 0 {
+		if b.encoding == EncodingBlob {
+			write(`,"blob":"`)
+			if err == nil {
+				err = b.content.writeBase64(out)
+			}
+			write(`"`)
+		} else {
+			write(`,"text":`)
+			if err == nil {
+				err = b.content.writeJSONString(out)
+			}
+		}
+	}
+	if len(b.meta) > 0 {
+		write(`,"_meta":`)
+		writeValue(b.meta)
+	}
+	write(`}`)
+	if len(b.annotations) > 0 {
+		write(`,"annotations":`)
+		writeValue(b.annotations)
+	}
+	if len(b.outerMeta) > 0 {
+		write(`,"_meta":`)
+		writeValue(b.outerMeta)
+	}
+	write(`}`)
+	return err
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,83 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// largeHTML is a multi-megabyte widget with multibyte text, as produced by
+// bundling a framework and its assets into one document
+var largeHTML = "<!DOCTYPE html><html><head><title>Grüße</title></head><body>" +
+	strings.Repeat(`<div class="row">Ünïcødé text & <b>markup</b> 🚀</div>`+"\n", 64*1024) +
+	"</body></html>"
+
+func benchmarkCreateUIResource(b *testing.B, encoding Encoding) {
+	content := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: largeHTML}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(largeHTML)))
+	for i := 0; i < b.N; i++ {
+		resource, err := CreateUIResource("ui://bench", content, encoding, WithProtocol(ProtocolTypeMCPApps))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := json.NewEncoder(io.Discard).Encode(resource); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCreateUIResource_LargeText(b *testing.B) {
+	benchmarkCreateUIResource(b, EncodingText)
+}
+
+func BenchmarkCreateUIResource_LargeBlob(b *testing.B) {
+	benchmarkCreateUIResource(b, EncodingBlob)
+}
+
+func benchmarkUIResourceStream(b *testing.B, encoding Encoding) {
+	content := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: largeHTML}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(largeHTML)))
+	for i := 0; i < b.N; i++ {
+		stream, err := NewUIResourceStream("ui://bench", content, encoding, WithProtocol(ProtocolTypeMCPApps))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := stream.WriteTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUIResourceStream_LargeText(b *testing.B) {
+	benchmarkUIResourceStream(b, EncodingText)
+}
+
+func BenchmarkUIResourceStream_LargeBlob(b *testing.B) {
+	benchmarkUIResourceStream(b, EncodingBlob)
+}
+
+func BenchmarkUIResourceStream_MarshalJSON(b *testing.B) {
+	content := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: largeHTML}
+	stream, err := NewUIResourceStream("ui://bench", content, EncodingBlob, WithProtocol(ProtocolTypeMCPApps))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(largeHTML)))
+	for i := 0; i < b.N; i++ {
+		if _, err := stream.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInjectScriptTag(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(largeHTML)))
+	for i := 0; i < b.N; i++ {
+		injectScriptTag(largeHTML, `<script src="https://cdn.example.com/mcpapps-v1.js"></script>`)
+	}
+}
@@ -0,0 +1,234 @@
+package mcpuiserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectScriptTag(t *testing.T) {
+	tag := `<script src="a.js"></script>`
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "head",
+			html: "<html><head><title>t</title></head><body></body></html>",
+			want: "<html><head>\n" + tag + "<title>t</title></head><body></body></html>",
+		},
+		{
+			name: "uppercase head",
+			html: "<HTML><HEAD></HEAD><BODY></BODY></HTML>",
+			want: "<HTML><HEAD>\n" + tag + "</HEAD><BODY></BODY></HTML>",
+		},
+		{
+			name: "multibyte text before head",
+			html: "<!-- Grüße 🚀 ÄÖÜ --><html><head></head><body>日本語</body></html>",
+			want: "<!-- Grüße 🚀 ÄÖÜ --><html><head>\n" + tag + "</head><body>日本語</body></html>",
+		},
+		{
+			name: "html without head",
+			html: "<!-- ÄÖÜ --><Html><body>x</body></html>",
+			want: "<!-- ÄÖÜ --><Html>\n<head>\n" + tag + "\n</head>\n<body>x</body></html>",
+		},
+		{
+			name: "fragment",
+			html: "<p>Grüße</p>",
+			want: "<html>\n<head>\n" + tag + "\n</head>\n<body>\n<p>Grüße</p>\n</body>\n</html>",
+		},
+		{
+			name: "closing head before opening head",
+			html: "</head><p><head-like></p>",
+			want: "<html>\n<head>\n" + tag + "\n</head>\n<body>\n</head><p><head-like></p>\n</body>\n</html>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := injectScriptTag(tt.html, tag)
+			assert.Equal(t, tt.want, parts.String())
+			assert.Equal(t, len(tt.want), parts.Len())
+		})
+	}
+}
+
+func TestIndexTagFold(t *testing.T) {
+	tests := []struct {
+		s    string
+		tag  string
+		want int
+	}{
+		{s: "<head>", tag: "<head>", want: 0},
+		{s: "ÄÖÜ<HeAd>", tag: "<head>", want: 6},
+		{s: "<he<head>", tag: "<head>", want: 3},
+		{s: "<hea", tag: "<head>", want: -1},
+		{s: "no tags", tag: "<head>", want: -1},
+		{s: "<headİ>", tag: "<head>", want: -1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, indexTagFold(tt.s, tt.tag), tt.s)
+	}
+}
+
+func TestContentParts_Encoding(t *testing.T) {
+	for _, parts := range []contentParts{
+		nil,
+		{"a"},
+		{"ab", "c", "", "defg"},
+		{strings.Repeat("x", 3*1024+1), "yz", strings.Repeat("Ü", 5000)},
+	} {
+		joined := strings.Join(parts, "")
+		assert.Equal(t, joined, parts.String())
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(joined)), parts.base64())
+	}
+}
+
+func TestContentParts_WriteJSONString(t *testing.T) {
+	inputs := []string{
+		"",
+		"plain",
+		`quote " backslash \ slash /`,
+		"<script>alert('x') && y</script>",
+		"controls \x00\x01\b\f\n\r\t\x1f\x7f",
+		"unicode Grüße 🚀    ",
+		"invalid \xff\xfe utf-8 \xe2\x82",
+		strings.Repeat("a", jsonChunkSize-1) + "🚀" + strings.Repeat("<Ü>", jsonChunkSize),
+		strings.Repeat("a", jsonChunkSize-1) + "\xe2\x82" + strings.Repeat("\x82", jsonChunkSize+5),
+	}
+
+	for _, input := range inputs {
+		want, err := json.Marshal(input)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, contentParts{input}.writeJSONString(&buf))
+		assert.Equal(t, string(want), buf.String(), "%q", input)
+	}
+}
+
+func TestUIResourceStream_MatchesCreateUIResource(t *testing.T) {
+	html := &RawHTMLPayload{
+		Type:       ContentTypeRawHTML,
+		HTMLString: "<html><head></head><body>Grüße \"quoted\" <b>&amp;</b>\n\t  \xff</body></html>",
+	}
+
+	tests := []struct {
+		name     string
+		content  ResourceContentPayload
+		encoding Encoding
+		opts     []Option
+	}{
+		{name: "raw HTML text", content: html, encoding: EncodingText},
+		{name: "raw HTML blob", content: html, encoding: EncodingBlob},
+		{
+			name:     "raw HTML with adapter",
+			content:  html,
+			encoding: EncodingText,
+			opts:     []Option{WithProtocol(ProtocolTypeMCPApps)},
+		},
+		{
+			name:     "raw HTML blob with adapter",
+			content:  html,
+			encoding: EncodingBlob,
+			opts:     []Option{WithProtocol(ProtocolTypeAppsSDK)},
+		},
+		{
+			name:     "external URL",
+			content:  &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com/?a=1&b=<2>"},
+			encoding: EncodingText,
+		},
+		{
+			name:     "remote DOM host page",
+			content:  &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('</script>')", Framework: FrameworkReact},
+			encoding: EncodingText,
+			opts:     []Option{WithProtocol(ProtocolTypeMCPApps)},
+		},
+		{
+			name:     "metadata and embedded props",
+			content:  html,
+			encoding: EncodingText,
+			opts: []Option{
+				WithUIMetadata(map[string]interface{}{UIMetadataKeyPreferredFrameSize: []string{"800px", "600px"}}),
+				WithMetadata(map[string]interface{}{"note": "<b>&</b>"}),
+				WithEmbeddedResourceProps(map[string]interface{}{
+					"annotations": map[string]interface{}{"priority": 0.5},
+					"_meta":       map[string]interface{}{"z": 1, "a": 2},
+				}),
+			},
+		},
+		{
+			name:     "transformers",
+			content:  html,
+			encoding: EncodingBlob,
+			opts:     []Option{WithProtocol(ProtocolTypeMCPApps), WithTransformers(InjectHead(`<meta name="build" content="1">`))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource("ui://test", tt.content, tt.encoding, tt.opts...)
+			assert.NoError(t, err)
+			want, err := json.Marshal(resource)
+			assert.NoError(t, err)
+
+			stream, err := NewUIResourceStream("ui://test", tt.content, tt.encoding, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, resource, stream.UIResource())
+			assert.Equal(t, resource.Resource.MimeType, stream.MimeType())
+
+			got, err := json.Marshal(stream)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+
+			var buf bytes.Buffer
+			n, err := stream.WriteTo(&buf)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(buf.Len()), n)
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+func TestUIResourceStream_Errors(t *testing.T) {
+	_, err := NewUIResourceStream("http://test", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText)
+	assert.ErrorIs(t, err, ErrInvalidURI)
+
+	stream, err := NewUIResourceStream("ui://test", &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText,
+		WithMetadata(map[string]interface{}{"bad": make(chan int)}))
+	assert.NoError(t, err)
+
+	_, err = stream.MarshalJSON()
+	var unsupported *json.UnsupportedTypeError
+	assert.ErrorAs(t, err, &unsupported)
+
+	_, err = stream.WriteTo(&bytes.Buffer{})
+	assert.ErrorAs(t, err, &unsupported)
+}
+
+func TestUIResourceStream_WriterError(t *testing.T) {
+	errWrite := errors.New("write failed")
+	stream, err := NewUIResourceStream("ui://test",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: strings.Repeat("<p>Hi</p>", 10000)}, EncodingBlob)
+	assert.NoError(t, err)
+
+	n, err := stream.WriteTo(failingWriter{err: errWrite})
+	assert.ErrorIs(t, err, errWrite)
+	assert.Zero(t, n)
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
@@ -0,0 +1,92 @@
+package mcpuiserver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Strict mode errors, reported by CreateUIResource when WithStrict is set
+var (
+	ErrIgnoredProtocol      = errors.New("protocol is not applied to unwrapped externalUrl content")
+	ErrIgnoredWrapping      = errors.New("external URL wrapping requires externalUrl content and the appssdk or mcpapps protocol")
+	ErrMetadataConflict     = errors.New("metadata overwrites a UI metadata key")
+	ErrInvalidResourceProps = errors.New("resource property has the wrong type")
+)
+
+// checkStrict reports every option combination that CreateUIResource would
+// otherwise ignore or resolve silently
+func checkStrict(opts *CreateUIResourceOptions) error {
+	var errs []error
+
+	isExternalURL := opts.Content.contentType() == ContentTypeExternalURL
+	if opts.Protocol != nil && isExternalURL && !opts.WrapExternalURL {
+		errs = append(errs, fmt.Errorf("%w: protocol %q has no effect on %s content without WithExternalURLWrapping",
+			ErrIgnoredProtocol, opts.Protocol.Type, opts.Content.contentType()))
+	}
+	if opts.WrapExternalURL && (!isExternalURL || opts.Protocol == nil || !requiresHTMLHost(opts.Protocol.Type)) {
+		protocol := ProtocolType("none")
+		if opts.Protocol != nil {
+			protocol = opts.Protocol.Type
+		}
+		errs = append(errs, fmt.Errorf("%w: got %s content with protocol %q",
+			ErrIgnoredWrapping, opts.Content.contentType(), protocol))
+	}
+
+	if err := opts.URIVersion.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	propsMeta, propsMetaOK := opts.ResourceProps["_meta"].(map[string]interface{})
+	if _, ok := opts.ResourceProps["_meta"]; ok && !propsMetaOK {
+		errs = append(errs, fmt.Errorf("%w: ResourceProps[\"_meta\"] must be map[string]interface{}, got %T",
+			ErrInvalidResourceProps, opts.ResourceProps["_meta"]))
+	}
+
+	for _, key := range sortedKeys(opts.UIMetadata) {
+		prefixed := UIMetadataPrefix + key
+		if _, ok := opts.Metadata[prefixed]; ok {
+			errs = append(errs, fmt.Errorf("%w: WithMetadata key %q overwrites WithUIMetadata key %q",
+				ErrMetadataConflict, prefixed, key))
+		}
+		if _, ok := propsMeta[prefixed]; ok {
+			errs = append(errs, fmt.Errorf("%w: ResourceProps _meta key %q overwrites WithUIMetadata key %q",
+				ErrMetadataConflict, prefixed, key))
+		}
+	}
+
+	for _, key := range []string{"annotations", "_meta"} {
+		value, ok := opts.EmbeddedResourceProps[key]
+		if !ok {
+			continue
+		}
+		switch value.(type) {
+		case Annotations, *Annotations:
+			if key == "annotations" {
+				continue
+			}
+		}
+		m, isMap := value.(map[string]interface{})
+		if !isMap {
+			errs = append(errs, fmt.Errorf("%w: EmbeddedResourceProps[%q] must be map[string]interface{}, got %T",
+				ErrInvalidResourceProps, key, value))
+			continue
+		}
+		if key == "annotations" {
+			if err := checkAnnotationMap(m); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkAnnotationMap reports EmbeddedResourceProps annotations that are not
+// valid MCP annotations, which CreateUIResource otherwise passes through
+func checkAnnotationMap(annotations map[string]interface{}) error {
+	typed, err := annotationsFromMap(annotations)
+	if err != nil {
+		return err
+	}
+	return typed.validate()
+}
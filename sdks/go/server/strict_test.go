@@ -0,0 +1,195 @@
+package mcpuiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStrict(t *testing.T) {
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	externalURL := &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com"}
+	remoteDOM := &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkReact}
+
+	tests := []struct {
+		name        string
+		content     ResourceContentPayload
+		opts        []Option
+		wantErr     error
+		errContains string
+	}{
+		{
+			name:    "protocol on raw HTML",
+			content: html,
+			opts:    []Option{WithProtocol(ProtocolTypeAppsSDK)},
+		},
+		{
+			name:        "protocol on external URL",
+			content:     externalURL,
+			opts:        []Option{WithProtocol(ProtocolTypeAppsSDK)},
+			wantErr:     ErrIgnoredProtocol,
+			errContains: `protocol "appssdk" has no effect on externalUrl content`,
+		},
+		{
+			name:    "protocol on wrapped external URL",
+			content: externalURL,
+			opts:    []Option{WithProtocol(ProtocolTypeAppsSDK), WithExternalURLWrapping()},
+		},
+		{
+			name:        "wrapping without protocol",
+			content:     externalURL,
+			opts:        []Option{WithExternalURLWrapping()},
+			wantErr:     ErrIgnoredWrapping,
+			errContains: `externalUrl content with protocol "none"`,
+		},
+		{
+			name:        "wrapping with generic protocol",
+			content:     externalURL,
+			opts:        []Option{WithExternalURLWrapping(), WithProtocol(ProtocolTypeGeneric)},
+			wantErr:     ErrIgnoredWrapping,
+			errContains: `protocol "generic"`,
+		},
+		{
+			name:        "wrapping raw HTML",
+			content:     html,
+			opts:        []Option{WithExternalURLWrapping(), WithProtocol(ProtocolTypeMCPApps)},
+			wantErr:     ErrIgnoredWrapping,
+			errContains: "rawHtml content",
+		},
+		{
+			name:    "protocol on remote DOM",
+			content: remoteDOM,
+			opts:    []Option{WithProtocolConfig(&ProtocolConfig{Type: ProtocolTypeMCPApps})},
+		},
+		{
+			name:    "distinct metadata keys",
+			content: html,
+			opts: []Option{
+				WithUIMetadata(map[string]interface{}{UIMetadataKeyPreferredFrameSize: []string{"1px", "1px"}}),
+				WithMetadata(map[string]interface{}{"custom": true}),
+			},
+		},
+		{
+			name:    "metadata overwrites UI metadata",
+			content: html,
+			opts: []Option{
+				WithUIMetadata(map[string]interface{}{UIMetadataKeyPreferredFrameSize: []string{"1px", "1px"}}),
+				WithMetadata(map[string]interface{}{UIMetadataPrefix + UIMetadataKeyPreferredFrameSize: "big"}),
+			},
+			wantErr:     ErrMetadataConflict,
+			errContains: `WithMetadata key "mcpui.dev/ui-preferred-frame-size"`,
+		},
+		{
+			name:    "resource props overwrite UI metadata",
+			content: html,
+			opts: []Option{
+				WithUIMetadata(map[string]interface{}{UIMetadataKeyInitialRenderData: map[string]interface{}{}}),
+				WithResourceProps(map[string]interface{}{
+					"_meta": map[string]interface{}{UIMetadataPrefix + UIMetadataKeyInitialRenderData: nil},
+				}),
+			},
+			wantErr:     ErrMetadataConflict,
+			errContains: "ResourceProps _meta key",
+		},
+		{
+			name:        "resource props meta with wrong type",
+			content:     html,
+			opts:        []Option{WithResourceProps(map[string]interface{}{"_meta": map[string]string{"a": "b"}})},
+			wantErr:     ErrInvalidResourceProps,
+			errContains: "map[string]string",
+		},
+		{
+			name:    "embedded resource props",
+			content: html,
+			opts: []Option{WithEmbeddedResourceProps(map[string]interface{}{
+				"annotations": map[string]interface{}{"priority": 1},
+				"_meta":       map[string]interface{}{"a": "b"},
+			})},
+		},
+		{
+			name:    "embedded typed annotations",
+			content: html,
+			opts: []Option{WithEmbeddedResourceProps(map[string]interface{}{
+				"annotations": Annotations{Audience: []Role{RoleUser}},
+			})},
+		},
+		{
+			name:        "embedded annotations with wrong type",
+			content:     html,
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": []string{"user"}})},
+			wantErr:     ErrInvalidResourceProps,
+			errContains: `EmbeddedResourceProps["annotations"]`,
+		},
+		{
+			name:        "embedded annotations of another map type",
+			content:     html,
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": map[string]string{"priority": "1"}})},
+			wantErr:     ErrInvalidResourceProps,
+			errContains: "got map[string]string",
+		},
+		{
+			name:        "embedded annotations priority above 1",
+			content:     html,
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": map[string]interface{}{"priority": 5}})},
+			wantErr:     ErrInvalidAnnotations,
+			errContains: "priority 5",
+		},
+		{
+			name:        "embedded annotations priority not a number",
+			content:     html,
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": map[string]interface{}{"priority": "high"}})},
+			wantErr:     ErrInvalidAnnotations,
+			errContains: "numeric priority",
+		},
+		{
+			name:        "embedded annotations unknown role",
+			content:     html,
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"annotations": map[string]interface{}{"audience": []string{"model"}}})},
+			wantErr:     ErrInvalidAnnotations,
+			errContains: `role "model"`,
+		},
+		{
+			name:        "embedded meta with wrong type",
+			content:     html,
+			opts:        []Option{WithEmbeddedResourceProps(map[string]interface{}{"_meta": "x"})},
+			wantErr:     ErrInvalidResourceProps,
+			errContains: `EmbeddedResourceProps["_meta"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lenient, err := CreateUIResource("ui://test", tt.content, EncodingText, tt.opts...)
+			assert.NoError(t, err, "without WithStrict the combination is accepted")
+
+			strict, err := CreateUIResource("ui://test", tt.content, EncodingText, append(tt.opts, WithStrict())...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, lenient, strict)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Nil(t, strict)
+		})
+	}
+}
+
+func TestWithStrict_ReportsAllProblems(t *testing.T) {
+	_, err := CreateUIResource(
+		"ui://test",
+		&ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com"},
+		EncodingText,
+		WithStrict(),
+		WithProtocol(ProtocolTypeMCPApps),
+		WithUIMetadata(map[string]interface{}{"a": 1}),
+		WithMetadata(map[string]interface{}{UIMetadataPrefix + "a": 2}),
+		WithEmbeddedResourceProps(map[string]interface{}{"annotations": 3}),
+		WithVersionedURI("bogus"),
+	)
+
+	assert.ErrorIs(t, err, ErrIgnoredProtocol)
+	assert.ErrorIs(t, err, ErrMetadataConflict)
+	assert.ErrorIs(t, err, ErrInvalidResourceProps)
+	assert.ErrorIs(t, err, ErrInvalidURIVersionStyle)
+}
@@ -0,0 +1,172 @@
+package mcpuiserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrTransformerFailed is wrapped by every TransformerError
+var ErrTransformerFailed = errors.New("resource transformer failed")
+
+// TransformerError reports which transformer in the chain failed. Index
+// counts the default chain first, then the transformers from
+// WithTransformers.
+type TransformerError struct {
+	Index int
+	Err   error
+}
+
+func (e *TransformerError) Error() string {
+	return fmt.Sprintf("transformer %d: %v", e.Index, e.Err)
+}
+
+func (e *TransformerError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TransformerError) Is(target error) bool {
+	return target == ErrTransformerFailed
+}
+
+// ResourceDraft is a resource after content processing and before encoding.
+// Transformers may change Content, MimeType and Meta; URI, ContentType and
+// Protocol describe the resource being built and are ignored if changed.
+type ResourceDraft struct {
+	URI         string
+	ContentType ContentType
+	Protocol    ProtocolType // Empty when no protocol is configured
+	Content     string       // Decoded content, with any protocol adapter applied
+	MimeType    string
+	Meta        map[string]interface{} // Nil when the resource has no metadata
+}
+
+// IsHTML reports whether the draft content is an HTML document, including
+// the adapter MIME types
+func (d *ResourceDraft) IsHTML() bool {
+	return strings.HasPrefix(d.MimeType, MimeTypeHTML)
+}
+
+// SetMeta sets a metadata key, creating the map if needed
+func (d *ResourceDraft) SetMeta(key string, value interface{}) {
+	if d.Meta == nil {
+		d.Meta = make(map[string]interface{})
+	}
+	d.Meta[key] = value
+}
+
+// ResourceTransformer changes a resource before it is encoded, so
+// cross-cutting concerns such as analytics beacons, stylesheets, CSP meta
+// tags or build metadata live in one place instead of in every widget.
+type ResourceTransformer interface {
+	Transform(draft *ResourceDraft) error
+}
+
+// ResourceTransformerFunc adapts an ordinary function to the
+// ResourceTransformer interface.
+type ResourceTransformerFunc func(draft *ResourceDraft) error
+
+// Transform calls f(draft)
+func (f ResourceTransformerFunc) Transform(draft *ResourceDraft) error {
+	return f(draft)
+}
+
+var (
+	defaultTransformersMu  sync.RWMutex
+	defaultTransformers    []ResourceTransformer
+	defaultTransformersGen uint64 // Incremented by SetDefaultTransformers
+)
+
+// SetDefaultTransformers replaces the package-level transformer chain that
+// CreateUIResource runs for every resource, before any transformers from
+// WithTransformers. Call it with no arguments to clear the chain. It is safe
+// for concurrent use, but is usually called once during startup.
+//
+// Example:
+//
+//	mcpuiserver.SetDefaultTransformers(
+//	    mcpuiserver.InjectHead(`<link rel="stylesheet" href="https://cdn.example.com/corp.css">`),
+//	    mcpuiserver.AddMetadata(map[string]interface{}{"build": version}),
+//	)
+func SetDefaultTransformers(transformers ...ResourceTransformer) {
+	defaultTransformersMu.Lock()
+	defer defaultTransformersMu.Unlock()
+	defaultTransformers = append([]ResourceTransformer(nil), transformers...)
+	defaultTransformersGen++
+}
+
+// defaultTransformersGeneration identifies the current default chain, so
+// ResourceCache entries built with an earlier chain are not reused
+func defaultTransformersGeneration() uint64 {
+	defaultTransformersMu.RLock()
+	defer defaultTransformersMu.RUnlock()
+	return defaultTransformersGen
+}
+
+// DefaultTransformers returns a copy of the package-level transformer chain
+func DefaultTransformers() []ResourceTransformer {
+	defaultTransformersMu.RLock()
+	defer defaultTransformersMu.RUnlock()
+	return append([]ResourceTransformer(nil), defaultTransformers...)
+}
+
+// WithTransformers adds transformers that run, in order, after the default
+// chain. Repeated calls append.
+// Example: WithTransformers(InjectHead(`<meta name="build" content="1.2.3">`))
+func WithTransformers(transformers ...ResourceTransformer) Option {
+	return func(o *CreateUIResourceOptions) {
+		o.Transformers = append(o.Transformers, transformers...)
+	}
+}
+
+// WithoutDefaultTransformers skips the package-level transformer chain for
+// this resource. Transformers from WithTransformers still run.
+func WithoutDefaultTransformers() Option {
+	return func(o *CreateUIResourceOptions) {
+		o.SkipDefaultTransformers = true
+	}
+}
+
+// transformerChain returns the default chain, unless skipped, followed by
+// the resource's own transformers
+func transformerChain(options *CreateUIResourceOptions) []ResourceTransformer {
+	var chain []ResourceTransformer
+	if !options.SkipDefaultTransformers {
+		chain = DefaultTransformers()
+	}
+	return append(chain, options.Transformers...)
+}
+
+// applyTransformers runs chain on draft, stopping at the first error
+func applyTransformers(draft *ResourceDraft, chain []ResourceTransformer) error {
+	for i, transformer := range chain {
+		if err := transformer.Transform(draft); err != nil {
+			return &TransformerError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// InjectHead returns a transformer that adds an HTML fragment, such as a
+// stylesheet link, CSP meta tag or analytics script, to the <head> of HTML
+// resources. Other content types are left unchanged.
+func InjectHead(fragment string) ResourceTransformer {
+	return ResourceTransformerFunc(func(draft *ResourceDraft) error {
+		if draft.IsHTML() {
+			draft.Content = injectScriptTag(draft.Content, fragment).String()
+		}
+		return nil
+	})
+}
+
+// AddMetadata returns a transformer that sets metadata keys on every
+// resource, overwriting existing values
+func AddMetadata(meta map[string]interface{}) ResourceTransformer {
+	return ResourceTransformerFunc(func(draft *ResourceDraft) error {
+		for _, key := range sortedKeys(meta) {
+			draft.SetMeta(key, cloneValue(meta[key]))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,178 @@
+package mcpuiserver
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTransformers(t *testing.T) {
+	csp := `<meta http-equiv="Content-Security-Policy" content="default-src 'self'">`
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<html><head></head><body>Hi</body></html>"}
+	remoteDOM := &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkReact}
+
+	tests := []struct {
+		name         string
+		content      ResourceContentPayload
+		opts         []Option
+		wantContains []string
+		wantContent  string
+		wantMimeType string
+		wantMeta     map[string]interface{}
+	}{
+		{
+			name:         "inject into HTML head",
+			content:      html,
+			opts:         []Option{WithTransformers(InjectHead(csp))},
+			wantContains: []string{"<head>\n" + csp + "</head>"},
+			wantMimeType: MimeTypeHTML,
+		},
+		{
+			name:         "inject into adapter page",
+			content:      html,
+			opts:         []Option{WithProtocol(ProtocolTypeMCPApps), WithTransformers(InjectHead(csp))},
+			wantContains: []string{csp, "mcpapps-"},
+			wantMimeType: MimeTypeMCPAppsAdapter,
+		},
+		{
+			name:         "inject skips remote DOM scripts",
+			content:      remoteDOM,
+			opts:         []Option{WithTransformers(InjectHead(csp))},
+			wantContent:  "root.append('hi')",
+			wantMimeType: MimeTypeRemoteDomReact,
+		},
+		{
+			name:    "add metadata",
+			content: html,
+			opts: []Option{
+				WithMetadata(map[string]interface{}{"build": "old", "owner": "team"}),
+				WithTransformers(AddMetadata(map[string]interface{}{"build": "1.2.3"})),
+			},
+			wantMimeType: MimeTypeHTML,
+			wantMeta:     map[string]interface{}{"build": "1.2.3", "owner": "team"},
+		},
+		{
+			name:    "chain runs in order and sees protocol",
+			content: remoteDOM,
+			opts: []Option{
+				WithProtocol(ProtocolTypeAppsSDK),
+				WithTransformers(ResourceTransformerFunc(func(d *ResourceDraft) error {
+					d.SetMeta("seen", string(d.ContentType)+"/"+string(d.Protocol))
+					return nil
+				})),
+				WithTransformers(ResourceTransformerFunc(func(d *ResourceDraft) error {
+					d.SetMeta("seen", d.Meta["seen"].(string)+"/second")
+					return nil
+				})),
+			},
+			wantMimeType: MimeTypeAppsSdkAdapter,
+			wantMeta:     map[string]interface{}{"seen": "remoteDom/appssdk/second"},
+		},
+		{
+			name:    "change MIME type and clear metadata",
+			content: html,
+			opts: []Option{
+				WithMetadata(map[string]interface{}{"a": 1}),
+				WithTransformers(ResourceTransformerFunc(func(d *ResourceDraft) error {
+					d.MimeType = MimeTypeMCPAppsAdapter
+					d.Meta = nil
+					return nil
+				})),
+			},
+			wantMimeType: MimeTypeMCPAppsAdapter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource("ui://test", tt.content, EncodingBlob, tt.opts...)
+			assert.NoError(t, err)
+
+			decoded, err := base64.StdEncoding.DecodeString(resource.Resource.Blob)
+			assert.NoError(t, err)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, string(decoded), want)
+			}
+			if tt.wantContent != "" {
+				assert.Equal(t, tt.wantContent, string(decoded))
+			}
+			assert.Equal(t, tt.wantMimeType, resource.Resource.MimeType)
+			assert.Equal(t, tt.wantMeta, resource.Resource.Meta)
+		})
+	}
+}
+
+func TestWithTransformers_Error(t *testing.T) {
+	errBoom := errors.New("boom")
+	called := false
+
+	resource, err := CreateUIResource("ui://test",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText,
+		WithTransformers(
+			AddMetadata(map[string]interface{}{"a": 1}),
+			ResourceTransformerFunc(func(*ResourceDraft) error { return errBoom }),
+			ResourceTransformerFunc(func(*ResourceDraft) error { called = true; return nil }),
+		))
+
+	assert.Nil(t, resource)
+	assert.ErrorIs(t, err, ErrTransformerFailed)
+	assert.ErrorIs(t, err, errBoom)
+	var transformerErr *TransformerError
+	if assert.ErrorAs(t, err, &transformerErr) {
+		assert.Equal(t, 1, transformerErr.Index)
+	}
+	assert.False(t, called, "the chain stops at the first error")
+}
+
+func TestSetDefaultTransformers(t *testing.T) {
+	defer SetDefaultTransformers()
+
+	var order []string
+	record := func(name string) ResourceTransformer {
+		return ResourceTransformerFunc(func(*ResourceDraft) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	SetDefaultTransformers(record("default"), AddMetadata(map[string]interface{}{"build": "1.2.3"}))
+	assert.Len(t, DefaultTransformers(), 2)
+
+	html := &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}
+	resource, err := CreateUIResource("ui://test", html, EncodingText, WithTransformers(record("option")))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "option"}, order)
+	assert.Equal(t, "1.2.3", resource.Resource.Meta["build"])
+
+	order = nil
+	resource, err = CreateUIResource("ui://test", html, EncodingText, WithoutDefaultTransformers(), WithTransformers(record("option")))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"option"}, order)
+	assert.Nil(t, resource.Resource.Meta)
+
+	SetDefaultTransformers()
+	assert.Empty(t, DefaultTransformers())
+}
+
+func TestDefaultTransformers_ReturnsCopy(t *testing.T) {
+	defer SetDefaultTransformers()
+
+	SetDefaultTransformers(AddMetadata(nil))
+	chain := DefaultTransformers()
+	chain[0] = nil
+
+	assert.NotNil(t, DefaultTransformers()[0])
+}
+
+func TestResourceDraft_IsHTML(t *testing.T) {
+	for mimeType, want := range map[string]bool{
+		MimeTypeHTML:           true,
+		MimeTypeAppsSdkAdapter: true,
+		MimeTypeMCPAppsAdapter: true,
+		MimeTypeURIList:        false,
+		MimeTypeRemoteDomWC:    false,
+	} {
+		assert.Equal(t, want, (&ResourceDraft{MimeType: mimeType}).IsHTML(), mimeType)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/MCP-UI-Org/mcp-ui/sdks/go/server/internal/pixels"
 )
 
 // URI scheme and metadata constants
@@ -32,6 +34,7 @@ const (
 const (
 	UIMetadataKeyPreferredFrameSize = "preferred-frame-size"
 	UIMetadataKeyInitialRenderData  = "initial-render-data"
+	UIMetadataKeyContentHash        = "content-hash"
 )
 
 // Protocol version and metadata keys for MCP Apps standard
@@ -102,6 +105,33 @@ const (
 	DisplayModeFullscreen DisplayMode = "fullscreen"
 )
 
+// LogLevel controls how verbose the adapter runtimes are in the browser console.
+// The empty value keeps the runtime default, which logs everything.
+type LogLevel string
+
+const (
+	LogLevelDebug  LogLevel = "debug"
+	LogLevelInfo   LogLevel = "info"
+	LogLevelWarn   LogLevel = "warn"
+	LogLevelError  LogLevel = "error"
+	LogLevelSilent LogLevel = "silent"
+)
+
+// IsValid reports whether l is one of the defined log levels
+func (l LogLevel) IsValid() bool {
+	switch l {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelSilent:
+		return true
+	}
+	return false
+}
+
+// AppInfo identifies a widget to MCP Apps hosts during ui/initialize
+type AppInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
 // RenderData contains initialization data passed to widgets
 type RenderData struct {
 	ToolInput   map[string]interface{} `json:"toolInput,omitempty"`
@@ -113,22 +143,45 @@ type RenderData struct {
 	MaxHeight   int                    `json:"maxHeight,omitempty"`
 }
 
+// UnmarshalJSON decodes render data, rounding a fractional maxHeight to the
+// nearest pixel. Browsers report CSS pixel lengths such as 412.5 on zoomed or
+// scaled pages. Unknown fields are rejected.
+func (r *RenderData) UnmarshalJSON(data []byte) error {
+	type plain RenderData
+	aux := struct {
+		*plain
+		MaxHeight float64 `json:"maxHeight,omitempty"`
+	}{plain: (*plain)(r), MaxHeight: float64(r.MaxHeight)}
+	if err := decodeStrict(data, &aux); err != nil {
+		return err
+	}
+	maxHeight, err := pixels.Round(aux.MaxHeight)
+	if err != nil {
+		return fmt.Errorf("maxHeight: %w", err)
+	}
+	r.MaxHeight = maxHeight
+	return nil
+}
+
 // ProtocolMessageType represents MCP-UI protocol message types
 type ProtocolMessageType string
 
 const (
-	MessageTypeToolCall                ProtocolMessageType = "tool"
-	MessageTypePrompt                  ProtocolMessageType = "prompt"
-	MessageTypeLink                    ProtocolMessageType = "link"
-	MessageTypeIntent                  ProtocolMessageType = "intent"
-	MessageTypeNotify                  ProtocolMessageType = "notify"
-	MessageTypeLifecycleReady          ProtocolMessageType = "ui-lifecycle-iframe-ready"
-	MessageTypeSizeChange              ProtocolMessageType = "ui-size-change"
-	MessageTypeRequestData             ProtocolMessageType = "ui-request-data"
-	MessageTypeRequestRenderData       ProtocolMessageType = "ui-request-render-data"
-	MessageTypeLifecycleRenderData     ProtocolMessageType = "ui-lifecycle-iframe-render-data"
-	MessageTypeMessageReceived         ProtocolMessageType = "ui-message-received"
-	MessageTypeMessageResponse         ProtocolMessageType = "ui-message-response"
+	MessageTypeToolCall            ProtocolMessageType = "tool"
+	MessageTypePrompt              ProtocolMessageType = "prompt"
+	MessageTypeLink                ProtocolMessageType = "link"
+	MessageTypeIntent              ProtocolMessageType = "intent"
+	MessageTypeNotify              ProtocolMessageType = "notify"
+	MessageTypeLifecycleReady      ProtocolMessageType = "ui-lifecycle-iframe-ready"
+	MessageTypeSizeChange          ProtocolMessageType = "ui-size-change"
+	MessageTypeRequestData         ProtocolMessageType = "ui-request-data"
+	MessageTypeRequestRenderData   ProtocolMessageType = "ui-request-render-data"
+	MessageTypeLifecycleRenderData ProtocolMessageType = "ui-lifecycle-iframe-render-data"
+	MessageTypeMessageReceived     ProtocolMessageType = "ui-message-received"
+	MessageTypeMessageResponse     ProtocolMessageType = "ui-message-response"
+	MessageTypeToolCancelled       ProtocolMessageType = "ui-lifecycle-tool-cancelled"
+	MessageTypeTeardown            ProtocolMessageType = "ui-lifecycle-teardown"
+	MessageTypeToolInputPartial    ProtocolMessageType = "ui-lifecycle-tool-input-partial"
 )
 
 // ResourceContentPayload is the interface for content payloads
@@ -211,14 +264,21 @@ type UIResource struct {
 
 // CreateUIResourceOptions contains all options for creating a UI resource
 type CreateUIResourceOptions struct {
-	URI                   string
-	Content               ResourceContentPayload
-	Encoding              Encoding
-	UIMetadata            map[string]interface{}
-	Metadata              map[string]interface{}
-	ResourceProps         map[string]interface{}
-	EmbeddedResourceProps map[string]interface{}
-	Protocol              *ProtocolConfig // Server-side protocol selection with external adapter scripts
+	URI                     string
+	Content                 ResourceContentPayload
+	Encoding                Encoding
+	UIMetadata              map[string]interface{}
+	Metadata                map[string]interface{}
+	ResourceProps           map[string]interface{}
+	EmbeddedResourceProps   map[string]interface{}
+	Protocol                *ProtocolConfig       // Server-side protocol selection with external adapter scripts
+	Strict                  bool                  // Reject option combinations that would otherwise be ignored
+	WrapExternalURL         bool                  // Wrap external URLs in an HTML page for HTML-only protocols
+	Transformers            []ResourceTransformer // Run after the default chain, before encoding
+	SkipDefaultTransformers bool                  // Skip the package-level transformer chain
+	ContentHash             bool                  // Record the content hash in _meta
+	URIVersion              URIVersionStyle       // Add the content hash to the URI
+	Annotations             *Annotations          // Typed MCP annotations of the embedded resource
 }
 
 // ProtocolType defines the UI protocol to use for a session
@@ -278,6 +338,30 @@ func WithEmbeddedResourceProps(props map[string]interface{}) Option {
 	}
 }
 
+// WithStrict makes CreateUIResource reject option combinations it would
+// otherwise ignore or resolve silently: a protocol or wrapping that has no
+// effect on the content, metadata overwriting prefixed UI metadata keys, and
+// embedded resource or resource props whose values have the wrong type. Use
+// it in tests to catch misconfigured resources early.
+func WithStrict() Option {
+	return func(o *CreateUIResourceOptions) {
+		o.Strict = true
+	}
+}
+
+// WithExternalURLWrapping converts ExternalURLPayload resources for hosts
+// that do not render text/uri-list. When the protocol is appssdk or mcpapps,
+// the URL is wrapped in an HTML page with a full-size iframe, the protocol
+// adapter and a relay that forwards MCP-UI messages between the inner page and
+// the host. The adapter applies the rules from the ProtocolConfig as it does
+// for raw HTML. The URL must be absolute http or https.
+// Example: WithExternalURLWrapping()
+func WithExternalURLWrapping() Option {
+	return func(o *CreateUIResourceOptions) {
+		o.WrapExternalURL = true
+	}
+}
+
 // WithProtocol sets the protocol type for this resource.
 // This enables server-side protocol selection using external adapter scripts,
 // eliminating the need to inject large adapter runtimes into HTML content.
@@ -261,3 +261,11 @@ func TestContentTypeMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestLogLevel_IsValid(t *testing.T) {
+	for _, level := range []LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelSilent} {
+		assert.True(t, level.IsValid(), "%q should be valid", level)
+	}
+	assert.False(t, LogLevel("").IsValid())
+	assert.False(t, LogLevel("verbose").IsValid())
+}
@@ -1,15 +1,16 @@
 package mcpuiserver
 
 import (
-	"encoding/base64"
+	"sort"
 )
 
-// encodeBase64 encodes a string to base64
+// encodeBase64 encodes a string to base64 without copying it to a byte slice
 func encodeBase64(s string) string {
-	return base64.StdEncoding.EncodeToString([]byte(s))
+	return contentParts{s}.base64()
 }
 
-// buildMetadata builds the metadata map from UI metadata and custom metadata
+// buildMetadata builds the metadata map from UI metadata and custom metadata.
+// Values are deep copied so the resource never aliases the caller's maps.
 func buildMetadata(opts *CreateUIResourceOptions) map[string]interface{} {
 	if opts.UIMetadata == nil && opts.Metadata == nil && opts.ResourceProps == nil {
 		return nil
@@ -19,19 +20,19 @@ func buildMetadata(opts *CreateUIResourceOptions) map[string]interface{} {
 
 	// Add prefixed UI metadata
 	for k, v := range opts.UIMetadata {
-		meta[UIMetadataPrefix+k] = v
+		meta[UIMetadataPrefix+k] = cloneValue(v)
 	}
 
 	// Add custom metadata (can override)
 	for k, v := range opts.Metadata {
-		meta[k] = v
+		meta[k] = cloneValue(v)
 	}
 
 	// Merge with resource props metadata
 	if opts.ResourceProps != nil {
 		if propsMeta, ok := opts.ResourceProps["_meta"].(map[string]interface{}); ok {
 			for k, v := range propsMeta {
-				meta[k] = v
+				meta[k] = cloneValue(v)
 			}
 		}
 	}
@@ -42,3 +43,13 @@ func buildMetadata(opts *CreateUIResourceOptions) map[string]interface{} {
 
 	return meta
 }
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
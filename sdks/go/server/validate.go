@@ -0,0 +1,232 @@
+package mcpuiserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Resource validation errors reported by ValidateUIResource
+var (
+	ErrNilResource          = errors.New("resource cannot be nil")
+	ErrInvalidResourceType  = errors.New("type must be 'resource'")
+	ErrUnknownMimeType      = errors.New("unsupported MIME type")
+	ErrAmbiguousContent     = errors.New("exactly one of text or blob must be set")
+	ErrInvalidBase64        = errors.New("blob must be valid base64")
+	ErrInvalidUTF8          = errors.New("content must be valid UTF-8")
+	ErrInvalidIframeURL     = errors.New("iframe URL must be an absolute http or https URL")
+	ErrInvalidMetadataValue = errors.New("metadata value has the wrong shape")
+	ErrResourceURIMismatch  = errors.New("resource URI metadata must match resource.uri")
+)
+
+// FieldError reports a problem with one field of a UIResource. Field is the
+// JSON path of the field, for example "resource.blob" or
+// `resource._meta["mcpui.dev/ui-preferred-frame-size"]`.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateUIResource checks a UIResource that was not necessarily built by
+// CreateUIResource, such as one decoded from an upstream server or changed
+// after creation. It checks:
+//   - type is "resource" and the URI uses the ui:// scheme
+//   - the MIME type is one the SDK produces, compared as a media type so case
+//     and spacing around parameters do not matter
+//   - exactly one of text or blob is set, blobs are valid base64 and the
+//     content is valid UTF-8
+//   - the content suits the MIME type: HTML and scripts are non-empty and URI
+//     lists contain an http or https URL
+//   - known mcpui.dev/ui- metadata keys have the expected shape
+//   - any ResourceURIMetaKey entry matches the resource URI
+//   - a recorded content hash matches the content
+//   - annotations have valid audience roles, a priority between 0 and 1 and
+//     an RFC 3339 lastModified
+//
+// All problems are returned together as a joined error of *FieldError
+// values, each wrapping one of the sentinel errors above,
+// ErrContentHashMismatch, ErrInvalidAnnotations, ErrInvalidURI or the ErrEmpty* content errors.
+//
+// Example:
+//
+//	if err := mcpuiserver.ValidateUIResource(resource); err != nil {
+//	    var fieldErr *mcpuiserver.FieldError
+//	    if errors.As(err, &fieldErr) {
+//	        log.Printf("first problem at %s", fieldErr.Field)
+//	    }
+//	    return err
+//	}
+func ValidateUIResource(resource *UIResource) error {
+	if resource == nil {
+		return ErrNilResource
+	}
+
+	var errs []error
+	fail := func(field string, err error) {
+		errs = append(errs, &FieldError{Field: field, Err: err})
+	}
+
+	if resource.Type != "resource" {
+		fail("type", ErrInvalidResourceType)
+	}
+
+	content := resource.Resource
+	if err := validateURI(content.URI); err != nil {
+		fail("resource.uri", err)
+	}
+
+	mimeType, mimeOK := knownMimeType(content.MimeType)
+	if !mimeOK {
+		fail("resource.mimeType", fmt.Errorf("%w: %q", ErrUnknownMimeType, content.MimeType))
+	}
+
+	text, field, ok := decodedContent(content, fail)
+	if ok {
+		if !utf8.ValidString(text) {
+			fail(field, ErrInvalidUTF8)
+		} else if mimeOK {
+			if err := validateContentForMimeType(mimeType, text); err != nil {
+				fail(field, err)
+			}
+		}
+		if recorded, ok := content.Meta[ContentHashMetaKey].(string); ok && isHex(recorded) {
+			if actual := hashContent(contentParts{text}); recorded != actual {
+				fail(fmt.Sprintf("resource._meta[%q]", ContentHashMetaKey),
+					fmt.Errorf("%w: recorded %s, content hashes to %s", ErrContentHashMismatch, recorded, actual))
+			}
+		}
+	}
+
+	validateMetadata("resource._meta", content.Meta, content.URI, fail)
+	validateMetadata("_meta", resource.Meta, content.URI, fail)
+	validateAnnotations(resource.Annotations, fail)
+
+	return errors.Join(errs...)
+}
+
+// knownMimeTypes are the MIME types a UI resource may declare
+var knownMimeTypes = []string{
+	MimeTypeHTML, MimeTypeAppsSdkAdapter, MimeTypeMCPAppsAdapter,
+	MimeTypeURIList, MimeTypeRemoteDomReact, MimeTypeRemoteDomWC,
+}
+
+// knownMimeType returns the SDK constant for a MIME type, comparing media
+// types and parameters so "text/html; profile=mcp-app" or "Text/HTML" match
+func knownMimeType(mimeType string) (string, bool) {
+	for _, known := range knownMimeTypes {
+		if sameMimeType(mimeType, known) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// decodedContent returns the resource content as a string along with the
+// field it came from, reporting encoding problems through fail
+func decodedContent(content ResourceContent, fail func(string, error)) (string, string, bool) {
+	switch {
+	case content.Text != "" && content.Blob != "":
+		fail("resource", ErrAmbiguousContent)
+		return "", "", false
+	case content.Blob != "":
+		decoded, err := base64.StdEncoding.DecodeString(content.Blob)
+		if err != nil {
+			fail("resource.blob", fmt.Errorf("%w: %v", ErrInvalidBase64, err))
+			return "", "", false
+		}
+		return string(decoded), "resource.blob", true
+	case content.Text != "":
+		return content.Text, "resource.text", true
+	default:
+		fail("resource", ErrAmbiguousContent)
+		return "", "", false
+	}
+}
+
+func validateContentForMimeType(mimeType, content string) error {
+	switch mimeType {
+	case MimeTypeURIList:
+		return validateURIList(content)
+	case MimeTypeRemoteDomReact, MimeTypeRemoteDomWC:
+		if strings.TrimSpace(content) == "" {
+			return ErrEmptyScript
+		}
+	default:
+		if strings.TrimSpace(content) == "" {
+			return ErrEmptyHTMLString
+		}
+	}
+	return nil
+}
+
+// validateURIList checks a text/uri-list body (RFC 2483): the first URI that
+// is not a comment is the one rendered and must be absolute http or https
+func validateURIList(content string) error {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, err := url.Parse(line)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidIframeURL, line)
+		}
+		return nil
+	}
+	return ErrEmptyIframeURL
+}
+
+// validateMetadata checks the known keys of a _meta map
+func validateMetadata(path string, meta map[string]interface{}, uri string, fail func(string, error)) {
+	for _, key := range sortedKeys(meta) {
+		field := fmt.Sprintf("%s[%q]", path, key)
+		value := meta[key]
+
+		switch key {
+		case ResourceURIMetaKey:
+			s, ok := value.(string)
+			if !ok {
+				fail(field, fmt.Errorf("%w: want a string, got %T", ErrInvalidMetadataValue, value))
+			} else if s != uri {
+				fail(field, fmt.Errorf("%w: %q != %q", ErrResourceURIMismatch, s, uri))
+			}
+		case UIMetadataPrefix + UIMetadataKeyPreferredFrameSize:
+			var size []string
+			if !decodeMetadata(value, &size) || len(size) != 2 || size[0] == "" || size[1] == "" {
+				fail(field, fmt.Errorf("%w: want [width, height] strings, got %v", ErrInvalidMetadataValue, value))
+			}
+		case ContentHashMetaKey:
+			if s, ok := value.(string); !ok || len(s) != sha256.Size*2 || !isHex(s) {
+				fail(field, fmt.Errorf("%w: want a hex SHA-256 hash, got %v", ErrInvalidMetadataValue, value))
+			}
+		case UIMetadataPrefix + UIMetadataKeyInitialRenderData:
+			var renderData map[string]interface{}
+			if !decodeMetadata(value, &renderData) || renderData == nil {
+				fail(field, fmt.Errorf("%w: want an object, got %T", ErrInvalidMetadataValue, value))
+			}
+		}
+	}
+}
+
+// decodeMetadata converts a metadata value, which may be a Go value or one
+// decoded from JSON, into dst through its JSON form
+func decodeMetadata(value, dst interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dst) == nil
+}
@@ -0,0 +1,304 @@
+package mcpuiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUIResource_CreatedResources(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  ResourceContentPayload
+		encoding Encoding
+		opts     []Option
+	}{
+		{
+			name:     "raw HTML text",
+			content:  &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"},
+			encoding: EncodingText,
+		},
+		{
+			name:     "raw HTML blob with metadata",
+			content:  &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Grüße</p>"},
+			encoding: EncodingBlob,
+			opts: []Option{
+				WithUIMetadata(map[string]interface{}{
+					UIMetadataKeyPreferredFrameSize: []string{"800px", "600px"},
+					UIMetadataKeyInitialRenderData:  RenderData{Theme: "dark"},
+				}),
+				WithMetadata(map[string]interface{}{ResourceURIMetaKey: "ui://test/widget"}),
+			},
+		},
+		{
+			name:     "external URL",
+			content:  &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com/app"},
+			encoding: EncodingText,
+		},
+		{
+			name:     "remote DOM",
+			content:  &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkWebComponents},
+			encoding: EncodingBlob,
+		},
+		{
+			name:     "MCP Apps protocol",
+			content:  &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"},
+			encoding: EncodingText,
+			opts:     []Option{WithProtocol(ProtocolTypeMCPApps)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := CreateUIResource("ui://test/widget", tt.content, tt.encoding, tt.opts...)
+			assert.NoError(t, err)
+			assert.NoError(t, ValidateUIResource(resource))
+		})
+	}
+}
+
+func TestValidateUIResource_Errors(t *testing.T) {
+	valid := func() *UIResource {
+		return &UIResource{
+			Type: "resource",
+			Resource: ResourceContent{
+				URI:      "ui://test/widget",
+				MimeType: MimeTypeHTML,
+				Text:     "<p>Hi</p>",
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(r *UIResource)
+		field   string
+		wantErr error
+	}{
+		{
+			name:    "wrong type",
+			mutate:  func(r *UIResource) { r.Type = "text" },
+			field:   "type",
+			wantErr: ErrInvalidResourceType,
+		},
+		{
+			name:    "wrong URI scheme",
+			mutate:  func(r *UIResource) { r.Resource.URI = "https://example.com" },
+			field:   "resource.uri",
+			wantErr: ErrInvalidURI,
+		},
+		{
+			name:    "unknown MIME type",
+			mutate:  func(r *UIResource) { r.Resource.MimeType = "application/json" },
+			field:   "resource.mimeType",
+			wantErr: ErrUnknownMimeType,
+		},
+		{
+			name:    "text and blob",
+			mutate:  func(r *UIResource) { r.Resource.Blob = "PHA+SGk8L3A+" },
+			field:   "resource",
+			wantErr: ErrAmbiguousContent,
+		},
+		{
+			name:    "no content",
+			mutate:  func(r *UIResource) { r.Resource.Text = "" },
+			field:   "resource",
+			wantErr: ErrAmbiguousContent,
+		},
+		{
+			name: "invalid base64",
+			mutate: func(r *UIResource) {
+				r.Resource.Text = ""
+				r.Resource.Blob = "not base64!"
+			},
+			field:   "resource.blob",
+			wantErr: ErrInvalidBase64,
+		},
+		{
+			name: "invalid UTF-8 blob",
+			mutate: func(r *UIResource) {
+				r.Resource.Text = ""
+				r.Resource.Blob = "/w=="
+			},
+			field:   "resource.blob",
+			wantErr: ErrInvalidUTF8,
+		},
+		{
+			name:    "blank HTML",
+			mutate:  func(r *UIResource) { r.Resource.Text = "  \n" },
+			field:   "resource.text",
+			wantErr: ErrEmptyHTMLString,
+		},
+		{
+			name: "URI list without URL",
+			mutate: func(r *UIResource) {
+				r.Resource.MimeType = MimeTypeURIList
+				r.Resource.Text = "# only a comment"
+			},
+			field:   "resource.text",
+			wantErr: ErrEmptyIframeURL,
+		},
+		{
+			name: "URI list with relative URL",
+			mutate: func(r *UIResource) {
+				r.Resource.MimeType = MimeTypeURIList
+				r.Resource.Text = "/app"
+			},
+			field:   "resource.text",
+			wantErr: ErrInvalidIframeURL,
+		},
+		{
+			name: "URI list spelled differently",
+			mutate: func(r *UIResource) {
+				r.Resource.MimeType = "Text/URI-List"
+				r.Resource.Text = "/app"
+			},
+			field:   "resource.text",
+			wantErr: ErrInvalidIframeURL,
+		},
+		{
+			name:    "MIME type with extra parameter",
+			mutate:  func(r *UIResource) { r.Resource.MimeType = "text/html; profile=other" },
+			field:   "resource.mimeType",
+			wantErr: ErrUnknownMimeType,
+		},
+		{
+			name: "blank remote DOM script",
+			mutate: func(r *UIResource) {
+				r.Resource.MimeType = MimeTypeRemoteDomReact
+				r.Resource.Text = " "
+			},
+			field:   "resource.text",
+			wantErr: ErrEmptyScript,
+		},
+		{
+			name: "frame size with one value",
+			mutate: func(r *UIResource) {
+				r.Resource.Meta = map[string]interface{}{UIMetadataPrefix + UIMetadataKeyPreferredFrameSize: []string{"800px"}}
+			},
+			field:   `resource._meta["mcpui.dev/ui-preferred-frame-size"]`,
+			wantErr: ErrInvalidMetadataValue,
+		},
+		{
+			name: "frame size with numbers",
+			mutate: func(r *UIResource) {
+				r.Resource.Meta = map[string]interface{}{UIMetadataPrefix + UIMetadataKeyPreferredFrameSize: []interface{}{800.0, 600.0}}
+			},
+			field:   `resource._meta["mcpui.dev/ui-preferred-frame-size"]`,
+			wantErr: ErrInvalidMetadataValue,
+		},
+		{
+			name: "render data not an object",
+			mutate: func(r *UIResource) {
+				r.Resource.Meta = map[string]interface{}{UIMetadataPrefix + UIMetadataKeyInitialRenderData: "dark"}
+			},
+			field:   `resource._meta["mcpui.dev/ui-initial-render-data"]`,
+			wantErr: ErrInvalidMetadataValue,
+		},
+		{
+			name: "resource URI metadata not a string",
+			mutate: func(r *UIResource) {
+				r.Meta = map[string]interface{}{ResourceURIMetaKey: 1}
+			},
+			field:   `_meta["ui/resourceUri"]`,
+			wantErr: ErrInvalidMetadataValue,
+		},
+		{
+			name: "resource URI metadata mismatch",
+			mutate: func(r *UIResource) {
+				r.Resource.Meta = map[string]interface{}{ResourceURIMetaKey: "ui://other"}
+			},
+			field:   `resource._meta["ui/resourceUri"]`,
+			wantErr: ErrResourceURIMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := valid()
+			tt.mutate(resource)
+
+			err := ValidateUIResource(resource)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			var fieldErr *FieldError
+			if assert.ErrorAs(t, err, &fieldErr) {
+				assert.Equal(t, tt.field, fieldErr.Field)
+				assert.Contains(t, err.Error(), tt.field+": ")
+			}
+		})
+	}
+}
+
+func TestValidateUIResource_MimeTypeSpelling(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		text     string
+	}{
+		{name: "case", mimeType: "Text/HTML", text: "<p>Hi</p>"},
+		{name: "parameter spacing", mimeType: "text/html; profile=mcp-app", text: "<p>Hi</p>"},
+		{name: "remote DOM without space", mimeType: "application/vnd.mcp-ui.remote-dom+javascript;framework=react", text: "render()"},
+		{name: "URI list with padding", mimeType: " text/uri-list ", text: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &UIResource{
+				Type:     "resource",
+				Resource: ResourceContent{URI: "ui://test/widget", MimeType: tt.mimeType, Text: tt.text},
+			}
+			assert.NoError(t, ValidateUIResource(resource))
+		})
+	}
+}
+
+func TestValidateUIResource_ReportsAllProblems(t *testing.T) {
+	resource := &UIResource{
+		Type: "resource",
+		Resource: ResourceContent{
+			URI:      "http://example.com",
+			MimeType: "text/plain",
+			Text:     "hi",
+			Blob:     "aGk=",
+			Meta:     map[string]interface{}{ResourceURIMetaKey: "ui://other"},
+		},
+	}
+
+	err := ValidateUIResource(resource)
+	assert.ErrorIs(t, err, ErrInvalidURI)
+	assert.ErrorIs(t, err, ErrUnknownMimeType)
+	assert.ErrorIs(t, err, ErrAmbiguousContent)
+	assert.ErrorIs(t, err, ErrResourceURIMismatch)
+
+	var joined interface{ Unwrap() []error }
+	if assert.True(t, errors.As(err, &joined)) {
+		assert.Len(t, joined.Unwrap(), 4)
+	}
+}
+
+func TestValidateUIResource_DecodedJSON(t *testing.T) {
+	data := `{
+		"type": "resource",
+		"resource": {
+			"uri": "ui://test/widget",
+			"mimeType": "text/html;profile=mcp-app",
+			"blob": "PHA+SGk8L3A+",
+			"_meta": {
+				"mcpui.dev/ui-preferred-frame-size": ["800px", "600px"],
+				"mcpui.dev/ui-initial-render-data": {"theme": "dark"},
+				"ui/resourceUri": "ui://test/widget"
+			}
+		}
+	}`
+
+	var resource UIResource
+	assert.NoError(t, json.Unmarshal([]byte(data), &resource))
+	assert.NoError(t, ValidateUIResource(&resource))
+}
+
+func TestValidateUIResource_Nil(t *testing.T) {
+	assert.ErrorIs(t, ValidateUIResource(nil), ErrNilResource)
+}
@@ -0,0 +1,181 @@
+package mcpuiserver
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotGenericResource is returned by ForProtocol for resources that are
+// already in a protocol's form
+var ErrNotGenericResource = errors.New("resource is not a generic MCP-UI resource")
+
+// ProtocolTypes lists every protocol a resource can be produced for, in the
+// order CreateUIResourceVariants builds them
+var ProtocolTypes = []ProtocolType{ProtocolTypeGeneric, ProtocolTypeAppsSDK, ProtocolTypeMCPApps}
+
+// CreateUIResourceVariants creates one resource per protocol in
+// ProtocolTypes from a single widget definition, for example to cache
+// ready-made variants or to answer resources/read for any host.
+//
+// The options are applied to every variant. BaseURL, Version and Config from
+// WithProtocolConfig or the WithProtocol* options are shared by the adapter
+// variants and the protocol type is replaced per variant. The generic variant
+// has no protocol, and external URLs are wrapped for the Apps SDK and MCP
+// Apps variants as with WithExternalURLWrapping.
+//
+// Example:
+//
+//	variants, err := CreateUIResourceVariants(
+//	    "ui://greeting",
+//	    &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<h1>Hello</h1>"},
+//	    EncodingText,
+//	    WithProtocolVersion("v2"),
+//	)
+//	resource := variants[ProtocolTypeMCPApps]
+func CreateUIResourceVariants(uri string, content ResourceContentPayload, encoding Encoding, opts ...Option) (map[ProtocolType]*UIResource, error) {
+	variants := make(map[ProtocolType]*UIResource, len(ProtocolTypes))
+	for _, protocol := range ProtocolTypes {
+		variantOpts := append(append([]Option{}, opts...), withVariantProtocol(protocol))
+		resource, err := CreateUIResource(uri, content, encoding, variantOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("%s variant: %w", protocol, err)
+		}
+		variants[protocol] = resource
+	}
+	return variants, nil
+}
+
+// withVariantProtocol selects the protocol of one variant without changing
+// the caller's ProtocolConfig
+func withVariantProtocol(protocol ProtocolType) Option {
+	return func(o *CreateUIResourceOptions) {
+		if protocol == ProtocolTypeGeneric {
+			o.Protocol = nil
+			o.WrapExternalURL = false
+			return
+		}
+		config := ProtocolConfig{}
+		if o.Protocol != nil {
+			config = *o.Protocol
+		}
+		config.Type = protocol
+		o.Protocol = &config
+		o.WrapExternalURL = o.Content.contentType() == ContentTypeExternalURL
+	}
+}
+
+// ForProtocol returns a copy of a generic resource in the form a protocol's
+// hosts expect, using the default adapter location. See ForProtocolConfig.
+//
+// Example:
+//
+//	appsResource, err := resource.ForProtocol(ProtocolTypeMCPApps)
+func (r *UIResource) ForProtocol(protocol ProtocolType) (*UIResource, error) {
+	return r.ForProtocolConfig(&ProtocolConfig{Type: protocol})
+}
+
+// ForProtocolConfig returns a copy of a generic resource, such as one
+// created without a protocol or decoded from an upstream server, converted
+// for config. The content is decoded, the adapter is applied as
+// CreateUIResource would (external URLs are wrapped), and the result is
+// re-encoded as text or blob like the original with the protocol's MIME
+// type. Metadata and annotations are copied, so the variants never share maps
+// with r. Transformers are not run again, since r already went through them.
+// A recorded content hash, and the version in a versioned URI, are updated
+// for the converted content.
+//
+// Resources whose MIME type already belongs to an adapter return
+// ErrNotGenericResource.
+func (r *UIResource) ForProtocolConfig(config *ProtocolConfig) (*UIResource, error) {
+	if r == nil {
+		return nil, ErrNilResource
+	}
+
+	content, err := r.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	options := &CreateUIResourceOptions{URI: r.Resource.URI, Content: content}
+	if config != nil && config.Type != ProtocolTypeGeneric {
+		options.Protocol = config
+		options.WrapExternalURL = true
+	}
+	contentString, mimeType, err := renderContent(content, options)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := &UIResource{
+		Type: r.Type,
+		Resource: ResourceContent{
+			URI:      r.Resource.URI,
+			MimeType: mimeType,
+			Meta:     cloneMap(r.Resource.Meta),
+		},
+		Annotations: cloneMap(r.Annotations),
+		Meta:        cloneMap(r.Meta),
+	}
+	if _, ok := converted.Resource.Meta[ContentHashMetaKey]; ok {
+		converted.Resource.URI = applyContentHash(converted.Resource.Meta, r.Resource.URI,
+			contentParts{contentString}, uriVersionStyle(r.Resource.URI))
+	}
+	if r.Resource.Blob != "" {
+		converted.Resource.Blob = encodeBase64(contentString)
+	} else {
+		converted.Resource.Text = contentString
+	}
+	return converted, nil
+}
+
+// payload reconstructs the content payload of a generic resource
+func (r *UIResource) payload() (ResourceContentPayload, error) {
+	text, err := r.decodedText()
+	if err != nil {
+		return nil, err
+	}
+
+	var content ResourceContentPayload
+	mimeType := r.Resource.MimeType
+	switch {
+	case sameMimeType(mimeType, MimeTypeHTML):
+		content = &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: text}
+	case sameMimeType(mimeType, MimeTypeURIList):
+		content = &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: firstURI(text)}
+	case sameMimeType(mimeType, MimeTypeRemoteDomReact):
+		content = &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: text, Framework: FrameworkReact}
+	case sameMimeType(mimeType, MimeTypeRemoteDomWC):
+		content = &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: text, Framework: FrameworkWebComponents}
+	default:
+		return nil, fmt.Errorf("%w: MIME type %q", ErrNotGenericResource, r.Resource.MimeType)
+	}
+	if err := content.validate(); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// decodedText returns the text, or the decoded blob
+func (r *UIResource) decodedText() (string, error) {
+	if r.Resource.Blob == "" {
+		return r.Resource.Text, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(r.Resource.Blob)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+	}
+	return string(decoded), nil
+}
+
+// firstURI returns the first URI of a text/uri-list body, skipping comments
+func firstURI(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package mcpuiserver
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUIResourceVariants(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   ResourceContentPayload
+		wantMimes map[ProtocolType]string
+	}{
+		{
+			name:    "raw HTML",
+			content: &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"},
+			wantMimes: map[ProtocolType]string{
+				ProtocolTypeGeneric: MimeTypeHTML,
+				ProtocolTypeAppsSDK: MimeTypeAppsSdkAdapter,
+				ProtocolTypeMCPApps: MimeTypeMCPAppsAdapter,
+			},
+		},
+		{
+			name:    "external URL",
+			content: &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com/app"},
+			wantMimes: map[ProtocolType]string{
+				ProtocolTypeGeneric: MimeTypeURIList,
+				ProtocolTypeAppsSDK: MimeTypeAppsSdkAdapter,
+				ProtocolTypeMCPApps: MimeTypeMCPAppsAdapter,
+			},
+		},
+		{
+			name:    "remote DOM",
+			content: &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkWebComponents},
+			wantMimes: map[ProtocolType]string{
+				ProtocolTypeGeneric: MimeTypeRemoteDomWC,
+				ProtocolTypeAppsSDK: MimeTypeAppsSdkAdapter,
+				ProtocolTypeMCPApps: MimeTypeMCPAppsAdapter,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := CreateUIResourceVariants("ui://test/widget", tt.content, EncodingBlob,
+				WithStrict(), WithUIMetadata(map[string]interface{}{UIMetadataKeyPreferredFrameSize: []string{"1px", "1px"}}))
+
+			assert.NoError(t, err)
+			assert.Len(t, variants, len(ProtocolTypes))
+			for protocol, wantMime := range tt.wantMimes {
+				resource := variants[protocol]
+				if !assert.NotNil(t, resource, protocol) {
+					continue
+				}
+				assert.Equal(t, wantMime, resource.Resource.MimeType, protocol)
+				assert.NotEmpty(t, resource.Resource.Blob, protocol)
+				assert.Contains(t, resource.Resource.Meta, UIMetadataPrefix+UIMetadataKeyPreferredFrameSize, protocol)
+				assert.NoError(t, ValidateUIResource(resource), protocol)
+			}
+		})
+	}
+}
+
+func TestCreateUIResourceVariants_SharesProtocolConfig(t *testing.T) {
+	config := &ProtocolConfig{Type: ProtocolTypeAppsSDK, BaseURL: "https://cdn.example.com", Version: "v2"}
+	variants, err := CreateUIResourceVariants("ui://test/widget",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText, WithProtocolConfig(config))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProtocolTypeAppsSDK, config.Type, "caller's config must not change")
+	assert.NotContains(t, variants[ProtocolTypeGeneric].Resource.Text, "<script")
+	assert.Contains(t, variants[ProtocolTypeAppsSDK].Resource.Text, "https://cdn.example.com/appssdk-v2.js")
+	assert.Contains(t, variants[ProtocolTypeMCPApps].Resource.Text, "https://cdn.example.com/mcpapps-v2.js")
+}
+
+func TestCreateUIResourceVariants_Error(t *testing.T) {
+	_, err := CreateUIResourceVariants("ui://test/widget",
+		&ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "/relative"}, EncodingText)
+
+	assert.ErrorIs(t, err, ErrInvalidIframeURL)
+	assert.Contains(t, err.Error(), "appssdk variant: ")
+}
+
+func TestUIResource_ForProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  ResourceContentPayload
+		encoding Encoding
+	}{
+		{
+			name:     "raw HTML text",
+			content:  &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<html><head></head><body>Grüße</body></html>"},
+			encoding: EncodingText,
+		},
+		{
+			name:     "raw HTML blob",
+			content:  &RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Grüße</p>"},
+			encoding: EncodingBlob,
+		},
+		{
+			name:     "external URL blob",
+			content:  &ExternalURLPayload{Type: ContentTypeExternalURL, IframeURL: "https://example.com/app"},
+			encoding: EncodingBlob,
+		},
+		{
+			name:     "remote DOM React",
+			content:  &RemoteDOMPayload{Type: ContentTypeRemoteDOM, Script: "root.append('hi')", Framework: FrameworkReact},
+			encoding: EncodingText,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, protocol := range ProtocolTypes {
+			t.Run(tt.name+"/"+string(protocol), func(t *testing.T) {
+				opts := []Option{WithMetadata(map[string]interface{}{"custom": "value"})}
+				generic, err := CreateUIResource("ui://test/widget", tt.content, tt.encoding, opts...)
+				assert.NoError(t, err)
+
+				want, err := CreateUIResourceVariants("ui://test/widget", tt.content, tt.encoding, opts...)
+				assert.NoError(t, err)
+
+				got, err := generic.ForProtocol(protocol)
+				assert.NoError(t, err)
+				assert.Equal(t, want[protocol], got)
+
+				got.Resource.Meta["custom"] = "changed"
+				assert.Equal(t, "value", generic.Resource.Meta["custom"], "metadata must be copied")
+			})
+		}
+	}
+}
+
+func TestUIResource_ForProtocolConfig(t *testing.T) {
+	generic := &UIResource{
+		Type: "resource",
+		Resource: ResourceContent{
+			URI:      "ui://test/widget",
+			MimeType: MimeTypeURIList,
+			Blob:     base64.StdEncoding.EncodeToString([]byte("# comment\nhttps://example.com/app\nhttps://example.com/other")),
+		},
+		Annotations: map[string]interface{}{"priority": 0.5},
+	}
+
+	got, err := generic.ForProtocolConfig(&ProtocolConfig{Type: ProtocolTypeMCPApps, BaseURL: "https://cdn.example.com", Version: "v3"})
+	assert.NoError(t, err)
+	assert.Equal(t, MimeTypeMCPAppsAdapter, got.Resource.MimeType)
+	assert.Empty(t, got.Resource.Text)
+	assert.Equal(t, generic.Annotations, got.Annotations)
+
+	page, err := base64.StdEncoding.DecodeString(got.Resource.Blob)
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `src="https://example.com/app"`)
+	assert.Contains(t, string(page), "https://cdn.example.com/mcpapps-v3.js")
+	assert.False(t, strings.Contains(string(page), "example.com/other"))
+}
+
+func TestUIResource_ForProtocolMimeTypeSpelling(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		text     string
+		want     string
+	}{
+		{mimeType: "Text/HTML", text: "<p>Hi</p>", want: "<p>Hi</p>"},
+		{mimeType: "text/uri-list ", text: "https://example.com/app", want: `src="https://example.com/app"`},
+		{mimeType: "application/vnd.mcp-ui.remote-dom+javascript;framework=react", text: "root.append('hi')", want: `"framework":"react"`},
+		{mimeType: "Application/Vnd.MCP-UI.Remote-DOM+JavaScript; framework=webcomponents", text: "root.append('hi')", want: `"framework":"webcomponents"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			generic := &UIResource{Type: "resource", Resource: ResourceContent{
+				URI: "ui://test/widget", MimeType: tt.mimeType, Text: tt.text,
+			}}
+
+			got, err := generic.ForProtocol(ProtocolTypeMCPApps)
+			assert.NoError(t, err)
+			assert.Equal(t, MimeTypeMCPAppsAdapter, got.Resource.MimeType)
+			assert.Contains(t, got.Resource.Text, tt.want)
+		})
+	}
+}
+
+func TestUIResource_ForProtocolErrors(t *testing.T) {
+	adapted, err := CreateUIResource("ui://test/widget",
+		&RawHTMLPayload{Type: ContentTypeRawHTML, HTMLString: "<p>Hi</p>"}, EncodingText, WithProtocol(ProtocolTypeMCPApps))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		resource *UIResource
+		wantErr  error
+	}{
+		{
+			name:     "nil resource",
+			resource: nil,
+			wantErr:  ErrNilResource,
+		},
+		{
+			name:     "already adapted",
+			resource: adapted,
+			wantErr:  ErrNotGenericResource,
+		},
+		{
+			name: "invalid blob",
+			resource: &UIResource{Type: "resource", Resource: ResourceContent{
+				URI: "ui://test/widget", MimeType: MimeTypeHTML, Blob: "not base64!",
+			}},
+			wantErr: ErrInvalidBase64,
+		},
+		{
+			name: "empty HTML",
+			resource: &UIResource{Type: "resource", Resource: ResourceContent{
+				URI: "ui://test/widget", MimeType: MimeTypeHTML,
+			}},
+			wantErr: ErrEmptyHTMLString,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resource.ForProtocol(ProtocolTypeAppsSDK)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
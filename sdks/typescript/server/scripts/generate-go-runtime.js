@@ -0,0 +1,65 @@
+import { build } from 'esbuild';
+import { writeFileSync } from 'fs';
+import { fileURLToPath } from 'url';
+import { dirname, join } from 'path';
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = dirname(__filename);
+
+const ADAPTERS = [
+  { adapterName: 'appssdk', goPackage: 'appssdk', title: 'Apps SDK' },
+  { adapterName: 'mcp-apps', goPackage: 'mcpapps', title: 'MCP Apps' },
+];
+
+// Comment-only lines are dropped; the Go SDK ships the runtime without them
+const COMMENT_LINE = /^\s*(\/\/|\/\*|\*)/;
+
+async function generateGoRuntime({ adapterName, goPackage, title }) {
+  const source = `sdks/typescript/server/src/adapters/${adapterName}/adapter-runtime.ts`;
+  try {
+    // Same options as bundle-adapter.js so both SDKs inject the same script
+    const result = await build({
+      entryPoints: [join(__dirname, `../src/adapters/${adapterName}/adapter-runtime.ts`)],
+      bundle: false,
+      write: false,
+      format: 'esm',
+      platform: 'browser',
+      target: 'es2020',
+      minify: false,
+    });
+
+    const code = result.outputFiles[0].text
+      .split('\n')
+      .filter((line) => !COMMENT_LINE.test(line))
+      .join('\n');
+
+    // The script is embedded in a Go raw string literal, which cannot contain backticks
+    if (code.includes('`')) {
+      throw new Error(`${source} must not use template literals or backticks`);
+    }
+
+    const today = new Date().toISOString().slice(0, 10);
+    const outputContent = `// Code generated by sdks/typescript/server/scripts/generate-go-runtime.js. DO NOT EDIT.
+
+package ${goPackage}
+
+// adapterRuntimeScript contains the JavaScript runtime for the ${title} adapter.
+// This code is compiled from the TypeScript adapter-runtime.ts file; run
+// \`pnpm run generate:go-runtime\` in sdks/typescript/server after editing it.
+// Generated from: ${source}
+// Last updated: ${today}
+const adapterRuntimeScript = \`${code}\`
+`;
+
+    const outputGoPath = join(__dirname, `../../../go/server/adapters/${goPackage}/runtime.go`);
+    writeFileSync(outputGoPath, outputContent);
+    console.log(`✅ Successfully generated Go runtime for ${adapterName} adapter`);
+  } catch (error) {
+    console.error(`❌ Failed to generate Go runtime for ${adapterName} adapter:`, error);
+    process.exit(1);
+  }
+}
+
+for (const adapter of ADAPTERS) {
+  await generateGoRuntime(adapter);
+}
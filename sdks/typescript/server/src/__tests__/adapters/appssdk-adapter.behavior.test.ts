@@ -201,6 +201,20 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
       expect(env.dispatchedToIframe.length).toBe(0);
     });
 
+    it('should not write below the configured log level', () => {
+      const originalLog = console.log;
+      const log = vi.fn();
+      console.log = log;
+      try {
+        initializeAdapter(env, { logLevel: 'warn' });
+      } finally {
+        console.log = originalLog;
+      }
+
+      expect(log).not.toHaveBeenCalled();
+      expect(env.dispatchedToIframe.length).toBeGreaterThan(0);
+    });
+
     it('should send initial render data on initialization', () => {
       env.setOpenAIData({
         toolInput: { query: 'initial input' },
@@ -303,7 +317,7 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
 
         expect(response).toBeDefined();
         expect(response?.payload?.error).toBeDefined();
-        expect(response?.payload?.error?.message).toContain('Tool not found');
+        expect(response?.payload?.error).toEqual({ code: 'host-error', message: 'Tool not found' });
       });
 
       it('should send error when callTool is not available', async () => {
@@ -322,6 +336,7 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
             msg.type === 'ui-message-response' && msg.payload?.messageId === 'tool-unsupported-1',
         );
 
+        expect(response?.payload?.error?.code).toBe('unsupported');
         expect(response?.payload?.error?.message).toContain('not supported');
       });
     });
@@ -428,6 +443,48 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
 
         expect(response?.payload?.response).toEqual({ ignored: true });
       });
+
+      it('should follow a declared intent route', async () => {
+        env.restore();
+        env = createTestEnvironment();
+        initializeAdapter(env, {
+          intentHandling: 'ignore',
+          intentRoutes: {
+            add_to_cart: {
+              action: 'tool',
+              toolName: 'cart_add',
+              paramMapping: { sku: 'productId' },
+            },
+            '*': { action: 'prompt', template: 'The user chose {{$intent}}: {{$params}}' },
+          },
+        });
+        env.mockOpenAI.callTool.mockResolvedValue({ added: true });
+        env.mockOpenAI.sendFollowUpMessage.mockResolvedValue(undefined);
+        env.clear();
+
+        env.sendMcpUiMessage({
+          type: 'intent',
+          messageId: 'routed-tool-1',
+          payload: { intent: 'add_to_cart', params: { productId: 'p-1', quantity: 2 } },
+        });
+        env.sendMcpUiMessage({
+          type: 'intent',
+          messageId: 'routed-prompt-1',
+          payload: { intent: 'compare', params: { ids: [1, 2] } },
+        });
+
+        await vi.runAllTimersAsync();
+
+        expect(env.mockOpenAI.callTool).toHaveBeenCalledWith('cart_add', { sku: 'p-1' });
+        expect(env.mockOpenAI.sendFollowUpMessage).toHaveBeenCalledWith({
+          prompt: 'The user chose compare: {"ids":[1,2]}',
+        });
+        const response = env.dispatchedToIframe.find(
+          (msg) =>
+            msg.type === 'ui-message-response' && msg.payload?.messageId === 'routed-tool-1',
+        );
+        expect(response?.payload?.response).toEqual({ added: true });
+      });
     });
 
     describe('notify message', () => {
@@ -529,6 +586,53 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
         expect(renderData?.payload?.renderData?.locale).toBe('de-DE');
       });
     });
+
+    describe('ui-request-data message', () => {
+      it('should call the tool of the registered data provider', async () => {
+        env.restore();
+        env = createTestEnvironment();
+        initializeAdapter(env, {
+          dataProviders: {
+            weather: { toolName: 'get_weather', paramMapping: { city: 'location' } },
+          },
+        });
+        env.mockOpenAI.callTool.mockResolvedValue({ temperature: 21 });
+        env.clear();
+
+        env.sendMcpUiMessage({
+          type: 'ui-request-data',
+          messageId: 'data-1',
+          payload: { requestType: 'weather', params: { location: 'Paris', units: 'metric' } },
+        });
+
+        await vi.runAllTimersAsync();
+
+        expect(env.mockOpenAI.callTool).toHaveBeenCalledWith('get_weather', { city: 'Paris' });
+        const response = env.dispatchedToIframe.find(
+          (msg) => msg.type === 'ui-message-response' && msg.payload?.messageId === 'data-1',
+        );
+        expect(response?.payload?.response).toEqual({ temperature: 21 });
+      });
+
+      it('should respond with an error without a data provider', async () => {
+        env.sendMcpUiMessage({
+          type: 'ui-request-data',
+          messageId: 'data-2',
+          payload: { requestType: 'unknown', params: {} },
+        });
+
+        await vi.runAllTimersAsync();
+
+        expect(env.mockOpenAI.callTool).not.toHaveBeenCalled();
+        const response = env.dispatchedToIframe.find(
+          (msg) =>
+            msg.type === 'ui-message-response' &&
+            msg.payload?.messageId === 'data-2' &&
+            msg.payload?.error,
+        );
+        expect(response?.payload?.error).toBeDefined();
+      });
+    });
   });
 
   describe('Apps SDK Events', () => {
@@ -555,6 +659,29 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
       expect(renderData?.payload?.renderData?.toolInput).toEqual({ newQuery: 'updated' });
       expect(renderData?.payload?.renderData?.theme).toBe('dark');
     });
+
+    it('should send teardown once when the page is hidden', () => {
+      env.window.dispatchEvent(new Event('pagehide'));
+      env.window.dispatchEvent(new Event('pagehide'));
+
+      const teardowns = env.dispatchedToIframe.filter(
+        (msg) => msg.type === 'ui-lifecycle-teardown',
+      );
+
+      expect(teardowns).toHaveLength(1);
+      expect(teardowns[0].payload?.reason).toBe('pagehide');
+    });
+
+    it('should send teardown when the adapter is uninstalled', () => {
+      const api = (env.window as unknown as { MCPUIAppsSdkAdapter: { uninstall(): void } })
+        .MCPUIAppsSdkAdapter;
+      api.uninstall();
+
+      const teardown = env.dispatchedToIframe.find((msg) => msg.type === 'ui-lifecycle-teardown');
+
+      expect(teardown?.payload?.reason).toBe('adapter-uninstalled');
+      expect(env.window.removeEventListener).toHaveBeenCalledWith('pagehide', expect.any(Function));
+    });
   });
 
   describe('Render Data Contents', () => {
@@ -635,6 +762,7 @@ describe('Apps SDK Adapter - Behavioral Tests', () => {
         (msg) => msg.type === 'ui-message-response' && msg.payload?.messageId === 'tool-timeout-1',
       );
 
+      expect(response?.payload?.error?.code).toBe('timeout');
       expect(response?.payload?.error?.message).toContain('timed out');
     });
 
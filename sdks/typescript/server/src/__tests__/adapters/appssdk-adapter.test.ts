@@ -68,6 +68,33 @@ describe('Apps SDK Adapter - Script Generation', () => {
       expect(script).toContain('"hostOrigin":"https://custom.com"');
     });
 
+    it('should inject custom logLevel config', () => {
+      const script = getAppsSdkAdapterScript({ logLevel: 'warn' });
+
+      expect(script).toContain('"logLevel":"warn"');
+      expect(script).toContain('createLeveledLogger');
+    });
+
+    it('should inject intent routes', () => {
+      const script = getAppsSdkAdapterScript({
+        intentRoutes: { '*': { action: 'prompt', template: 'Intent {{$intent}}' } },
+      });
+
+      expect(script).toContain(
+        '"intentRoutes":{"*":{"action":"prompt","template":"Intent {{$intent}}"}}',
+      );
+    });
+
+    it('should inject data providers', () => {
+      const script = getAppsSdkAdapterScript({
+        dataProviders: { weather: { toolName: 'get_weather', paramMapping: { city: 'location' } } },
+      });
+
+      expect(script).toContain(
+        '"dataProviders":{"weather":{"toolName":"get_weather","paramMapping":{"city":"location"}}}',
+      );
+    });
+
     it('should inject multiple config options', () => {
       const config: AppsSdkAdapterConfig = {
         timeout: 10000,
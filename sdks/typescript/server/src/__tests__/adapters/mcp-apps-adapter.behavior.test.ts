@@ -191,6 +191,22 @@ describe('MCP Apps Adapter - Behavioral Tests', () => {
       });
     });
 
+    it('should send the configured app identity and capabilities', () => {
+      initializeAdapter(env, {
+        appInfo: { name: 'weather-widget', version: '2.1.0' },
+        appCapabilities: { experimental: { charts: true } },
+      });
+
+      const initRequest = env.sentToHost.find(
+        (msg): msg is JsonRpcRequest => 'id' in msg && msg.method === 'ui/initialize',
+      );
+
+      expect(initRequest?.params).toMatchObject({
+        appInfo: { name: 'weather-widget', version: '2.1.0' },
+        appCapabilities: { experimental: { charts: true } },
+      });
+    });
+
     it('should send ui/notifications/initialized after receiving init response', () => {
       initializeAdapter(env);
 
@@ -310,6 +326,37 @@ describe('MCP Apps Adapter - Behavioral Tests', () => {
           (messageRequest?.params?.content as Array<{ type: string; text: string }>)[0].text,
         ).toContain('navigate');
       });
+
+      it('should follow a declared intent route instead of sending a prompt', () => {
+        env.restore();
+        env = createTestEnvironment();
+        initializeAdapter(env, {
+          intentRoutes: {
+            search: { action: 'link', template: 'https://example.com/search?q={{query}}' },
+            '*': { action: 'tool', toolName: 'handle_intent', paramMapping: { q: 'query' } },
+          },
+        });
+        env.clear();
+
+        env.sendMcpUiMessage({
+          type: 'intent',
+          messageId: 'routed-1',
+          payload: { intent: 'search', params: { query: 'a&b' } },
+        });
+        env.sendMcpUiMessage({
+          type: 'intent',
+          messageId: 'routed-2',
+          payload: { intent: 'other', params: { query: 'x', extra: true } },
+        });
+
+        const methods = env.sentToHost.map((msg) => msg.method);
+        const linkRequest = env.sentToHost.find((msg) => msg.method === 'ui/open-link');
+        const toolRequest = env.sentToHost.find((msg) => msg.method === 'tools/call');
+
+        expect(methods).not.toContain('ui/message');
+        expect(linkRequest?.params).toEqual({ url: 'https://example.com/search?q=a%26b' });
+        expect(toolRequest?.params).toEqual({ name: 'handle_intent', arguments: { q: 'x' } });
+      });
     });
 
     describe('link message', () => {
@@ -405,6 +452,26 @@ describe('MCP Apps Adapter - Behavioral Tests', () => {
       });
     });
 
+    describe('tool-input-partial notification', () => {
+      it('should dispatch partial input and render data', () => {
+        env.receiveFromHost({
+          jsonrpc: '2.0',
+          method: 'ui/notifications/tool-input-partial',
+          params: { arguments: { query: 'sea' } },
+        });
+
+        const partial = env.dispatchedToIframe.find(
+          (msg) => msg.type === 'ui-lifecycle-tool-input-partial',
+        );
+        const renderData = env.dispatchedToIframe.find(
+          (msg) => msg.type === 'ui-lifecycle-iframe-render-data',
+        );
+
+        expect(partial?.payload?.arguments).toEqual({ query: 'sea' });
+        expect(renderData?.payload?.renderData).toMatchObject({ toolInput: { query: 'sea' } });
+      });
+    });
+
     describe('tool-result notification', () => {
       it('should dispatch render data when receiving tool-result', () => {
         env.receiveFromHost({
@@ -553,7 +620,57 @@ describe('MCP Apps Adapter - Behavioral Tests', () => {
 
       expect(response).toBeDefined();
       expect(response?.payload?.messageId).toBe('link-req-1');
-      expect(response?.payload?.error).toBeDefined();
+      expect(response?.payload?.error).toEqual({
+        code: 'host-error',
+        message: 'URL blocked by policy',
+      });
+    });
+
+    it('should map JSON-RPC error codes to protocol error codes', () => {
+      env.sendMcpUiMessage({
+        type: 'link',
+        messageId: 'link-req-2',
+        payload: { url: 'https://example.com' },
+      });
+
+      const linkRequest = env.sentToHost.find(
+        (msg): msg is JsonRpcRequest => 'id' in msg && msg.method === 'ui/open-link',
+      );
+
+      env.receiveFromHost({
+        jsonrpc: '2.0',
+        id: linkRequest!.id,
+        error: { code: -32002, message: 'User declined', data: { url: 'https://example.com' } },
+      });
+
+      const response = env.dispatchedToIframe.find(
+        (msg) => msg.type === 'ui-message-response' && msg.payload?.messageId === 'link-req-2',
+      );
+
+      expect(response?.payload?.error).toEqual({
+        code: 'denied',
+        message: 'User declined',
+        data: { url: 'https://example.com' },
+      });
+    });
+
+    it('should send a timeout error when the host does not answer', async () => {
+      env.sendMcpUiMessage({
+        type: 'tool',
+        messageId: 'tool-timeout-1',
+        payload: { toolName: 'slow_tool', params: {} },
+      });
+
+      await vi.advanceTimersByTimeAsync(30000);
+
+      const response = env.dispatchedToIframe.find(
+        (msg) => msg.type === 'ui-message-response' && msg.payload?.messageId === 'tool-timeout-1',
+      );
+
+      expect(response?.payload?.error).toEqual({
+        code: 'timeout',
+        message: 'Request timed out after 30000ms',
+      });
     });
   });
 
@@ -605,5 +722,36 @@ describe('MCP Apps Adapter - Behavioral Tests', () => {
         locale: 'en-US',
       });
     });
+
+    it('should answer ui-request-data with a tools/call of the data provider', () => {
+      env.restore();
+      env = createTestEnvironment();
+      initializeAdapter(env, {
+        dataProviders: { weather: { toolName: 'get_weather', paramMapping: { city: 'location' } } },
+      });
+      env.clear();
+
+      env.sendMcpUiMessage({
+        type: 'ui-request-data',
+        messageId: 'data-1',
+        payload: { requestType: 'weather', params: { location: 'Paris' } },
+      });
+      env.sendMcpUiMessage({
+        type: 'ui-request-data',
+        messageId: 'data-2',
+        payload: { requestType: 'unknown', params: {} },
+      });
+
+      const toolRequest = env.sentToHost.find((msg) => msg.method === 'tools/call');
+      const errorResponse = env.dispatchedToIframe.find(
+        (msg) => msg.type === 'ui-message-response' && msg.messageId === 'data-2',
+      );
+
+      expect(toolRequest?.params).toEqual({ name: 'get_weather', arguments: { city: 'Paris' } });
+      expect(errorResponse?.payload?.error).toEqual({
+        code: 'unsupported',
+        message: 'No data provider registered for request type: unknown',
+      });
+    });
   });
 });
@@ -46,6 +46,36 @@ describe('MCP Apps Adapter - Script Generation', () => {
       expect(script).toContain('"timeout":5000');
     });
 
+    it('should inject app identity, capabilities and log level', () => {
+      const config: McpAppsAdapterConfig = {
+        appInfo: { name: 'weather-widget', version: '2.1.0' },
+        appCapabilities: { experimental: { charts: true } },
+        logLevel: 'error',
+      };
+
+      const script = getMcpAppsAdapterScript(config);
+
+      expect(script).toContain('"appInfo":{"name":"weather-widget","version":"2.1.0"}');
+      expect(script).toContain('"appCapabilities":{"experimental":{"charts":true}}');
+      expect(script).toContain('"logLevel":"error"');
+    });
+
+    it('should inject intent routes', () => {
+      const script = getMcpAppsAdapterScript({
+        intentRoutes: { share: { action: 'drop' } },
+      });
+
+      expect(script).toContain('"intentRoutes":{"share":{"action":"drop"}}');
+    });
+
+    it('should inject data providers', () => {
+      const script = getMcpAppsAdapterScript({
+        dataProviders: { weather: { toolName: 'get_weather' } },
+      });
+
+      expect(script).toContain('"dataProviders":{"weather":{"toolName":"get_weather"}}');
+    });
+
     it('should expose global McpAppsAdapter API', () => {
       const script = getMcpAppsAdapterScript();
 
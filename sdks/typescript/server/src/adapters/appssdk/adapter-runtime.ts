@@ -3,28 +3,188 @@
  *
  * This module enables MCP-UI embeddable widgets to run in Apps SDK environments (e.g., ChatGPT)
  * by intercepting MCP-UI protocol messages and translating them to the Apps SDK API (e.g., window.openai).
+ *
+ * Note: The Go SDK embeds the compiled script in a raw string literal (scripts/generate-go-runtime.js),
+ * so this file must not use template literals.
  */
 
-import type { AppsSdkAdapterConfig, MCPUIMessage, PendingRequest, RenderData } from './types.js';
+import type {
+  AppsSdkAdapterConfig,
+  MCPUIMessage,
+  MCPUIRequestDataMessage,
+  PendingRequest,
+  RenderData,
+} from './types.js';
 import type { UIActionResult } from '../../types.js';
+import type {
+  AdapterLogger,
+  AdapterLogLevel,
+  DataProvider,
+  IntentRoute,
+  LeveledLogger,
+  ProtocolError,
+  ProtocolErrorCode,
+} from '../types.js';
 
 type ParentPostMessage = Window['postMessage'];
 
+/**
+ * Adapter configuration with defaults applied
+ */
+type ResolvedConfig = Required<
+  Omit<AppsSdkAdapterConfig, 'logger' | 'logLevel' | 'intentRoutes' | 'dataProviders'>
+> & {
+  logger: LeveledLogger;
+  intentRoutes: Record<string, IntentRoute> | null;
+  dataProviders: Record<string, DataProvider> | null;
+};
+
+// Numeric order of the log levels; a method is kept when its level is at or above the threshold
+const LOG_LEVELS: Record<AdapterLogLevel, number> = {
+  debug: 0,
+  info: 1,
+  warn: 2,
+  error: 3,
+  silent: 4,
+};
+
+/**
+ * Wrap a logger so that methods below the given level are no-ops
+ */
+function createLeveledLogger(logger: AdapterLogger, level?: AdapterLogLevel): LeveledLogger {
+  const threshold = LOG_LEVELS[level as AdapterLogLevel] ?? LOG_LEVELS.debug;
+  const noop = () => {};
+  const bind = (method: keyof LeveledLogger, minLevel: number) =>
+    threshold <= minLevel && typeof (logger as Partial<LeveledLogger>)[method] === 'function'
+      ? (logger as LeveledLogger)[method].bind(logger)
+      : noop;
+  return {
+    debug: bind('debug', LOG_LEVELS.debug),
+    log: bind('log', LOG_LEVELS.info),
+    info: bind('info', LOG_LEVELS.info),
+    warn: bind('warn', LOG_LEVELS.warn),
+    error: bind('error', LOG_LEVELS.error),
+  };
+}
+
+/**
+ * Render an intent route template, URL-encoding substituted values when encode is set
+ */
+function renderIntentTemplate(
+  template: string,
+  intent: string,
+  params: Record<string, unknown> | undefined,
+  encode: boolean,
+): string {
+  return template.replace(/\{\{\s*([$\w.-]+)\s*\}\}/g, (_match, key: string) => {
+    let value: unknown;
+    if (key === '$intent') {
+      value = intent;
+    } else if (key === '$params') {
+      value = params ?? {};
+    } else {
+      value = params?.[key];
+    }
+    if (value === undefined || value === null) {
+      value = '';
+    }
+    const text = typeof value === 'string' ? value : JSON.stringify(value);
+    return encode ? encodeURIComponent(text) : text;
+  });
+}
+
+/**
+ * Map intent or data request parameters to tool arguments; without a mapping the parameters
+ * are passed unchanged
+ */
+function mapParams(
+  mapping: Record<string, string> | undefined,
+  params: Record<string, unknown> | undefined,
+): Record<string, unknown> {
+  if (!mapping) {
+    return params ?? {};
+  }
+  const result: Record<string, unknown> = {};
+  for (const [target, source] of Object.entries(mapping)) {
+    if (params && source in params) {
+      result[target] = params[source];
+    }
+  }
+  return result;
+}
+
+/**
+ * Find the route for an intent, falling back to the '*' route
+ */
+function resolveIntentRoute(
+  routes: Record<string, IntentRoute> | null,
+  intent: string,
+): IntentRoute | null {
+  if (!routes) {
+    return null;
+  }
+  return routes[intent] ?? routes['*'] ?? null;
+}
+
+/** JSON-RPC error code of each protocol error code; must match the Go SDK's mapping */
+const PROTOCOL_ERROR_CODES: Record<ProtocolErrorCode, number> = {
+  unsupported: -32601,
+  timeout: -32001,
+  denied: -32002,
+  'invalid-params': -32602,
+  'host-error': -32603,
+};
+
+/** Error carrying a protocol error code */
+type CodedError = Error & { code?: string; data?: unknown };
+
+/**
+ * Create an Error that toProtocolError reports with the given code
+ */
+function createProtocolError(code: ProtocolErrorCode, message: string, data?: unknown): CodedError {
+  const error: CodedError = new Error(message);
+  error.code = code;
+  if (data !== undefined) {
+    error.data = data;
+  }
+  return error;
+}
+
+/**
+ * Convert a thrown value to the error sent to the widget; errors without a known code are
+ * reported as host errors
+ */
+function toProtocolError(error: unknown): ProtocolError {
+  const coded = error as CodedError | null;
+  if (coded && typeof coded.code === 'string' && Object.hasOwn(PROTOCOL_ERROR_CODES, coded.code)) {
+    const result: ProtocolError = { code: coded.code as ProtocolErrorCode, message: coded.message };
+    if (coded.data !== undefined) {
+      result.data = coded.data;
+    }
+    return result;
+  }
+  return { code: 'host-error', message: error instanceof Error ? error.message : String(error) };
+}
+
 /**
  * Main adapter class that handles protocol translations
  */
 class MCPUIAppsSdkAdapter {
-  private config: Required<AppsSdkAdapterConfig>;
+  private config: ResolvedConfig;
   private pendingRequests: Map<string, PendingRequest<unknown>> = new Map();
   private messageIdCounter = 0;
   private originalPostMessage: ParentPostMessage | null = null;
+  private pageHideListener: (() => void) | null = null;
+  private tornDown = false;
 
   constructor(config: AppsSdkAdapterConfig = {}) {
     this.config = {
-      logger: config.logger || console,
+      logger: createLeveledLogger(config.logger || console, config.logLevel),
       hostOrigin: config.hostOrigin || window.location.origin,
       timeout: config.timeout || 30000,
       intentHandling: config.intentHandling || 'prompt',
+      intentRoutes: config.intentRoutes || null,
+      dataProviders: config.dataProviders || null,
     };
   }
 
@@ -58,6 +218,13 @@ class MCPUIAppsSdkAdapter {
    * Clean up pending requests and restore original postMessage
    */
   uninstall(): void {
+    // Let the widget clean up before the adapter goes away
+    this.sendTeardown('adapter-uninstalled');
+    if (this.pageHideListener) {
+      window.removeEventListener('pagehide', this.pageHideListener);
+      this.pageHideListener = null;
+    }
+
     // Clear pending requests
     for (const request of this.pendingRequests.values()) {
       clearTimeout(request.timeoutId);
@@ -201,7 +368,7 @@ class MCPUIAppsSdkAdapter {
           this.handleSizeChange(message);
           break;
         case 'ui-request-data':
-          this.handleRequestData(message);
+          await this.handleRequestData(message);
           break;
         default:
           this.config.logger.warn('[MCPUI-Apps SDK Adapter] Unknown message type:', message.type);
@@ -226,7 +393,10 @@ class MCPUIAppsSdkAdapter {
 
     try {
       if (!window.openai?.callTool) {
-        throw new Error('Tool calling is not supported in this environment');
+        throw createProtocolError(
+          'unsupported',
+          'Tool calling is not supported in this environment',
+        );
       }
 
       const result = await this.withTimeout(window.openai.callTool(toolName, params), messageId);
@@ -249,7 +419,10 @@ class MCPUIAppsSdkAdapter {
 
     try {
       if (!window.openai?.sendFollowUpMessage) {
-        throw new Error('Followup turns are not supported in this environment');
+        throw createProtocolError(
+          'unsupported',
+          'Followup turns are not supported in this environment',
+        );
       }
 
       await this.withTimeout(window.openai.sendFollowUpMessage({ prompt }), messageId);
@@ -268,6 +441,18 @@ class MCPUIAppsSdkAdapter {
     const messageId = message.messageId || this.generateMessageId();
     this.sendAcknowledgment(messageId);
 
+    // A route for the intent takes precedence over intentHandling
+    const route = resolveIntentRoute(this.config.intentRoutes, message.payload.intent);
+    if (route) {
+      await this.handleRoutedIntent(
+        messageId,
+        route,
+        message.payload.intent,
+        message.payload.params,
+      );
+      return;
+    }
+
     if (this.config.intentHandling === 'ignore') {
       this.config.logger.log('[MCPUI-Apps SDK Adapter] Intent ignored:', message.payload.intent);
       this.sendSuccessResponse(messageId, { ignored: true });
@@ -276,11 +461,14 @@ class MCPUIAppsSdkAdapter {
 
     // Convert to prompt
     const { intent, params } = message.payload;
-    const prompt = `${intent}${params ? `: ${JSON.stringify(params)}` : ''}`;
+    const prompt = intent + (params ? ': ' + JSON.stringify(params) : '');
 
     try {
       if (!window.openai?.sendFollowUpMessage) {
-        throw new Error('Followup turns are not supported in this environment');
+        throw createProtocolError(
+          'unsupported',
+          'Followup turns are not supported in this environment',
+        );
       }
 
       await this.withTimeout(window.openai.sendFollowUpMessage({ prompt }), messageId);
@@ -291,6 +479,68 @@ class MCPUIAppsSdkAdapter {
     }
   }
 
+  /**
+   * Handle an intent with a declared route
+   */
+  private async handleRoutedIntent(
+    messageId: string,
+    route: IntentRoute,
+    intent: string,
+    params: Record<string, unknown> | undefined,
+  ): Promise<void> {
+    try {
+      switch (route.action) {
+        case 'tool': {
+          if (!window.openai?.callTool) {
+            throw createProtocolError(
+              'unsupported',
+              'Tool calling is not supported in this environment',
+            );
+          }
+          const args = mapParams(route.paramMapping, params);
+          const result = await this.withTimeout(
+            window.openai.callTool(route.toolName, args),
+            messageId,
+          );
+          this.sendSuccessResponse(messageId, result);
+          break;
+        }
+        case 'prompt': {
+          if (!window.openai?.sendFollowUpMessage) {
+            throw createProtocolError(
+              'unsupported',
+              'Followup turns are not supported in this environment',
+            );
+          }
+          const prompt = renderIntentTemplate(route.template, intent, params, false);
+          await this.withTimeout(window.openai.sendFollowUpMessage({ prompt }), messageId);
+          this.sendSuccessResponse(messageId, { success: true });
+          break;
+        }
+        case 'link': {
+          if (!window.openai?.openExternal) {
+            throw createProtocolError(
+              'unsupported',
+              'Navigation is not supported in Apps SDK environment',
+            );
+          }
+          const href = renderIntentTemplate(route.template, intent, params, true);
+          await this.withTimeout(Promise.resolve(window.openai.openExternal({ href })), messageId);
+          this.sendSuccessResponse(messageId, { success: true });
+          break;
+        }
+        case 'drop':
+          this.config.logger.log('[MCPUI-Apps SDK Adapter] Intent dropped:', intent);
+          this.sendSuccessResponse(messageId, { ignored: true });
+          break;
+        default:
+          throw new Error('Unknown intent route action: ' + (route as IntentRoute).action);
+      }
+    } catch (error) {
+      this.sendErrorResponse(messageId, error);
+    }
+  }
+
   /**
    * Handle 'notify' message - log only
    */
@@ -311,7 +561,7 @@ class MCPUIAppsSdkAdapter {
     this.sendAcknowledgment(messageId);
     this.sendErrorResponse(
       messageId,
-      new Error('Navigation is not supported in Apps SDK environment'),
+      createProtocolError('unsupported', 'Navigation is not supported in Apps SDK environment'),
     );
   }
 
@@ -326,12 +576,39 @@ class MCPUIAppsSdkAdapter {
   }
 
   /**
-   * Handle generic data request
+   * Handle generic data request - call the tool of the data provider registered for its type
    */
-  private handleRequestData(message: MCPUIMessage): void {
+  private async handleRequestData(message: MCPUIMessage): Promise<void> {
     const messageId = message.messageId || this.generateMessageId();
     this.sendAcknowledgment(messageId);
-    this.sendErrorResponse(messageId, new Error('Generic data requests not yet implemented'));
+
+    const { requestType, params } = (message as MCPUIRequestDataMessage).payload || {};
+    const provider = this.config.dataProviders?.[requestType];
+
+    try {
+      if (!provider) {
+        throw createProtocolError(
+          'unsupported',
+          'No data provider registered for request type: ' + requestType,
+        );
+      }
+      if (!window.openai?.callTool) {
+        throw createProtocolError(
+          'unsupported',
+          'Tool calling is not supported in this environment',
+        );
+      }
+
+      const args = mapParams(provider.paramMapping, params);
+      const result = await this.withTimeout(
+        window.openai.callTool(provider.toolName, args),
+        messageId,
+      );
+
+      this.sendSuccessResponse(messageId, result);
+    } catch (error) {
+      this.sendErrorResponse(messageId, error);
+    }
   }
 
   /**
@@ -342,6 +619,22 @@ class MCPUIAppsSdkAdapter {
       this.config.logger.debug('[MCPUI-Apps SDK Adapter] Globals updated');
       this.sendRenderData();
     });
+
+    // Apps SDK has no teardown request, so the page being hidden stands in for it
+    this.pageHideListener = () => this.sendTeardown('pagehide');
+    window.addEventListener('pagehide', this.pageHideListener);
+  }
+
+  /**
+   * Notify the widget that it is being torn down; sent at most once
+   */
+  private sendTeardown(reason: string): void {
+    if (this.tornDown) return;
+    this.tornDown = true;
+    this.dispatchMessageToIframe({
+      type: 'ui-lifecycle-teardown',
+      payload: { reason },
+    });
   }
 
   /**
@@ -391,14 +684,9 @@ class MCPUIAppsSdkAdapter {
    * Send error response
    */
   private sendErrorResponse(messageId: string, error: unknown): void {
-    const errorObj =
-      error instanceof Error
-        ? { message: error.message, name: error.name }
-        : { message: String(error) };
-
     this.dispatchMessageToIframe({
       type: 'ui-message-response',
-      payload: { messageId, error: errorObj },
+      payload: { messageId, error: toProtocolError(error) },
     });
   }
 
@@ -423,7 +711,9 @@ class MCPUIAppsSdkAdapter {
     return new Promise<T>((resolve, reject) => {
       const timeoutId = setTimeout(() => {
         this.pendingRequests.delete(requestId);
-        reject(new Error(`Request timed out after ${this.config.timeout}ms`));
+        reject(
+          createProtocolError('timeout', 'Request timed out after ' + this.config.timeout + 'ms'),
+        );
       }, this.config.timeout);
 
       this.pendingRequests.set(requestId, {
@@ -452,7 +742,7 @@ class MCPUIAppsSdkAdapter {
    * Generate a unique message ID
    */
   private generateMessageId(): string {
-    return `adapter-${Date.now()}-${++this.messageIdCounter}`;
+    return 'adapter-' + Date.now() + '-' + ++this.messageIdCounter;
   }
 }
 
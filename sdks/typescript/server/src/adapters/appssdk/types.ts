@@ -3,6 +3,7 @@
  */
 
 import type { UIActionResult } from '../../types.js';
+import type { AdapterLogger, AdapterLogLevel, DataProvider, IntentRoute } from '../types.js';
 
 /**
  * Additional MCP-UI Protocol Messages (Lifecycle & Control)
@@ -46,6 +47,14 @@ export interface MCPUIRenderDataMessage {
   };
 }
 
+export interface MCPUITeardownMessage {
+  type: 'ui-lifecycle-teardown';
+  messageId?: string;
+  payload: {
+    reason?: string;
+  };
+}
+
 export interface MCPUIMessageReceivedMessage {
   type: 'ui-message-received';
   messageId?: string;
@@ -82,6 +91,7 @@ export type MCPUIMessage =
   | MCPUIRequestDataMessage
   | MCPUIRequestRenderDataMessage
   | MCPUIRenderDataMessage
+  | MCPUITeardownMessage
   | MCPUIMessageReceivedMessage
   | MCPUIMessageResponseMessage;
 
@@ -97,6 +107,7 @@ export interface AppsSdkBridge {
   callTool(name: string, args: Record<string, unknown>): Promise<unknown>;
   sendFollowUpMessage(params: { prompt: string }): Promise<void>;
   requestDisplayMode(params: { mode: 'inline' | 'pip' | 'fullscreen' }): Promise<void>;
+  openExternal?(params: { href: string }): Promise<void> | void;
   maxHeight?: number;
   displayMode?: 'inline' | 'pip' | 'fullscreen';
   locale?: string;
@@ -126,7 +137,12 @@ export interface AppsSdkAdapterConfig {
   /**
    * Custom logger (defaults to console)
    */
-  logger?: Pick<Console, 'log' | 'warn' | 'error' | 'debug'>;
+  logger?: AdapterLogger;
+
+  /**
+   * Minimum level written to the logger (defaults to 'debug', everything)
+   */
+  logLevel?: AdapterLogLevel;
 
   /**
    * Origin to use when dispatching MessageEvents to the iframe (defaults to window.location.origin)
@@ -145,6 +161,18 @@ export interface AppsSdkAdapterConfig {
    * - 'ignore': Log and acknowledge but take no action
    */
   intentHandling?: 'prompt' | 'ignore';
+
+  /**
+   * Routing table from intent names (or '*') to declarative routes.
+   * A routed intent is handled by its route instead of intentHandling.
+   */
+  intentRoutes?: Record<string, IntentRoute>;
+
+  /**
+   * Data providers keyed by ui-request-data request type. Requests without a
+   * provider are answered with an error.
+   */
+  dataProviders?: Record<string, DataProvider>;
 }
 
 /**
@@ -1,2 +1,9 @@
 export * from './appssdk/index.js';
 export * from './mcp-apps/index.js';
+export type {
+  AdapterLogLevel,
+  DataProvider,
+  IntentRoute,
+  ProtocolError as AdapterProtocolError,
+  ProtocolErrorCode,
+} from './types.js';
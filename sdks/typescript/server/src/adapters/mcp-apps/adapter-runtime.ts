@@ -9,12 +9,24 @@
  * All runtime values (like LATEST_PROTOCOL_VERSION) must be defined locally to avoid
  * bundling the entire ext-apps package into the output.
  *
+ * The Go SDK embeds the compiled script in a raw string literal (scripts/generate-go-runtime.js),
+ * so this file must not use template literals.
+ *
  * @see https://github.com/modelcontextprotocol/ext-apps
  */
 
 // Import types from ext-apps for compile-time type checking only
 // These are erased during compilation and don't affect the bundled output
 import type { McpUiHostContext, McpUiInitializeResult } from '@modelcontextprotocol/ext-apps';
+import type {
+  AdapterLogger,
+  AdapterLogLevel,
+  DataProvider,
+  IntentRoute,
+  LeveledLogger,
+  ProtocolError,
+  ProtocolErrorCode,
+} from '../types.js';
 
 // ============================================================================
 // Protocol Constants (must match @modelcontextprotocol/ext-apps)
@@ -65,16 +77,170 @@ const METHODS = {
   MESSAGE: 'ui/message',
 } as const;
 
+// Numeric order of the log levels; a method is kept when its level is at or above the threshold
+const LOG_LEVELS: Record<AdapterLogLevel, number> = {
+  debug: 0,
+  info: 1,
+  warn: 2,
+  error: 3,
+  silent: 4,
+};
+
+/**
+ * Wrap a logger so that methods below the given level are no-ops
+ */
+function createLeveledLogger(logger: AdapterLogger, level?: AdapterLogLevel): LeveledLogger {
+  const threshold = LOG_LEVELS[level as AdapterLogLevel] ?? LOG_LEVELS.debug;
+  const noop = () => {};
+  const bind = (method: keyof LeveledLogger, minLevel: number) =>
+    threshold <= minLevel && typeof (logger as Partial<LeveledLogger>)[method] === 'function'
+      ? (logger as LeveledLogger)[method].bind(logger)
+      : noop;
+  return {
+    debug: bind('debug', LOG_LEVELS.debug),
+    log: bind('log', LOG_LEVELS.info),
+    info: bind('info', LOG_LEVELS.info),
+    warn: bind('warn', LOG_LEVELS.warn),
+    error: bind('error', LOG_LEVELS.error),
+  };
+}
+
+/**
+ * Render an intent route template, URL-encoding substituted values when encode is set
+ */
+function renderIntentTemplate(
+  template: string,
+  intent: string,
+  params: Record<string, unknown> | undefined,
+  encode: boolean,
+): string {
+  return template.replace(/\{\{\s*([$\w.-]+)\s*\}\}/g, (_match, key: string) => {
+    let value: unknown;
+    if (key === '$intent') {
+      value = intent;
+    } else if (key === '$params') {
+      value = params ?? {};
+    } else {
+      value = params?.[key];
+    }
+    if (value === undefined || value === null) {
+      value = '';
+    }
+    const text = typeof value === 'string' ? value : JSON.stringify(value);
+    return encode ? encodeURIComponent(text) : text;
+  });
+}
+
+/**
+ * Map intent or data request parameters to tool arguments; without a mapping the parameters
+ * are passed unchanged
+ */
+function mapParams(
+  mapping: Record<string, string> | undefined,
+  params: Record<string, unknown> | undefined,
+): Record<string, unknown> {
+  if (!mapping) {
+    return params ?? {};
+  }
+  const result: Record<string, unknown> = {};
+  for (const [target, source] of Object.entries(mapping)) {
+    if (params && source in params) {
+      result[target] = params[source];
+    }
+  }
+  return result;
+}
+
+/**
+ * Find the route for an intent, falling back to the '*' route
+ */
+function resolveIntentRoute(
+  routes: Record<string, IntentRoute> | null,
+  intent: string,
+): IntentRoute | null {
+  if (!routes) {
+    return null;
+  }
+  return routes[intent] ?? routes['*'] ?? null;
+}
+
 // ============================================================================
 // Local Types (for runtime - mirrors ext-apps types)
 // ============================================================================
 
+/** JSON-RPC error code of each protocol error code; must match the Go SDK's mapping */
+const PROTOCOL_ERROR_CODES: Record<ProtocolErrorCode, number> = {
+  unsupported: -32601,
+  timeout: -32001,
+  denied: -32002,
+  'invalid-params': -32602,
+  'host-error': -32603,
+};
+
+/** Error carrying a protocol error code */
+type CodedError = Error & { code?: string; data?: unknown };
+
+/**
+ * Create an Error that toProtocolError reports with the given code
+ */
+function createProtocolError(code: ProtocolErrorCode, message: string, data?: unknown): CodedError {
+  const error: CodedError = new Error(message);
+  error.code = code;
+  if (data !== undefined) {
+    error.data = data;
+  }
+  return error;
+}
+
+/**
+ * Convert a thrown value to the error sent to the widget; errors without a known code are
+ * reported as host errors
+ */
+function toProtocolError(error: unknown): ProtocolError {
+  const coded = error as CodedError | null;
+  if (coded && typeof coded.code === 'string' && Object.hasOwn(PROTOCOL_ERROR_CODES, coded.code)) {
+    const result: ProtocolError = { code: coded.code as ProtocolErrorCode, message: coded.message };
+    if (coded.data !== undefined) {
+      result.data = coded.data;
+    }
+    return result;
+  }
+  return { code: 'host-error', message: error instanceof Error ? error.message : String(error) };
+}
+
+/**
+ * Convert a JSON-RPC error from the host to the error sent to the widget
+ */
+function protocolErrorFromJsonRpc(
+  error: { code?: number; message?: string; data?: unknown } | undefined,
+): ProtocolError {
+  const code =
+    (Object.keys(PROTOCOL_ERROR_CODES) as ProtocolErrorCode[]).find(
+      (key) => PROTOCOL_ERROR_CODES[key] === error?.code,
+    ) ?? 'host-error';
+  return toProtocolError(createProtocolError(code, error?.message ?? 'Unknown error', error?.data));
+}
+
 /** Configuration for the MCP Apps adapter */
 interface McpAppsAdapterConfig {
-  logger?: Pick<Console, 'log' | 'warn' | 'error' | 'debug'>;
+  logger?: AdapterLogger;
+  logLevel?: AdapterLogLevel;
   timeout?: number;
+  appInfo?: { name: string; version: string };
+  appCapabilities?: Record<string, unknown>;
+  intentRoutes?: Record<string, IntentRoute>;
+  dataProviders?: Record<string, DataProvider>;
 }
 
+/** Adapter configuration with defaults applied */
+type ResolvedConfig = Required<
+  Omit<McpAppsAdapterConfig, 'logger' | 'logLevel' | 'intentRoutes' | 'dataProviders'>
+> & {
+  logger: LeveledLogger;
+  intentRoutes: Record<string, IntentRoute> | null;
+  dataProviders: Record<string, DataProvider> | null;
+};
+
 /** Pending request tracking */
 interface PendingRequest<T = unknown> {
   messageId: string;
@@ -101,7 +267,7 @@ interface UIActionResult {
 type ParentPostMessage = Window['postMessage'];
 
 class McpAppsAdapter {
-  private config: Required<McpAppsAdapterConfig>;
+  private config: ResolvedConfig;
   private pendingRequests: Map<string, PendingRequest<unknown>> = new Map();
   private messageIdCounter = 0;
   private originalPostMessage: ParentPostMessage | null = null;
@@ -123,8 +289,12 @@ class McpAppsAdapter {
 
   constructor(config: McpAppsAdapterConfig = {}) {
     this.config = {
-      logger: config.logger || console,
+      logger: createLeveledLogger(config.logger || console, config.logLevel),
       timeout: config.timeout || 30000,
+      appInfo: config.appInfo || { name: 'mcp-ui-adapter', version: '1.0.0' },
+      appCapabilities: config.appCapabilities || {},
+      intentRoutes: config.intentRoutes || null,
+      dataProviders: config.dataProviders || null,
     };
   }
 
@@ -230,11 +400,8 @@ class McpAppsAdapter {
     // Send ui/initialize request
     this.config.logger.log('[MCP Apps Adapter] Sending ui/initialize request with id:', jsonRpcId);
     this.sendJsonRpcRequest(jsonRpcId, METHODS.INITIALIZE, {
-      appInfo: {
-        name: 'mcp-ui-adapter',
-        version: '1.0.0',
-      },
-      appCapabilities: {},
+      appInfo: this.config.appInfo,
+      appCapabilities: this.config.appCapabilities,
       protocolVersion: LATEST_PROTOCOL_VERSION,
     });
     this.config.logger.log('[MCP Apps Adapter] ui/initialize request sent');
@@ -357,6 +524,12 @@ class McpAppsAdapter {
         case METHODS.TOOL_INPUT_PARTIAL:
           // Update stored render data with partial input
           this.currentRenderData.toolInput = data.params?.arguments;
+          this.dispatchMessageToIframe({
+            type: 'ui-lifecycle-tool-input-partial',
+            payload: {
+              arguments: data.params?.arguments ?? {},
+            },
+          });
           this.sendRenderData();
           break;
 
@@ -433,7 +606,7 @@ class McpAppsAdapter {
           payload: {
             messageId: pendingRequest.messageId,
             response: data.result,
-            error: data.error,
+            error: data.error ? protocolErrorFromJsonRpc(data.error) : undefined,
           },
         });
       }
@@ -480,7 +653,7 @@ class McpAppsAdapter {
               this.dispatchMessageToIframe({
                 type: 'ui-message-response',
                 messageId,
-                payload: { messageId, error: 'Timeout' },
+                payload: { messageId, error: this.timeoutError() },
               });
             }, this.config.timeout),
           });
@@ -526,7 +699,7 @@ class McpAppsAdapter {
               this.dispatchMessageToIframe({
                 type: 'ui-message-response',
                 messageId,
-                payload: { messageId, error: 'Timeout' },
+                payload: { messageId, error: this.timeoutError() },
               });
             }, this.config.timeout),
           });
@@ -550,7 +723,7 @@ class McpAppsAdapter {
               this.dispatchMessageToIframe({
                 type: 'ui-message-response',
                 messageId,
-                payload: { messageId, error: 'Timeout' },
+                payload: { messageId, error: this.timeoutError() },
               });
             }, this.config.timeout),
           });
@@ -576,13 +749,49 @@ class McpAppsAdapter {
           break;
         }
 
+        // MCP-UI data request -> MCP Apps tools/call of the registered data provider
+        case 'ui-request-data': {
+          const { requestType, params } = ((message as MCPUIMessage).payload || {}) as {
+            requestType: string;
+            params?: Record<string, unknown>;
+          };
+          const provider = this.config.dataProviders?.[requestType];
+          if (!provider) {
+            this.dispatchMessageToIframe({
+              type: 'ui-message-response',
+              messageId,
+              payload: {
+                messageId,
+                error: toProtocolError(
+                  createProtocolError(
+                    'unsupported',
+                    'No data provider registered for request type: ' + requestType,
+                  ),
+                ),
+              },
+            });
+            break;
+          }
+          this.sendPendingRequest(messageId, 'request-data', METHODS.TOOLS_CALL, {
+            name: provider.toolName,
+            arguments: mapParams(provider.paramMapping, params),
+          });
+          break;
+        }
+
         // MCP-UI intent -> Currently no direct equivalent in MCP Apps
         // We translate it to a ui/message with the intent description
         case 'intent': {
           const { intent, params } = (message as UIActionResult).payload as {
             intent: string;
-            params: unknown;
+            params: Record<string, unknown> | undefined;
           };
+          // A route for the intent takes precedence over the default prompt
+          const route = resolveIntentRoute(this.config.intentRoutes, intent);
+          if (route) {
+            this.handleRoutedIntent(messageId, route, intent, params);
+            break;
+          }
           const jsonRpcId = this.generateJsonRpcId();
 
           this.pendingRequests.set(String(jsonRpcId), {
@@ -595,7 +804,7 @@ class McpAppsAdapter {
               this.dispatchMessageToIframe({
                 type: 'ui-message-response',
                 messageId,
-                payload: { messageId, error: 'Timeout' },
+                payload: { messageId, error: this.timeoutError() },
               });
             }, this.config.timeout),
           });
@@ -604,7 +813,10 @@ class McpAppsAdapter {
           this.sendJsonRpcRequest(jsonRpcId, METHODS.MESSAGE, {
             role: 'user',
             content: [
-              { type: 'text', text: `Intent: ${intent}. Parameters: ${JSON.stringify(params)}` },
+              {
+                type: 'text',
+                text: 'Intent: ' + intent + '. Parameters: ' + JSON.stringify(params),
+              },
             ],
           });
           break;
@@ -615,11 +827,89 @@ class McpAppsAdapter {
       this.dispatchMessageToIframe({
         type: 'ui-message-response',
         messageId,
-        payload: { messageId, error },
+        payload: { messageId, error: toProtocolError(error) },
       });
     }
   }
 
+  /**
+   * Handle an intent with a declared route by sending the matching host request
+   */
+  private handleRoutedIntent(
+    messageId: string,
+    route: IntentRoute,
+    intent: string,
+    params: Record<string, unknown> | undefined,
+  ): void {
+    switch (route.action) {
+      case 'tool':
+        this.sendPendingRequest(messageId, 'intent', METHODS.TOOLS_CALL, {
+          name: route.toolName,
+          arguments: mapParams(route.paramMapping, params),
+        });
+        break;
+      case 'prompt':
+        this.sendPendingRequest(messageId, 'intent', METHODS.MESSAGE, {
+          role: 'user',
+          content: [
+            { type: 'text', text: renderIntentTemplate(route.template, intent, params, false) },
+          ],
+        });
+        break;
+      case 'link':
+        this.sendPendingRequest(messageId, 'intent', METHODS.OPEN_LINK, {
+          url: renderIntentTemplate(route.template, intent, params, true),
+        });
+        break;
+      case 'drop':
+        this.config.logger.log('[MCP Apps Adapter] Intent dropped:', intent);
+        this.dispatchMessageToIframe({
+          type: 'ui-message-response',
+          messageId,
+          payload: { messageId, response: { ignored: true } },
+        });
+        break;
+      default:
+        throw new Error('Unknown intent route action: ' + (route as IntentRoute).action);
+    }
+  }
+
+  /**
+   * Send a JSON-RPC request to the host and answer the app when its response arrives
+   */
+  private sendPendingRequest(
+    messageId: string,
+    type: string,
+    method: string,
+    params: Record<string, unknown>,
+  ): void {
+    const jsonRpcId = this.generateJsonRpcId();
+    this.pendingRequests.set(String(jsonRpcId), {
+      messageId,
+      type,
+      resolve: () => {},
+      reject: () => {},
+      timeoutId: setTimeout(() => {
+        this.pendingRequests.delete(String(jsonRpcId));
+        this.dispatchMessageToIframe({
+          type: 'ui-message-response',
+          messageId,
+          payload: { messageId, error: this.timeoutError() },
+        });
+      }, this.config.timeout),
+    });
+    this.sendJsonRpcRequest(jsonRpcId, method, params);
+  }
+
+  /**
+   * Error sent to the widget when the host does not answer a request in time
+   */
+  private timeoutError(): ProtocolError {
+    return toProtocolError(
+      createProtocolError('timeout', 'Request timed out after ' + this.config.timeout + 'ms'),
+    );
+  }
+
   /**
    * Send current render data to the MCP-UI app
    * This mirrors the Apps SDK adapter's sendRenderData method
@@ -690,7 +980,7 @@ class McpAppsAdapter {
   }
 
   private generateMessageId(): string {
-    return `adapter-${Date.now()}-${++this.messageIdCounter}`;
+    return 'adapter-' + Date.now() + '-' + ++this.messageIdCounter;
   }
 
   private generateJsonRpcId(): number {
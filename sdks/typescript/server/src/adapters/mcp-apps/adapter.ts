@@ -6,6 +6,11 @@ export function getMcpAppsAdapterScript(config?: McpAppsAdapterConfig): string {
   const serializableConfig = config
     ? {
         timeout: config.timeout,
+        logLevel: config.logLevel,
+        appInfo: config.appInfo,
+        appCapabilities: config.appCapabilities,
+        intentRoutes: config.intentRoutes,
+        dataProviders: config.dataProviders,
       }
     : {};
   const configJson = JSON.stringify(serializableConfig);
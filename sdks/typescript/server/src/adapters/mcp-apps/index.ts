@@ -1,2 +1,6 @@
 export * from './adapter.js';
-export type { McpAppsAdapterConfig, MCPUIMessage as McpAppsMCPUIMessage } from './types.js';
+export type {
+  McpAppsAdapterConfig,
+  AppInfo as McpAppsAppInfo,
+  MCPUIMessage as McpAppsMCPUIMessage,
+} from './types.js';
@@ -1,4 +1,5 @@
 import type { UIActionResult } from '../../types.js';
+import type { AdapterLogger, AdapterLogLevel, DataProvider, IntentRoute } from '../types.js';
 
 /**
  * MCP-UI Protocol Messages
@@ -46,6 +47,30 @@ export interface MCPUIRenderDataMessage {
   };
 }
 
+export interface MCPUIToolInputPartialMessage {
+  type: 'ui-lifecycle-tool-input-partial';
+  messageId?: string;
+  payload: {
+    arguments: Record<string, unknown>;
+  };
+}
+
+export interface MCPUIToolCancelledMessage {
+  type: 'ui-lifecycle-tool-cancelled';
+  messageId?: string;
+  payload: {
+    reason?: string;
+  };
+}
+
+export interface MCPUITeardownMessage {
+  type: 'ui-lifecycle-teardown';
+  messageId?: string;
+  payload: {
+    reason?: string;
+  };
+}
+
 export interface MCPUIMessageReceivedMessage {
   type: 'ui-message-received';
   messageId?: string;
@@ -82,6 +107,9 @@ export type MCPUIMessage =
   | MCPUIRequestDataMessage
   | MCPUIRequestRenderDataMessage
   | MCPUIRenderDataMessage
+  | MCPUIToolInputPartialMessage
+  | MCPUIToolCancelledMessage
+  | MCPUITeardownMessage
   | MCPUIMessageReceivedMessage
   | MCPUIMessageResponseMessage;
 
@@ -89,10 +117,51 @@ export type MCPUIMessage =
  * MCP Apps Adapter Configuration
  */
 export interface McpAppsAdapterConfig {
+  /**
+   * Custom logger (defaults to console)
+   */
+  logger?: AdapterLogger;
+
+  /**
+   * Minimum level written to the logger (defaults to 'debug', everything)
+   */
+  logLevel?: AdapterLogLevel;
+
   /**
    * Timeout in milliseconds for async operations (defaults to 30000)
    */
   timeout?: number;
+
+  /**
+   * App name and version sent to the host in ui/initialize
+   * (defaults to { name: 'mcp-ui-adapter', version: '1.0.0' })
+   */
+  appInfo?: AppInfo;
+
+  /**
+   * Capabilities announced to the host in ui/initialize (defaults to {})
+   */
+  appCapabilities?: Record<string, unknown>;
+
+  /**
+   * Routing table from intent names (or '*') to declarative routes.
+   * Unrouted intents are sent to the host as a ui/message prompt.
+   */
+  intentRoutes?: Record<string, IntentRoute>;
+
+  /**
+   * Data providers keyed by ui-request-data request type, answered with a
+   * tools/call to the host. Requests without a provider are answered with an error.
+   */
+  dataProviders?: Record<string, DataProvider>;
+}
+
+/**
+ * Identifies the app to the host in ui/initialize
+ */
+export interface AppInfo {
+  name: string;
+  version: string;
 }
 
 export interface PendingRequest<T = unknown> {
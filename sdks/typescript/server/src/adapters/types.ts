@@ -0,0 +1,80 @@
+/**
+ * Type definitions shared by the adapters
+ */
+
+/**
+ * Minimum level the adapter runtime writes to its logger
+ * - 'debug': everything (default)
+ * - 'info': log, info, warn and error
+ * - 'warn': warn and error
+ * - 'error': errors only
+ * - 'silent': nothing
+ */
+export type AdapterLogLevel = 'debug' | 'info' | 'warn' | 'error' | 'silent';
+
+/**
+ * Logger used by the adapter runtime
+ */
+export type AdapterLogger = Pick<Console, 'log' | 'warn' | 'error' | 'debug'>;
+
+/**
+ * Logger returned by createLeveledLogger, with methods below the level replaced by no-ops
+ */
+export type LeveledLogger = Pick<Console, 'debug' | 'log' | 'info' | 'warn' | 'error'>;
+
+/**
+ * Declares how the adapter handles one intent name. Routing tables are keyed by
+ * intent name, with '*' matching any intent without a route of its own.
+ *
+ * Templates substitute {{name}} with the intent parameter "name" (strings as-is,
+ * other values as JSON), {{$intent}} with the intent name and {{$params}} with
+ * all parameters as JSON. Link templates URL-encode substituted values.
+ */
+export type IntentRoute =
+  | {
+      /** Call a tool with parameters mapped from the intent */
+      action: 'tool';
+      toolName: string;
+      /** Maps tool argument names to intent parameter names; omit to pass them unchanged */
+      paramMapping?: Record<string, string>;
+    }
+  | {
+      /** Send a follow-up prompt or open a URL rendered from the template */
+      action: 'prompt' | 'link';
+      template: string;
+    }
+  | {
+      /** Acknowledge the intent without doing anything */
+      action: 'drop';
+    };
+
+/**
+ * Answers ui-request-data messages of one request type by calling a tool through the host.
+ * The tool result is returned to the widget in a ui-message-response.
+ */
+export interface DataProvider {
+  /** The tool that produces the data */
+  toolName: string;
+  /** Maps tool argument names to request parameter names; omit to pass them unchanged */
+  paramMapping?: Record<string, string>;
+}
+
+/**
+ * Standard codes of the errors returned to widgets in ui-message-response messages
+ */
+export type ProtocolErrorCode =
+  | 'unsupported'
+  | 'timeout'
+  | 'denied'
+  | 'invalid-params'
+  | 'host-error';
+
+/**
+ * Error returned to widgets in the error field of a ui-message-response
+ */
+export interface ProtocolError {
+  code: ProtocolErrorCode;
+  message: string;
+  /** Optional details, e.g. the data of the host's JSON-RPC error */
+  data?: unknown;
+}
@@ -1,5 +1,6 @@
 import type { EmbeddedResource, Resource } from '@modelcontextprotocol/sdk/types.js';
 import type { McpAppsAdapterConfig } from './adapters/mcp-apps/types.js';
+import type { AdapterLogLevel, DataProvider, IntentRoute } from './adapters/types.js';
 
 // Re-export constants from the official ext-apps SDK for convenience
 // This ensures we stay in sync with the MCP Apps specification
@@ -117,6 +118,23 @@ export interface AppsSdkAdapterOptions {
      * Origin to use when dispatching MessageEvents to the iframe (defaults to window.location.origin)
      */
     hostOrigin?: string;
+
+    /**
+     * Minimum level the adapter writes to the console (defaults to 'debug', everything)
+     */
+    logLevel?: AdapterLogLevel;
+
+    /**
+     * Routing table from intent names (or '*') to declarative routes.
+     * A routed intent is handled by its route instead of intentHandling.
+     */
+    intentRoutes?: Record<string, IntentRoute>;
+
+    /**
+     * Data providers keyed by ui-request-data request type. Requests without a
+     * provider are answered with an error.
+     */
+    dataProviders?: Record<string, DataProvider>;
   };
 
   /**